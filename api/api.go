@@ -0,0 +1,10 @@
+// Package api holds the OpenAPI document of the URL shortener API. The Go client
+// generated from it is in the client package.
+package api
+
+import _ "embed"
+
+// Spec is the OpenAPI 3 document served at /openapi.json
+//
+//go:embed openapi.json
+var Spec []byte
@@ -0,0 +1,5 @@
+// Package client is a Go client for the URL shortener API, generated from api/openapi.json.
+// Run go generate after changing the document.
+package client
+
+//go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen@v2.4.1 -config oapi-codegen.yaml ../openapi.json
@@ -0,0 +1,5 @@
+// Package shortenerpb holds the gRPC API of the URL shortener, generated from shortener.proto.
+// Run go generate with protoc, protoc-gen-go and protoc-gen-go-grpc installed after changing it.
+package shortenerpb
+
+//go:generate protoc -I . --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative shortener.proto
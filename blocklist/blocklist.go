@@ -0,0 +1,243 @@
+package blocklist
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"URL_SHORTENER/models"
+)
+
+const (
+	minPrefixBytes = 4
+	maxPrefixBytes = sha256.Size
+	// maxHostSuffixes and maxPathPrefixes bound the url expressions hashed, as in Safe Browsing lookups
+	maxHostSuffixes = 5
+	maxPathPrefixes = 4
+)
+
+type Config struct {
+	// DomainFiles list blocked domains one per line, or in the hosts file format.
+	// The subdomains of a blocked domain are blocked too.
+	DomainFiles []string
+	// HashPrefixFiles list hex encoded SHA-256 prefixes of url expressions like
+	// evil.example/login/, 4 to 32 bytes one per line
+	HashPrefixFiles []string
+}
+
+// Blocklist screens urls against domain and url hash prefix lists loaded from files.
+// Lines starting with # are comments. The files are read again by Reload when they change.
+type Blocklist struct {
+	config   Config
+	mu       sync.RWMutex
+	domains  map[string]bool
+	prefixes map[int]map[string]bool // keyed by the prefix length in bytes
+	modTimes map[string]time.Time
+}
+
+// Load reads the lists named by the config
+func Load(config Config) (*Blocklist, error) {
+	b := &Blocklist{config: config}
+	if _, err := b.Reload(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Reload reads the lists again if any of the files changed since they were last read
+// and reports whether it did. The lists in use are kept when a file can't be read.
+func (b *Blocklist) Reload() (bool, error) {
+	modTimes := make(map[string]time.Time)
+	changed := b.modTimes == nil
+	for _, path := range append(append([]string{}, b.config.DomainFiles...), b.config.HashPrefixFiles...) {
+		info, err := os.Stat(path)
+		if err != nil {
+			return false, err
+		}
+		modTimes[path] = info.ModTime()
+		changed = changed || !info.ModTime().Equal(b.modTimes[path])
+	}
+	if !changed {
+		return false, nil
+	}
+
+	domains := make(map[string]bool)
+	for _, path := range b.config.DomainFiles {
+		err := readLines(path, func(line string) error {
+			fields := strings.Fields(line)
+			// Hosts files map the domain to an unroutable address
+			if len(fields) == 2 && net.ParseIP(fields[0]) != nil {
+				fields = fields[1:]
+			}
+			if len(fields) != 1 {
+				return fmt.Errorf("expected a domain")
+			}
+			domains[normalizeHost(fields[0])] = true
+			return nil
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+	prefixes := make(map[int]map[string]bool)
+	for _, path := range b.config.HashPrefixFiles {
+		err := readLines(path, func(line string) error {
+			prefix, err := hex.DecodeString(line)
+			if err != nil || len(prefix) < minPrefixBytes || len(prefix) > maxPrefixBytes {
+				return fmt.Errorf("expected %d to %d hex encoded bytes", minPrefixBytes, maxPrefixBytes)
+			}
+			if prefixes[len(prefix)] == nil {
+				prefixes[len(prefix)] = make(map[string]bool)
+			}
+			prefixes[len(prefix)][string(prefix)] = true
+			return nil
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.domains = domains
+	b.prefixes = prefixes
+	b.modTimes = modTimes
+	return true, nil
+}
+
+// readLines calls fn with every line of the file that isn't blank or a comment
+func readLines(path string, fn func(line string) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if err = fn(text); err != nil {
+			return fmt.Errorf("%s line %d: %w", path, line, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// Check reports why the url is blocked, if it is
+func (b *Blocklist) Check(rawUrl string) (string, bool) {
+	parsed, err := url.Parse(rawUrl)
+	if err != nil || parsed.Hostname() == "" {
+		return "", false
+	}
+	host := normalizeHost(parsed.Hostname())
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, domain := range parentDomains(host) {
+		if b.domains[domain] {
+			return fmt.Sprintf("the domain %s is on the blocklist", domain), true
+		}
+	}
+	if len(b.prefixes) == 0 {
+		return "", false
+	}
+	for _, expression := range expressions(host, parsed) {
+		hash := sha256.Sum256([]byte(expression))
+		for length, prefixes := range b.prefixes {
+			if prefixes[string(hash[:length])] {
+				return fmt.Sprintf("the url %s is on the blocklist", expression), true
+			}
+		}
+	}
+	return "", false
+}
+
+// CheckUrl checks every destination of the url, its original url, targeting rules and variants
+func (b *Blocklist) CheckUrl(url *models.Url) (string, bool) {
+	destinations := []string{url.OriginalUrl}
+	for _, rule := range url.Rules {
+		destinations = append(destinations, rule.Destination)
+	}
+	for _, variant := range url.Variants {
+		destinations = append(destinations, variant.Destination)
+	}
+	for _, destination := range destinations {
+		if reason, blocked := b.Check(destination); blocked {
+			return reason, true
+		}
+	}
+	return "", false
+}
+
+func normalizeHost(host string) string {
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
+
+// parentDomains returns the host and every domain it is a subdomain of
+func parentDomains(host string) []string {
+	domains := []string{host}
+	if net.ParseIP(host) != nil {
+		return domains
+	}
+	for i := strings.IndexByte(host, '.'); i >= 0; i = strings.IndexByte(host, '.') {
+		host = host[i+1:]
+		domains = append(domains, host)
+	}
+	return domains
+}
+
+// expressions returns the host suffix and path prefix combinations hashed for the url: the exact
+// host and up to four parent domains short of the top level domain, with the full path and query,
+// the full path, and up to four leading directories
+func expressions(host string, parsed *url.URL) []string {
+	hosts := []string{host}
+	if net.ParseIP(host) == nil {
+		labels := strings.Split(host, ".")
+		start := len(labels) - maxHostSuffixes
+		if start < 1 {
+			start = 1
+		}
+		for i := start; i < len(labels)-1; i++ {
+			hosts = append(hosts, strings.Join(labels[i:], "."))
+		}
+	}
+
+	path := parsed.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	var paths []string
+	if parsed.RawQuery != "" {
+		paths = append(paths, path+"?"+parsed.RawQuery)
+	}
+	paths = append(paths, path)
+	prefix := "/"
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i := 0; i < len(segments) && i < maxPathPrefixes; i++ {
+		if prefix != path {
+			paths = append(paths, prefix)
+		}
+		prefix += segments[i] + "/"
+	}
+
+	seen := make(map[string]bool)
+	var expressions []string
+	for _, h := range hosts {
+		for _, p := range paths {
+			if expression := h + p; !seen[expression] {
+				seen[expression] = true
+				expressions = append(expressions, expression)
+			}
+		}
+	}
+	return expressions
+}
@@ -0,0 +1,179 @@
+package blocklist
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"URL_SHORTENER/models"
+	"URL_SHORTENER/storage"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStore keeps the urls of the default domain and records the blocked ones
+type fakeStore struct {
+	urls    []*models.Url
+	blocked map[string]string
+}
+
+func (s *fakeStore) ListDomains() ([]models.Domain, error) {
+	return []models.Domain{}, nil
+}
+
+func (s *fakeStore) ExportUrls(filter *storage.UrlFilter, fn func(url *models.Url) error) error {
+	for _, url := range s.urls {
+		if url.Domain != filter.Domain {
+			continue
+		}
+		if err := fn(url); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *fakeStore) SetUrlStatus(domain string, shortUrl string, status string, reason string) error {
+	for _, url := range s.urls {
+		if url.Domain == domain && url.ShortUrl == shortUrl {
+			url.Status = status
+			url.StatusReason = reason
+			s.blocked[shortUrl] = reason
+			return nil
+		}
+	}
+	return errors.New(storage.ErrShortURLDoesNotExist)
+}
+
+func writeList(t *testing.T, path string, content string, modTime time.Time) {
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func hashPrefix(expression string, length int) string {
+	hash := sha256.Sum256([]byte(expression))
+	return hex.EncodeToString(hash[:length])
+}
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	domains := filepath.Join(dir, "domains.txt")
+	hosts := filepath.Join(dir, "hosts")
+	prefixes := filepath.Join(dir, "prefixes.txt")
+	now := time.Now()
+	writeList(t, domains, "# Phishing\nevil.example\n\nMalware.Example.\n", now)
+	writeList(t, hosts, "0.0.0.0 tracker.example\n127.0.0.1\tads.example\n", now)
+	writeList(t, prefixes, hashPrefix("bad.example/login/", 4)+"\n"+hashPrefix("files.example/payload.exe", 32)+"\n", now)
+
+	list, err := Load(Config{DomainFiles: []string{domains, hosts}, HashPrefixFiles: []string{prefixes}})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		url     string
+		blocked bool
+	}{
+		{"Listed domain", "https://evil.example/", true},
+		{"Subdomain of listed domain", "http://www.login.evil.example/path", true},
+		{"Domain names are case insensitive", "https://MALWARE.example", true},
+		{"Hosts file entry", "https://tracker.example/pixel.gif", true},
+		{"Hosts file entry separated by a tab", "https://ads.example", true},
+		{"Similar domain", "https://notevil.example", false},
+		{"Parent of listed domain", "https://example", false},
+		{"Hash prefix of a directory", "https://bad.example/login/form.php?next=1", true},
+		{"Hash prefix of a subdomain", "https://a.b.bad.example/login/", true},
+		{"Other path on the same host", "https://bad.example/about", false},
+		{"Full hash of a path", "https://files.example/payload.exe", true},
+		{"Full hash of another path", "https://files.example/readme.txt", false},
+		{"Invalid url", "not a url", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, blocked := list.Check(tt.url)
+			require.Equal(t, tt.blocked, blocked, reason)
+			if blocked {
+				require.NotEmpty(t, reason)
+			}
+		})
+	}
+
+	t.Run("Every destination of a url is checked", func(t *testing.T) {
+		url := &models.Url{
+			OriginalUrl: "https://example.com",
+			Variants:    []models.Variant{{Name: "b", Destination: "https://evil.example/b", Weight: 1}},
+		}
+		reason, blocked := list.CheckUrl(url)
+		require.True(t, blocked)
+		require.Contains(t, reason, "evil.example")
+
+		url.Variants = nil
+		url.Rules = []models.TargetingRule{{Destination: "https://tracker.example"}}
+		_, blocked = list.CheckUrl(url)
+		require.True(t, blocked)
+	})
+}
+
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	domains := filepath.Join(dir, "domains.txt")
+	modTime := time.Now().Add(-time.Hour)
+	writeList(t, domains, "evil.example\n", modTime)
+
+	_, err := Load(Config{DomainFiles: []string{filepath.Join(dir, "missing.txt")}})
+	require.Error(t, err)
+
+	list, err := Load(Config{DomainFiles: []string{domains}})
+	require.NoError(t, err)
+
+	changed, err := list.Reload()
+	require.NoError(t, err)
+	require.False(t, changed)
+
+	writeList(t, domains, "other.example\n", modTime.Add(time.Minute))
+	changed, err = list.Reload()
+	require.NoError(t, err)
+	require.True(t, changed)
+	_, blocked := list.Check("https://evil.example")
+	require.False(t, blocked)
+	_, blocked = list.Check("https://other.example")
+	require.True(t, blocked)
+
+	// A broken list doesn't replace the one in use
+	writeList(t, domains, "other.example extra words\n", modTime.Add(2*time.Minute))
+	_, err = list.Reload()
+	require.ErrorContains(t, err, "line 1")
+	_, blocked = list.Check("https://other.example")
+	require.True(t, blocked)
+}
+
+func TestRescan(t *testing.T) {
+	dir := t.TempDir()
+	domains := filepath.Join(dir, "domains.txt")
+	writeList(t, domains, "evil.example\n", time.Now())
+	list, err := Load(Config{DomainFiles: []string{domains}})
+	require.NoError(t, err)
+
+	store := &fakeStore{
+		urls: []*models.Url{
+			{ShortUrl: "good", OriginalUrl: "https://example.com"},
+			{ShortUrl: "bad", OriginalUrl: "https://www.evil.example"},
+			{ShortUrl: "variant", OriginalUrl: "https://example.com", Variants: []models.Variant{{Name: "b", Destination: "https://evil.example", Weight: 1}}},
+		},
+		blocked: make(map[string]string),
+	}
+	scanner := NewScanner(list, store, time.Hour, time.Hour)
+	blocked, err := scanner.Rescan()
+	require.NoError(t, err)
+	require.Equal(t, 2, blocked)
+	require.Contains(t, store.blocked, "bad")
+	require.Contains(t, store.blocked, "variant")
+
+	// Urls that are blocked already are skipped
+	blocked, err = scanner.Rescan()
+	require.NoError(t, err)
+	require.Equal(t, 0, blocked)
+}
@@ -0,0 +1,130 @@
+package blocklist
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"URL_SHORTENER/models"
+	"URL_SHORTENER/storage"
+)
+
+// UrlStore is where the scanner finds the urls and blocks them
+type UrlStore interface {
+	ListDomains() ([]models.Domain, error)
+	ExportUrls(filter *storage.UrlFilter, fn func(url *models.Url) error) error
+	SetUrlStatus(domain string, shortUrl string, status string, reason string) error
+}
+
+// Scanner reloads the blocklist when its files change and blocks the stored urls it matches.
+// The urls are scanned again after every reload and every RescanInterval.
+type Scanner struct {
+	list           *Blocklist
+	store          UrlStore
+	reloadInterval time.Duration
+	rescanInterval time.Duration
+	stop           chan struct{}
+	stopOnce       sync.Once
+	wg             sync.WaitGroup
+}
+
+func NewScanner(list *Blocklist, store UrlStore, reloadInterval time.Duration, rescanInterval time.Duration) *Scanner {
+	return &Scanner{
+		list:           list,
+		store:          store,
+		reloadInterval: reloadInterval,
+		rescanInterval: rescanInterval,
+		stop:           make(chan struct{}),
+	}
+}
+
+// Start scans the urls once and then keeps watching in the background
+func (s *Scanner) Start() {
+	s.wg.Add(1)
+	go s.run()
+}
+
+// Stop waits for a running scan to finish
+func (s *Scanner) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+	s.wg.Wait()
+}
+
+func (s *Scanner) run() {
+	defer s.wg.Done()
+	reload := time.NewTicker(s.reloadInterval)
+	defer reload.Stop()
+	rescan := time.NewTicker(s.rescanInterval)
+	defer rescan.Stop()
+	s.logRescan()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-reload.C:
+			changed, err := s.list.Reload()
+			if err != nil {
+				log.Printf("blocklist reload failed, keeping the current lists: %v", err)
+			} else if changed {
+				s.logRescan()
+			}
+		case <-rescan.C:
+			s.logRescan()
+		}
+	}
+}
+
+func (s *Scanner) logRescan() {
+	blocked, err := s.Rescan()
+	if err != nil {
+		log.Printf("blocklist rescan failed: %v", err)
+	}
+	if blocked > 0 {
+		log.Printf("blocklist rescan blocked %d urls", blocked)
+	}
+}
+
+// Rescan checks the urls of every domain that aren't blocked yet and blocks those the
+// blocklist matches. It returns the number of urls blocked.
+func (s *Scanner) Rescan() (int, error) {
+	domains, err := s.store.ListDomains()
+	if err != nil {
+		return 0, err
+	}
+	names := []string{""}
+	for _, domain := range domains {
+		names = append(names, domain.Name)
+	}
+	type match struct {
+		shortUrl string
+		reason   string
+	}
+	blocked := 0
+	for _, name := range names {
+		// The urls are blocked once read, the store may not be used while they are
+		var matches []match
+		err = s.store.ExportUrls(&storage.UrlFilter{Domain: name}, func(url *models.Url) error {
+			if url.Status == models.StatusBlocked {
+				return nil
+			}
+			if reason, ok := s.list.CheckUrl(url); ok {
+				matches = append(matches, match{url.ShortUrl, reason})
+			}
+			return nil
+		})
+		if err != nil {
+			return blocked, err
+		}
+		for _, m := range matches {
+			if err = s.store.SetUrlStatus(name, m.shortUrl, models.StatusBlocked, m.reason); err != nil && err.Error() != storage.ErrShortURLDoesNotExist {
+				return blocked, err
+			}
+			if err == nil {
+				blocked++
+			}
+		}
+	}
+	return blocked, nil
+}
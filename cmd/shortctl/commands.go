@@ -0,0 +1,392 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"URL_SHORTENER/api/client"
+)
+
+// stringList is a repeatable string flag
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// parseArgs parses the flags, which may be given before or after the positional
+// arguments, and checks that there are as many positional arguments as names
+func parseArgs(flags *flag.FlagSet, args []string, names ...string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, &usageError{err.Error()}
+		}
+		if flags.NArg() == 0 {
+			break
+		}
+		positional = append(positional, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+	if len(positional) != len(names) {
+		return nil, &usageError{fmt.Sprintf("usage: shortctl %s [flags] %s", flags.Name(), strings.Join(names, " "))}
+	}
+	return positional, nil
+}
+
+func newFlagSet(cli *cli, name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(cli.stderr)
+	return flags
+}
+
+// isSet reports whether the flag was given on the command line
+func isSet(flags *flag.FlagSet, name string) bool {
+	set := false
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// checkResponse turns error responses into an apiError
+func checkResponse(res *http.Response, body []byte) error {
+	if res.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+	errorResponse := client.ErrorResponse{}
+	if json.Unmarshal(body, &errorResponse) != nil || errorResponse.Error == "" {
+		errorResponse.Error = strings.TrimSpace(string(body))
+	}
+	return &apiError{status: res.StatusCode, message: errorResponse.Error}
+}
+
+// findShortUrl describes the url. Unlike getting the short url this
+// neither counts a click nor needs the password of protected links.
+func findShortUrl(cli *cli, shortUrl string) (*client.ShortUrl, error) {
+	res, err := cli.client.DescribeShortUrlWithResponse(cli.ctx, shortUrl)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkResponse(res.HTTPResponse, res.Body); err != nil {
+		return nil, err
+	}
+	return res.JSON200, nil
+}
+
+func createCommand(cli *cli, args []string) error {
+	flags := newFlagSet(cli, "create")
+	password := flags.String("password", "", "protect the link with a password")
+	maxClicks := flags.Int("max-clicks", 0, "number of times the link can be followed, unlimited when 0")
+	title := flags.String("title", "", "title of the link")
+	description := flags.String("description", "", "description of the link")
+	domain := flags.String("domain", "", "registered domain to create the link on")
+	forwardQuery := flags.Bool("forward-query", false, "pass the query string of the short url on to the original url")
+	idempotencyKey := flags.String("idempotency-key", "", "key making retries of the command create the link only once")
+	var tags stringList
+	flags.Var(&tags, "tag", "tag of the link, repeatable")
+	positional, err := parseArgs(flags, args, "<original url>")
+	if err != nil {
+		return err
+	}
+
+	body := client.CreateShortUrlJSONRequestBody{OriginalUrl: positional[0]}
+	if *password != "" {
+		body.Password = password
+	}
+	if *maxClicks != 0 {
+		body.MaxClicks = maxClicks
+	}
+	if *title != "" {
+		body.Title = title
+	}
+	if *description != "" {
+		body.Description = description
+	}
+	if *domain != "" {
+		body.Domain = domain
+	}
+	if *forwardQuery {
+		body.ForwardQuery = forwardQuery
+	}
+	if len(tags) > 0 {
+		body.Tags = (*[]string)(&tags)
+	}
+	params := &client.CreateShortUrlParams{}
+	if *idempotencyKey != "" {
+		params.IdempotencyKey = idempotencyKey
+	}
+	res, err := cli.client.CreateShortUrlWithResponse(cli.ctx, params, body)
+	if err != nil {
+		return err
+	}
+	if err = checkResponse(res.HTTPResponse, res.Body); err != nil {
+		return err
+	}
+	return cli.printer.url(res.JSON201)
+}
+
+func getCommand(cli *cli, args []string) error {
+	positional, err := parseArgs(newFlagSet(cli, "get"), args, "<short url>")
+	if err != nil {
+		return err
+	}
+	url, err := findShortUrl(cli, positional[0])
+	if err != nil {
+		return err
+	}
+	return cli.printer.url(url)
+}
+
+func updateCommand(cli *cli, args []string) error {
+	flags := newFlagSet(cli, "update")
+	title := flags.String("title", "", "new title")
+	description := flags.String("description", "", "new description")
+	password := flags.String("password", "", "new password, empty removes the protection")
+	regenerate := flags.Bool("regenerate", false, "give the link a new short url")
+	var tags stringList
+	flags.Var(&tags, "tag", "tag replacing the current ones, repeatable")
+	positional, err := parseArgs(flags, args, "<short url>")
+	if err != nil {
+		return err
+	}
+
+	body := client.UpdateShortUrlRequest{}
+	if isSet(flags, "title") {
+		body.Title = title
+	}
+	if isSet(flags, "description") {
+		body.Description = description
+	}
+	if isSet(flags, "password") {
+		body.Password = password
+	}
+	if isSet(flags, "tag") {
+		body.Tags = (*[]string)(&tags)
+	}
+	if *regenerate {
+		res, err := cli.client.UpdateShortUrlWithResponse(cli.ctx, positional[0], nil, body)
+		if err != nil {
+			return err
+		}
+		if err = checkResponse(res.HTTPResponse, res.Body); err != nil {
+			return err
+		}
+		if cli.printer.format == outputJson {
+			return cli.printer.json(res.JSON201)
+		}
+		return cli.printer.table([]string{"SHORT URL", "UPDATED SHORT URL"}, [][]string{{positional[0], res.JSON201.UpdatedShortUrl}})
+	}
+	res, err := cli.client.PatchShortUrlWithResponse(cli.ctx, positional[0], nil, body)
+	if err != nil {
+		return err
+	}
+	if err = checkResponse(res.HTTPResponse, res.Body); err != nil {
+		return err
+	}
+	return cli.printer.url(res.JSON200)
+}
+
+func deleteCommand(cli *cli, args []string) error {
+	positional, err := parseArgs(newFlagSet(cli, "delete"), args, "<short url>")
+	if err != nil {
+		return err
+	}
+	res, err := cli.client.DeleteShortUrlWithResponse(cli.ctx, positional[0], nil)
+	if err != nil {
+		return err
+	}
+	if err = checkResponse(res.HTTPResponse, res.Body); err != nil {
+		return err
+	}
+	if cli.printer.format == outputJson {
+		return cli.printer.json(map[string]string{"deleted": positional[0]})
+	}
+	_, err = fmt.Fprintf(cli.printer.out, "Deleted %s\n", positional[0])
+	return err
+}
+
+func listCommand(cli *cli, args []string) error {
+	flags := newFlagSet(cli, "list")
+	tag := flags.String("tag", "", "only list the links with this tag")
+	limit := flags.Int("limit", 50, "number of links to list")
+	offset := flags.Int("offset", 0, "number of links to skip")
+	if _, err := parseArgs(flags, args); err != nil {
+		return err
+	}
+	params := &client.ListShortUrlsParams{Limit: limit, Offset: offset}
+	if *tag != "" {
+		params.Tag = tag
+	}
+	res, err := cli.client.ListShortUrlsWithResponse(cli.ctx, params)
+	if err != nil {
+		return err
+	}
+	if err = checkResponse(res.HTTPResponse, res.Body); err != nil {
+		return err
+	}
+	return cli.printer.urls(*res.JSON200)
+}
+
+// urlStats is the JSON output of the stats command
+type urlStats struct {
+	ShortUrl        string           `json:"short_url"`
+	ClickCount      int              `json:"click_count"`
+	MaxClicks       *int             `json:"max_clicks,omitempty"`
+	RemainingClicks *int             `json:"remaining_clicks,omitempty"`
+	Variants        []client.Variant `json:"variants,omitempty"`
+}
+
+func statsCommand(cli *cli, args []string) error {
+	positional, err := parseArgs(newFlagSet(cli, "stats"), args, "<short url>")
+	if err != nil {
+		return err
+	}
+	url, err := findShortUrl(cli, positional[0])
+	if err != nil {
+		return err
+	}
+	stats := urlStats{
+		ShortUrl:        url.ShortUrl,
+		ClickCount:      url.ClickCount,
+		MaxClicks:       url.MaxClicks,
+		RemainingClicks: url.RemainingClicks,
+		Variants:        deref(url.Variants),
+	}
+	if cli.printer.format == outputJson {
+		return cli.printer.json(stats)
+	}
+	rows := [][]string{{"total", "", strconv.Itoa(stats.ClickCount)}}
+	if stats.RemainingClicks != nil {
+		rows = append(rows, []string{"remaining", "", fmt.Sprintf("%d of %d", *stats.RemainingClicks, deref(stats.MaxClicks))})
+	}
+	for _, variant := range stats.Variants {
+		rows = append(rows, []string{"variant " + deref(variant.Name), variant.Destination, strconv.Itoa(deref(variant.ClickCount))})
+	}
+	return cli.printer.table([]string{"CLICKS", "DESTINATION", "COUNT"}, rows)
+}
+
+// importResult is the outcome of one CSV row
+type importResult struct {
+	Row      int    `json:"row"`
+	ShortUrl string `json:"short_url,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// importCommand creates a short url per CSV row. The header names the columns:
+// original_url is required, title, description, tags (comma separated), password
+// and max_clicks are optional. Rows that fail are reported and don't stop the import.
+func importCommand(cli *cli, args []string) error {
+	positional, err := parseArgs(newFlagSet(cli, "import"), args, "<csv file>")
+	if err != nil {
+		return err
+	}
+	var input io.Reader = cli.stdin
+	if positional[0] != "-" {
+		file, err := os.Open(positional[0])
+		if err != nil {
+			return &usageError{err.Error()}
+		}
+		defer file.Close()
+		input = file
+	}
+	reader := csv.NewReader(input)
+	header, err := reader.Read()
+	if err != nil {
+		return &usageError{fmt.Sprintf("can not read the CSV header: %v", err)}
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["original_url"]; !ok {
+		return &usageError{"the CSV header has no original_url column"}
+	}
+
+	var results []importResult
+	failed := 0
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		result := importResult{Row: row}
+		if err == nil {
+			result.ShortUrl, err = importRow(cli, columns, record)
+		}
+		if err != nil {
+			result.Error = err.Error()
+			failed++
+		}
+		results = append(results, result)
+	}
+
+	if cli.printer.format == outputJson {
+		err = cli.printer.json(results)
+	} else {
+		rows := make([][]string, 0, len(results))
+		for _, result := range results {
+			rows = append(rows, []string{strconv.Itoa(result.Row), result.ShortUrl, result.Error})
+		}
+		err = cli.printer.table([]string{"ROW", "SHORT URL", "ERROR"}, rows)
+	}
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d rows failed", failed, len(results))
+	}
+	return nil
+}
+
+func importRow(cli *cli, columns map[string]int, record []string) (string, error) {
+	value := func(name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	body := client.CreateShortUrlJSONRequestBody{OriginalUrl: value("original_url")}
+	for name, field := range map[string]**string{"title": &body.Title, "description": &body.Description, "password": &body.Password} {
+		if v := value(name); v != "" {
+			*field = &v
+		}
+	}
+	if tags := value("tags"); tags != "" {
+		split := strings.Split(tags, ",")
+		body.Tags = &split
+	}
+	if maxClicks := value("max_clicks"); maxClicks != "" {
+		parsed, err := strconv.Atoi(maxClicks)
+		if err != nil {
+			return "", fmt.Errorf("max_clicks must be an integer")
+		}
+		body.MaxClicks = &parsed
+	}
+	res, err := cli.client.CreateShortUrlWithResponse(cli.ctx, nil, body)
+	if err != nil {
+		return "", err
+	}
+	if err = checkResponse(res.HTTPResponse, res.Body); err != nil {
+		return "", err
+	}
+	return res.JSON201.ShortUrl, nil
+}
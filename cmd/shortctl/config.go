@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const defaultBaseUrl = "http://localhost:8080"
+
+// Config is the profile file, by default ~/.config/shortctl/config.json
+type Config struct {
+	// DefaultProfile is used when --profile isn't given
+	DefaultProfile string              `json:"default_profile"`
+	Profiles       map[string]*Profile `json:"profiles"`
+}
+
+// Profile is a server to talk to
+type Profile struct {
+	BaseUrl string `json:"base_url"`
+	// ApiKey is sent as bearer token
+	ApiKey string `json:"api_key,omitempty"`
+	// Host overrides the Host header to manage the urls of a branded domain
+	Host string `json:"host,omitempty"`
+}
+
+func defaultConfigPath() string {
+	if path := os.Getenv("SHORTCTL_CONFIG"); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "shortctl", "config.json")
+}
+
+// loadProfile reads the named profile from the config file. A missing file is only
+// an error if a profile was asked for, without it the local server is used.
+func loadProfile(path string, name string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && name == "" {
+		return &Profile{BaseUrl: defaultBaseUrl}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	config := &Config{}
+	if err = json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	if name == "" {
+		name = config.DefaultProfile
+	}
+	if name == "" {
+		name = "default"
+	}
+	profile, ok := config.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("profile %q not found in %s", name, path)
+	}
+	if profile.BaseUrl == "" {
+		profile.BaseUrl = defaultBaseUrl
+	}
+	return profile, nil
+}
@@ -0,0 +1,175 @@
+// Command shortctl manages short urls from a terminal through the /api/short endpoints.
+//
+//	shortctl [--profile name] [--output table|json] <command> [flags] [args]
+//
+// The server and API key are read from a profile of the config file, see Config.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"URL_SHORTENER/api/client"
+)
+
+// Exit codes
+const (
+	exitOK       = 0
+	exitError    = 1
+	exitUsage    = 2
+	exitNotFound = 3
+	exitConflict = 4
+)
+
+const usage = `Usage: shortctl [global flags] <command> [flags] [args]
+
+Commands:
+  create <original url>   Create a short url
+  get <short url>         Show a short url
+  update <short url>      Change the details of a short url, --regenerate gives it a new code
+  delete <short url>      Delete a short url
+  list                    List the short urls, newest first
+  stats <short url>       Show the clicks of a short url and its variants
+  import <csv file>       Create short urls from a CSV file, - reads stdin
+
+Global flags:
+`
+
+// command runs a subcommand with the arguments following its name
+type command func(cli *cli, args []string) error
+
+var commands = map[string]command{
+	"create": createCommand,
+	"get":    getCommand,
+	"update": updateCommand,
+	"delete": deleteCommand,
+	"list":   listCommand,
+	"stats":  statsCommand,
+	"import": importCommand,
+}
+
+// cli is what the subcommands share
+type cli struct {
+	ctx     context.Context
+	client  *client.ClientWithResponses
+	printer *printer
+	stdin   io.Reader
+	stderr  io.Writer
+}
+
+// usageError is reported with exit code 2
+type usageError struct {
+	message string
+}
+
+func (e *usageError) Error() string {
+	return e.message
+}
+
+// apiError is an error response of the server
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%s (HTTP %d)", e.message, e.status)
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command line and returns the exit code
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("shortctl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		flags.PrintDefaults()
+	}
+	configPath := flags.String("config", defaultConfigPath(), "config file with the profiles, $SHORTCTL_CONFIG")
+	profileName := flags.String("profile", os.Getenv("SHORTCTL_PROFILE"), "profile of the config file, $SHORTCTL_PROFILE")
+	baseUrl := flags.String("base-url", "", "server to talk to, overrides the profile")
+	output := flags.String("output", outputTable, "output format, table or json")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitUsage
+	}
+	cmd, ok := commands[flags.Arg(0)]
+	if !ok {
+		fmt.Fprintf(stderr, "shortctl: unknown command %q\n", flags.Arg(0))
+		flags.Usage()
+		return exitUsage
+	}
+	if *output != outputTable && *output != outputJson {
+		fmt.Fprintf(stderr, "shortctl: output must be %s or %s\n", outputTable, outputJson)
+		return exitUsage
+	}
+
+	profile, err := loadProfile(*configPath, *profileName)
+	if err != nil {
+		fmt.Fprintf(stderr, "shortctl: %v\n", err)
+		return exitUsage
+	}
+	if *baseUrl != "" {
+		profile.BaseUrl = *baseUrl
+	}
+	apiClient, err := client.NewClientWithResponses(strings.TrimSuffix(profile.BaseUrl, "/"),
+		client.WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
+			if profile.ApiKey != "" {
+				req.Header.Set("Authorization", "Bearer "+profile.ApiKey)
+			}
+			if profile.Host != "" {
+				req.Host = profile.Host
+			}
+			return nil
+		}))
+	if err != nil {
+		fmt.Fprintf(stderr, "shortctl: %v\n", err)
+		return exitUsage
+	}
+
+	err = cmd(&cli{
+		ctx:     context.Background(),
+		client:  apiClient,
+		printer: &printer{out: stdout, format: *output},
+		stdin:   stdin,
+		stderr:  stderr,
+	}, flags.Args()[1:])
+	return exitCode(err, stderr)
+}
+
+// exitCode reports the error and maps it to the exit code
+func exitCode(err error, stderr io.Writer) int {
+	if err == nil {
+		return exitOK
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	fmt.Fprintf(stderr, "shortctl: %v\n", err)
+	var usageErr *usageError
+	var apiErr *apiError
+	switch {
+	case errors.As(err, &usageErr):
+		return exitUsage
+	case errors.As(err, &apiErr) && apiErr.status == http.StatusNotFound:
+		return exitNotFound
+	case errors.As(err, &apiErr) && apiErr.status == http.StatusConflict:
+		return exitConflict
+	}
+	return exitError
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"URL_SHORTENER/api/client"
+	"URL_SHORTENER/controller"
+	"URL_SHORTENER/storage"
+
+	"github.com/gorilla/mux"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+)
+
+// setupServer serves the short url endpoints from an in-memory store
+func setupServer(t *testing.T) string {
+	_ = os.Setenv("DB_PATH", ":memory:")
+	store, err := storage.NewURLStore()
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+	controller.Init(store)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/short", controller.CreateShortUrl).Methods("POST")
+	router.HandleFunc("/api/short", controller.ListShortUrls).Methods("GET")
+	router.HandleFunc("/api/short/{short_url}", controller.RedirectUrl).Methods("GET")
+	router.HandleFunc("/api/short/{short_url}", controller.UpdateShortUrl).Methods("PUT")
+	router.HandleFunc("/api/short/{short_url}", controller.PatchShortUrl).Methods("PATCH")
+	router.HandleFunc("/api/short/{short_url}", controller.DeleteShortUrl).Methods("DELETE")
+	router.HandleFunc("/api/short/{short_url}/info", controller.DescribeShortUrl).Methods("GET")
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+// runCli runs shortctl against the server and returns the exit code and output
+func runCli(t *testing.T, baseUrl string, stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	args = append([]string{"--config", filepath.Join(t.TempDir(), "missing.json"), "--base-url", baseUrl}, args...)
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestCommands(t *testing.T) {
+	baseUrl := setupServer(t)
+
+	code, out, _ := runCli(t, baseUrl, "", "--output", "json", "create", "https://example.com", "--title", "Example", "--tag", "docs", "--tag", "go")
+	require.Equal(t, exitOK, code)
+	created := client.ShortUrl{}
+	require.NoError(t, json.Unmarshal([]byte(out), &created))
+	require.Equal(t, "https://example.com", *created.OriginalUrl)
+	require.Equal(t, []string{"docs", "go"}, *created.Tags)
+
+	code, out, _ = runCli(t, baseUrl, "", "get", created.ShortUrl)
+	require.Equal(t, exitOK, code)
+	require.Contains(t, out, "https://example.com")
+	require.Contains(t, out, "Example")
+
+	code, out, _ = runCli(t, baseUrl, "", "--output", "json", "update", "--title", "Renamed", created.ShortUrl)
+	require.Equal(t, exitOK, code)
+	updated := client.ShortUrl{}
+	require.NoError(t, json.Unmarshal([]byte(out), &updated))
+	require.Equal(t, "Renamed", *updated.Title)
+	require.Equal(t, []string{"docs", "go"}, *updated.Tags)
+
+	code, out, _ = runCli(t, baseUrl, "", "list", "--tag", "docs")
+	require.Equal(t, exitOK, code)
+	require.Contains(t, out, "SHORT URL")
+	require.Contains(t, out, created.ShortUrl)
+
+	code, out, _ = runCli(t, baseUrl, "", "--output", "json", "stats", created.ShortUrl)
+	require.Equal(t, exitOK, code)
+	stats := urlStats{}
+	require.NoError(t, json.Unmarshal([]byte(out), &stats))
+	require.Equal(t, 0, stats.ClickCount)
+
+	code, out, _ = runCli(t, baseUrl, "", "--output", "json", "update", "--regenerate", created.ShortUrl)
+	require.Equal(t, exitOK, code)
+	regenerated := client.UpdateShortUrlResponse{}
+	require.NoError(t, json.Unmarshal([]byte(out), &regenerated))
+	require.NotEqual(t, created.ShortUrl, regenerated.UpdatedShortUrl)
+
+	code, out, _ = runCli(t, baseUrl, "", "delete", regenerated.UpdatedShortUrl)
+	require.Equal(t, exitOK, code)
+	require.Contains(t, out, "Deleted")
+
+	code, _, stderr := runCli(t, baseUrl, "", "get", regenerated.UpdatedShortUrl)
+	require.Equal(t, exitNotFound, code)
+	require.Contains(t, stderr, "does not exist")
+
+	code, _, _ = runCli(t, baseUrl, "", "delete", regenerated.UpdatedShortUrl)
+	require.Equal(t, exitNotFound, code)
+}
+
+func TestUsageErrors(t *testing.T) {
+	baseUrl := setupServer(t)
+
+	code, _, _ := runCli(t, baseUrl, "")
+	require.Equal(t, exitUsage, code)
+	code, _, stderr := runCli(t, baseUrl, "", "shorten")
+	require.Equal(t, exitUsage, code)
+	require.Contains(t, stderr, "unknown command")
+	code, _, _ = runCli(t, baseUrl, "", "get")
+	require.Equal(t, exitUsage, code)
+	code, _, _ = runCli(t, baseUrl, "", "--output", "yaml", "list")
+	require.Equal(t, exitUsage, code)
+	code, _, _ = runCli(t, baseUrl, "", "create", "https://example.com", "--max-clicks", "many")
+	require.Equal(t, exitUsage, code)
+
+	// Invalid requests are reported by the server
+	code, _, stderr = runCli(t, baseUrl, "", "create", "https://example.com", "--max-clicks", "-1")
+	require.Equal(t, exitError, code)
+	require.Contains(t, stderr, "HTTP 400")
+}
+
+func TestImport(t *testing.T) {
+	baseUrl := setupServer(t)
+
+	csv := "original_url,title,tags,max_clicks\n" +
+		"https://example.com/a,First,\"docs,go\",\n" +
+		",Second,,\n" +
+		"https://example.com/c,,,many\n" +
+		"https://example.com/d,,,3\n"
+	code, out, stderr := runCli(t, baseUrl, csv, "--output", "json", "import", "-")
+	require.Equal(t, exitError, code)
+	require.Contains(t, stderr, "2 of 4 rows failed")
+	var results []importResult
+	require.NoError(t, json.Unmarshal([]byte(out), &results))
+	require.Len(t, results, 4)
+	require.NotEmpty(t, results[0].ShortUrl)
+	require.NotEmpty(t, results[1].Error)
+	require.Equal(t, "max_clicks must be an integer", results[2].Error)
+	require.NotEmpty(t, results[3].ShortUrl)
+
+	code, out, _ = runCli(t, baseUrl, "", "--output", "json", "list", "--tag", "go")
+	require.Equal(t, exitOK, code)
+	var urls []client.ShortUrl
+	require.NoError(t, json.Unmarshal([]byte(out), &urls))
+	require.Len(t, urls, 1)
+	require.Equal(t, "First", *urls[0].Title)
+
+	code, _, _ = runCli(t, baseUrl, "url\nhttps://example.com\n", "import", "-")
+	require.Equal(t, exitUsage, code)
+}
+
+func TestLoadProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"default_profile": "staging",
+		"profiles": {
+			"staging": {"base_url": "https://staging.example.com", "api_key": "secret"},
+			"local": {"base_url": "http://localhost:9000", "host": "sho.rt"}
+		}
+	}`), 0o600))
+
+	profile, err := loadProfile(path, "")
+	require.NoError(t, err)
+	require.Equal(t, "https://staging.example.com", profile.BaseUrl)
+	require.Equal(t, "secret", profile.ApiKey)
+
+	profile, err = loadProfile(path, "local")
+	require.NoError(t, err)
+	require.Equal(t, "sho.rt", profile.Host)
+
+	_, err = loadProfile(path, "production")
+	require.Error(t, err)
+
+	profile, err = loadProfile(filepath.Join(t.TempDir(), "missing.json"), "")
+	require.NoError(t, err)
+	require.Equal(t, defaultBaseUrl, profile.BaseUrl)
+}
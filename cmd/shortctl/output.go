@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"URL_SHORTENER/api/client"
+)
+
+const (
+	outputTable = "table"
+	outputJson  = "json"
+)
+
+// printer writes command results as indented JSON or as a table
+type printer struct {
+	out    io.Writer
+	format string
+}
+
+func (p *printer) json(value interface{}) error {
+	encoder := json.NewEncoder(p.out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// table writes the rows under the header with aligned columns
+func (p *printer) table(header []string, rows [][]string) error {
+	w := tabwriter.NewWriter(p.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+func (p *printer) urls(urls []client.ShortUrl) error {
+	if p.format == outputJson {
+		return p.json(urls)
+	}
+	rows := make([][]string, 0, len(urls))
+	for _, url := range urls {
+		rows = append(rows, []string{url.ShortUrl, deref(url.OriginalUrl), strconv.Itoa(url.ClickCount), strings.Join(deref(url.Tags), ","), url.CreatedAt.Format(time.RFC3339)})
+	}
+	return p.table([]string{"SHORT URL", "ORIGINAL URL", "CLICKS", "TAGS", "CREATED AT"}, rows)
+}
+
+func (p *printer) url(url *client.ShortUrl) error {
+	if p.format == outputJson {
+		return p.json(url)
+	}
+	return p.table([]string{"FIELD", "VALUE"}, [][]string{
+		{"short_url", url.ShortUrl},
+		{"original_url", deref(url.OriginalUrl)},
+		{"created_at", url.CreatedAt.Format(time.RFC3339)},
+		{"title", deref(url.Title)},
+		{"description", deref(url.Description)},
+		{"tags", strings.Join(deref(url.Tags), ",")},
+		{"password_protected", strconv.FormatBool(url.PasswordProtected)},
+		{"click_count", strconv.Itoa(url.ClickCount)},
+	})
+}
+
+// deref returns the value the pointer points to, or the zero value for nil
+func deref[T any](value *T) T {
+	if value == nil {
+		var zero T
+		return zero
+	}
+	return *value
+}
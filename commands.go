@@ -0,0 +1,87 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"URL_SHORTENER/storage"
+)
+
+const (
+	defaultBackupDir  = "backups"
+	defaultBackupKeep = 7
+)
+
+// backupConfig reads the backup directory and how many backups are kept from
+// the BACKUP_DIR and BACKUP_KEEP environment variables
+func backupConfig() (string, int, error) {
+	dir := os.Getenv("BACKUP_DIR")
+	if dir == "" {
+		dir = defaultBackupDir
+	}
+	keep := defaultBackupKeep
+	if value := os.Getenv("BACKUP_KEEP"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return "", 0, fmt.Errorf("BACKUP_KEEP must be a number of backups, 0 keeps all of them")
+		}
+		keep = parsed
+	}
+	return dir, keep, nil
+}
+
+// runCommand runs a maintenance subcommand instead of the server and returns the exit code
+//
+//	backup [--dir backups] [--keep 7]    snapshot the database while the server may keep running
+//	restore [--db path] <backup file>    replace the database with a backup, stop the server first
+func runCommand(args []string, dbPath string, stdout io.Writer, stderr io.Writer) int {
+	dir, keep, err := backupConfig()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	switch args[0] {
+	case "backup":
+		flags.StringVar(&dir, "dir", dir, "directory the backups are kept in, $BACKUP_DIR")
+		flags.IntVar(&keep, "keep", keep, "number of backups kept, 0 keeps all of them, $BACKUP_KEEP")
+		if flags.Parse(args[1:]) != nil || flags.NArg() != 0 {
+			return 2
+		}
+		_ = os.Setenv("DB_PATH", dbPath)
+		store, err := storage.NewURLStore()
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		defer store.Close()
+		backup, err := store.Backup(dir, keep)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "Wrote %s, %d bytes, schema version %d, sha256 %s\n", filepath.Join(dir, backup.Name), backup.Size,
+			backup.SchemaVersion, backup.Sha256)
+	case "restore":
+		flags.StringVar(&dbPath, "db", dbPath, "database to replace")
+		if flags.Parse(args[1:]) != nil || flags.NArg() != 1 {
+			fmt.Fprintln(stderr, "usage: restore [--db path] <backup file>")
+			return 2
+		}
+		backup, err := storage.RestoreBackup(flags.Arg(0), dbPath)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "Restored %s into %s, schema version %d\n", backup.Name, dbPath, backup.SchemaVersion)
+	default:
+		fmt.Fprintf(stderr, "unknown command %q, the commands are backup and restore\n", args[0])
+		return 2
+	}
+	return 0
+}
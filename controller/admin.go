@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+const (
+	HeaderAdminKey       = "X-Admin-Key"
+	ErrAdminApiDisabled  = "The admin API is disabled, set ADMIN_API_KEY to enable it."
+	ErrAdminUnauthorized = "A valid admin key is required."
+)
+
+// adminApiKey protects the admin endpoints, they are disabled while it is empty
+var adminApiKey string
+
+// SetAdminApiKey sets the key admin requests have to present
+func SetAdminApiKey(key string) {
+	adminApiKey = key
+}
+
+// AdminOnly only lets requests carrying the admin key through, either in the
+// X-Admin-Key header or as a bearer token
+func AdminOnly(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if adminApiKey == "" {
+			ServerResponse(w, http.StatusForbidden, ErrorResponse{Error: ErrAdminApiDisabled})
+			return
+		}
+		if !isAdmin(r) {
+			ServerResponse(w, http.StatusUnauthorized, ErrorResponse{Error: ErrAdminUnauthorized})
+			return
+		}
+		next(w, r)
+	}
+}
+
+// isAdmin reports whether the request carries the admin key, always false while the admin API is disabled
+func isAdmin(r *http.Request) bool {
+	if adminApiKey == "" {
+		return false
+	}
+	key := r.Header.Get(HeaderAdminKey)
+	if key == "" {
+		key = strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	}
+	return subtle.ConstantTimeCompare([]byte(key), []byte(adminApiKey)) == 1
+}
@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+
+	"URL_SHORTENER/storage"
+)
+
+// backupDir is where the backups taken through the admin API are written,
+// only the newest backupKeep of them are kept
+var (
+	backupDir  = "backups"
+	backupKeep = 7
+)
+
+// SetBackupConfig sets the backup directory and how many backups are kept, all of them when keep is 0
+func SetBackupConfig(dir string, keep int) {
+	backupDir = dir
+	backupKeep = keep
+}
+
+// CreateBackup writes a snapshot of the database while the server keeps running
+func CreateBackup(w http.ResponseWriter, r *http.Request) {
+	backup, err := store.Backup(backupDir, backupKeep)
+	if err != nil {
+		log.Printf("backup failed: %v", err)
+		ServerResponse(w, http.StatusInternalServerError, ErrorResponse{Error: "Error creating backup."})
+		return
+	}
+	ServerResponse(w, http.StatusCreated, backup)
+}
+
+// ListBackups lists the backups in the backup directory, newest first
+func ListBackups(w http.ResponseWriter, r *http.Request) {
+	backups, err := storage.ListBackups(backupDir)
+	if err != nil {
+		log.Printf("listing backups failed: %v", err)
+		ServerResponse(w, http.StatusInternalServerError, ErrorResponse{Error: "Error listing backups."})
+		return
+	}
+	ServerResponse(w, http.StatusOK, backups)
+}
@@ -0,0 +1,27 @@
+package controller
+
+import (
+	"fmt"
+
+	"URL_SHORTENER/blocklist"
+	"URL_SHORTENER/models"
+)
+
+// urlBlocklist screens the destinations of new and changed urls, nothing is screened while it is nil
+var urlBlocklist *blocklist.Blocklist
+
+// SetBlocklist sets the blocklist destinations are checked against
+func SetBlocklist(list *blocklist.Blocklist) {
+	urlBlocklist = list
+}
+
+// screenUrl rejects urls with a destination on the blocklist
+func screenUrl(url *models.Url) error {
+	if urlBlocklist == nil {
+		return nil
+	}
+	if reason, blocked := urlBlocklist.CheckUrl(url); blocked {
+		return fmt.Errorf("The destination is on the malware and phishing blocklist, %s", reason)
+	}
+	return nil
+}
@@ -1,130 +1,280 @@
 package controller
 
 import (
-	"crypto/rand"
 	"encoding/json"
 	"errors"
-	"math/big"
+	"io"
+	"log"
+	"math"
 	"net/http"
 	"time"
 
+	"URL_SHORTENER/fetcher"
 	"URL_SHORTENER/models"
+	"URL_SHORTENER/service"
 	"URL_SHORTENER/storage"
+	"URL_SHORTENER/webhook"
 )
 
 const (
 	PathParamShortUrlId = "short_url"
-	YYYYMMDDhhmmss      = "2006-01-02 15:04:05"
-	charSet             = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	// YYYYMMDDhhmmss lays out the times shown in pages
+	YYYYMMDDhhmmss = "2006-01-02 15:04:05"
 )
 
 var store storage.URLOperations
 
+// shortener runs the url operations of the handlers, screening the destinations and publishing the link events
+var shortener *service.Shortener
+
+// clock tells the time of the handlers, like the creation times they store and the time windows of rules
+var clock service.Clock = service.SystemClock{}
+
 func Init(urlStore storage.URLOperations) {
 	store = urlStore
+	shortener = service.NewShortener(urlStore, linkScreener{}, linkEvents{})
+	shortener.SetClock(clock)
 }
 
-type ShortUrlResponse struct {
-	OriginalUrl string `json:"original_url"`
-	ShortUrl    string `json:"short_url"`
-	CreatedAt   string `json:"created_at"`
+// SetClock sets the clock the handlers and the url service run on
+func SetClock(c service.Clock) {
+	clock = c
+	if shortener != nil {
+		shortener.SetClock(c)
+	}
 }
 
-type CreateShortUrlRequestParams struct {
-	OriginalUrl string `json:"original_url"`
+// Shortener returns the url service the handlers run on, for other APIs sharing their rules
+func Shortener() *service.Shortener {
+	return shortener
+}
+
+// pageFetcher fetches the destination page of new urls, nothing is fetched while it is nil
+var pageFetcher *fetcher.Fetcher
+
+// SetPageFetcher sets the fetcher new urls are queued on
+func SetPageFetcher(f *fetcher.Fetcher) {
+	pageFetcher = f
 }
 
+type ShortUrlResponse struct {
+	Domain            string                 `json:"domain,omitempty"`
+	OriginalUrl       string                 `json:"original_url,omitempty"`
+	ShortUrl          string                 `json:"short_url"`
+	CreatedAt         time.Time              `json:"created_at"`
+	PasswordProtected bool                   `json:"password_protected"`
+	MaxClicks         *int                   `json:"max_clicks,omitempty"`
+	RemainingClicks   *int                   `json:"remaining_clicks,omitempty"`
+	Title             string                 `json:"title,omitempty"`
+	Description       string                 `json:"description,omitempty"`
+	Tags              []string               `json:"tags,omitempty"`
+	Metadata          map[string]interface{} `json:"metadata,omitempty"`
+	Page              *models.PageMetadata   `json:"page,omitempty"`
+	ClickCount        int                    `json:"click_count"`
+	Utm               *models.UtmParams      `json:"utm,omitempty"`
+	ForwardQuery      bool                   `json:"forward_query"`
+	Rules             []models.TargetingRule `json:"rules,omitempty"`
+	Variants          []models.Variant       `json:"variants,omitempty"`
+	VariantMode       string                 `json:"variant_mode,omitempty"`
+	Status            string                 `json:"status"`
+	StatusReason      string                 `json:"status_reason,omitempty"`
+	Health            *models.LinkHealth     `json:"health,omitempty"`
+	// DestinationUrl is where the visitor was sent, only set when following the url
+	DestinationUrl string `json:"destination_url,omitempty"`
+}
+
+// CreateShortUrlRequestParams is the body of POST /api/short
+type CreateShortUrlRequestParams = service.CreateParams
+
+// UpdateShortUrlRequestParams is the optional body of PUT and PATCH /api/short/{short_url}
+type UpdateShortUrlRequestParams = service.UpdateParams
+
 type UpdateShortUrlResponse struct {
 	UpdatedShortUrl string `json:"updated_short_url"`
 }
 
+// CreateShortUrl creates a url, once per Idempotency-Key when the header is sent
 func CreateShortUrl(w http.ResponseWriter, r *http.Request) {
+	idempotent(w, r, createShortUrl)
+}
+
+func createShortUrl(w http.ResponseWriter, r *http.Request) {
 	params := new(CreateShortUrlRequestParams)
 	err := json.NewDecoder(r.Body).Decode(&params)
 	if err != nil {
 		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: "Invalid request parameters"})
 		return
 	}
-	err = validateShortenUrlParams(params)
+	url, err := shortener.Create(r.Context(), r.Host, params)
 	if err != nil {
-		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		writeServiceError(w, err)
 		return
 	}
-	shortUrl := generateUniqueShortUrl(8)
-	createdAt := time.Now().Format(YYYYMMDDhhmmss)
+	ServerResponse(w, http.StatusCreated, toShortUrlResponse(url))
+}
 
-	// Convert to DB request
-	url := &models.Url{
-		OriginalUrl: params.OriginalUrl,
-		ShortUrl:    shortUrl,
-		CreatedAt:   createdAt,
+func RedirectUrl(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get(queryParamPreview) == "1" {
+		PreviewUrl(w, r)
+		return
 	}
-
-	err = store.InsertUrl(url)
+	shortUrl, err := ParsePathParam(r, PathParamShortUrlId)
 	if err != nil {
-		// If the URL has already been Shortened
-		if err.Error() == storage.ErrURLAlreadyShortened {
-			ServerResponse(w, http.StatusConflict, ErrorResponse{Error: err.Error()})
-		} else {
-			ServerResponse(w, http.StatusInternalServerError, ErrorResponse{Error: "Error creating url"})
+		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	domain, ok := requestDomain(w, r)
+	if !ok {
+		return
+	}
+	url, err := store.GetOriginalUrl(domain.Name, shortUrl)
+	if err != nil {
+		// Unknown short urls of a domain with a fallback go there instead
+		if domain.FallbackUrl != "" {
+			http.Redirect(w, r, domain.FallbackUrl, domain.RedirectType)
+			return
 		}
+		ServerResponse(w, http.StatusNotFound, ErrorResponse{Error: storage.ErrShortURLDoesNotExist})
 		return
 	}
-	// convert DB response to API response
-	response := ShortUrlResponse{
-		OriginalUrl: url.OriginalUrl,
-		ShortUrl:    url.ShortUrl,
-		CreatedAt:   url.CreatedAt,
+	if !checkUrlStatus(w, r, url) {
+		return
 	}
-	ServerResponse(w, http.StatusCreated, response)
+	if !checkLinkPassword(w, r, url, r.Header.Get(HeaderLinkPassword)) {
+		return
+	}
+	// Clients holding the current state of the url are answered without counting a click
+	if notModified(r, url) {
+		SetHeader(w, headerETag, etag(url))
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	target, variant := pickDestination(w, r, url)
+	if !followUrl(w, url, variant) {
+		return
+	}
+	response := toShortUrlResponse(url)
+	response.DestinationUrl = destinationUrl(url, target, r)
+	SetHeader(w, headerETag, etag(url))
+	// Registered domains redirect visitors. The default namespace describes the url to API clients
+	// and only redirects browsers, like those opened by scanning a QR code.
+	redirectType := domain.RedirectType
+	if redirectType == 0 && acceptsHtml(r) {
+		redirectType = http.StatusFound
+	}
+	if redirectType != 0 {
+		SetHeader(w, "Location", response.DestinationUrl)
+		ServerResponse(w, redirectType, response)
+		return
+	}
+	ServerResponse(w, http.StatusOK, response)
 }
 
-func RedirectUrl(w http.ResponseWriter, r *http.Request) {
+func UpdateShortUrl(w http.ResponseWriter, r *http.Request) {
 	shortUrl, err := ParsePathParam(r, PathParamShortUrlId)
 	if err != nil {
 		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
-	url, err := store.GetOriginalUrl(shortUrl)
+	version, ok := requestVersion(w, r)
+	if !ok {
+		return
+	}
+	params, ok := parseUpdateShortUrlParams(w, r)
+	if !ok {
+		return
+	}
+	newShortUrl, err := shortener.Regenerate(r.Host, shortUrl, version, params)
 	if err != nil {
-		ServerResponse(w, http.StatusNotFound, ErrorResponse{Error: storage.ErrShortURLDoesNotExist})
+		writeServiceError(w, err)
 		return
 	}
-
 	// convert DB response to API response
-	response := ShortUrlResponse{
-		OriginalUrl: url.OriginalUrl,
-		ShortUrl:    url.ShortUrl,
-		CreatedAt:   url.CreatedAt,
+	response := UpdateShortUrlResponse{
+		UpdatedShortUrl: newShortUrl,
 	}
-	ServerResponse(w, http.StatusOK, response)
+	ServerResponse(w, http.StatusCreated, response)
+
 }
 
-func UpdateShortUrl(w http.ResponseWriter, r *http.Request) {
+// PatchShortUrl changes the password and details of a url while keeping its short url
+func PatchShortUrl(w http.ResponseWriter, r *http.Request) {
 	shortUrl, err := ParsePathParam(r, PathParamShortUrlId)
 	if err != nil {
 		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
+	version, ok := requestVersion(w, r)
+	if !ok {
+		return
+	}
+	params, ok := parseUpdateShortUrlParams(w, r)
+	if !ok {
+		return
+	}
+	url, err := shortener.Update(r.Host, shortUrl, version, params)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	SetHeader(w, headerETag, etag(url))
+	ServerResponse(w, http.StatusOK, toShortUrlResponse(url))
+}
 
-	newShortUrl := generateUniqueShortUrl(8)
-	createdAt := time.Now().Format(YYYYMMDDhhmmss)
-	err = store.UpdateShortUrl(newShortUrl, shortUrl, createdAt)
+// DescribeShortUrl describes a url without following it, so no click is counted and no password is needed.
+// Like in the list, only admins see where password protected urls lead.
+func DescribeShortUrl(w http.ResponseWriter, r *http.Request) {
+	shortUrl, err := ParsePathParam(r, PathParamShortUrlId)
 	if err != nil {
-		// If the Short url does not exist
-		if err.Error() == storage.ErrShortURLDoesNotExist {
-			ServerResponse(w, http.StatusNotFound, ErrorResponse{Error: err.Error()})
-		} else {
-			ServerResponse(w, http.StatusInternalServerError, ErrorResponse{Error: "Error updating short url."})
-		}
+		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
-	// convert DB response to API response
-	response := UpdateShortUrlResponse{
-		UpdatedShortUrl: newShortUrl,
+	url, err := shortener.Get(r.Host, shortUrl)
+	if err != nil {
+		writeServiceError(w, err)
+		return
 	}
-	ServerResponse(w, http.StatusCreated, response)
+	SetHeader(w, headerETag, etag(url))
+	if url.PasswordHash != "" && !isAdmin(r) {
+		url.HideDestinations()
+	}
+	ServerResponse(w, http.StatusOK, toShortUrlResponse(url))
+}
 
+// ListShortUrls lists the urls of the request domain, optionally only those with a tag, status or health
+func ListShortUrls(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, err := parseIntParam(query.Get("limit"), service.DefaultListLimit, 1, service.MaxListLimit)
+	if err != nil {
+		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: "limit " + err.Error()})
+		return
+	}
+	offset, err := parseIntParam(query.Get("offset"), 0, 0, math.MaxInt32)
+	if err != nil {
+		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: "offset " + err.Error()})
+		return
+	}
+	urls, err := shortener.List(r.Host, &service.ListParams{
+		Tag:    query.Get("tag"),
+		Status: query.Get("status"),
+		Health: query.Get("health"),
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	// Only admins see where password protected urls lead
+	admin := isAdmin(r)
+	response := make([]ShortUrlResponse, 0, len(urls))
+	for i := range urls {
+		if urls[i].PasswordHash != "" && !admin {
+			urls[i].HideDestinations()
+		}
+		response = append(response, toShortUrlResponse(&urls[i]))
+	}
+	ServerResponse(w, http.StatusOK, response)
 }
 
 func DeleteShortUrl(w http.ResponseWriter, r *http.Request) {
@@ -134,32 +284,88 @@ func DeleteShortUrl(w http.ResponseWriter, r *http.Request) {
 		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
-	err = store.DeleteShortUrl(shortUrl)
+	version, ok := requestVersion(w, r)
+	if !ok {
+		return
+	}
+	err = shortener.Delete(r.Host, shortUrl, version)
 	if err != nil {
-		// If the Short url does not exist
-		if err.Error() == storage.ErrShortURLDoesNotExist {
-			ServerResponse(w, http.StatusNotFound, ErrorResponse{Error: err.Error()})
-		} else {
-			ServerResponse(w, http.StatusInternalServerError, ErrorResponse{Error: "Error deleting short url."})
-		}
+		writeServiceError(w, err)
 		return
 	}
 	ServerResponse(w, http.StatusOK, "Deletion Successful.")
 }
 
-// generateUniqueShortUrl generates unique short Url of given length
-func generateUniqueShortUrl(length int) string {
-	shortUrl := make([]byte, length)
-	for i := 0; i < length; i++ {
-		charIndex, _ := rand.Int(rand.Reader, big.NewInt(int64(len(charSet))))
-		shortUrl[i] = charSet[charIndex.Int64()]
+// followUrl counts a visit of the url and of the variant served, using up one of its
+// clicks if it has a limit.
+// It writes the error response and returns false if the url can't be followed.
+func followUrl(w http.ResponseWriter, url *models.Url, variant string) bool {
+	if url.RemainingClicks != nil {
+		remainingClicks, err := store.ConsumeClick(url.Domain, url.ShortUrl)
+		if err != nil {
+			switch err.Error() {
+			case storage.ErrShortURLExhausted:
+				ServerResponse(w, http.StatusGone, ErrorResponse{Error: err.Error()})
+			case storage.ErrShortURLDoesNotExist:
+				ServerResponse(w, http.StatusNotFound, ErrorResponse{Error: err.Error()})
+			default:
+				ServerResponse(w, http.StatusInternalServerError, ErrorResponse{Error: "Error retrieving url."})
+			}
+			return false
+		}
+		url.RemainingClicks = &remainingClicks
+	}
+	// A lost click count shouldn't stop the visitor
+	clickCount, err := store.RecordClick(url.Domain, url.ShortUrl, variant)
+	if err != nil {
+		log.Printf("failed to record click of %s/%s: %v", url.Domain, url.ShortUrl, err)
+		return true
+	}
+	url.ClickCount = clickCount
+	for i := range url.Variants {
+		if url.Variants[i].Name == variant {
+			url.Variants[i].ClickCount++
+		}
+	}
+	publishLinkEvent(webhook.EventLinkClickThreshold, url)
+	return true
+}
+
+// parseUpdateShortUrlParams decodes the optional update body, writing the error response if it is malformed
+func parseUpdateShortUrlParams(w http.ResponseWriter, r *http.Request) (*UpdateShortUrlRequestParams, bool) {
+	params := new(UpdateShortUrlRequestParams)
+	// The request body is optional
+	err := json.NewDecoder(r.Body).Decode(params)
+	if err != nil && !errors.Is(err, io.EOF) {
+		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: "Invalid request parameters"})
+		return nil, false
 	}
-	return string(shortUrl)
+	return params, true
 }
 
-func validateShortenUrlParams(params *CreateShortUrlRequestParams) error {
-	if params.OriginalUrl == "" {
-		return errors.New("Original Url can not be empty")
+// toShortUrlResponse converts a DB url to the API response
+func toShortUrlResponse(url *models.Url) ShortUrlResponse {
+	return ShortUrlResponse{
+		Domain:            url.Domain,
+		OriginalUrl:       url.OriginalUrl,
+		ShortUrl:          url.ShortUrl,
+		CreatedAt:         url.CreatedAt,
+		PasswordProtected: url.PasswordHash != "",
+		MaxClicks:         url.MaxClicks,
+		RemainingClicks:   url.RemainingClicks,
+		Title:             url.Title,
+		Description:       url.Description,
+		Tags:              url.Tags,
+		Metadata:          url.Metadata,
+		Page:              url.Page,
+		ClickCount:        url.ClickCount,
+		Utm:               url.Utm,
+		ForwardQuery:      url.ForwardQuery,
+		Rules:             url.Rules,
+		Variants:          url.Variants,
+		VariantMode:       url.VariantMode,
+		Status:            url.Status,
+		StatusReason:      url.StatusReason,
+		Health:            url.Health,
 	}
-	return nil
 }
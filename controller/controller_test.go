@@ -5,13 +5,18 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"image/png"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"strings"
 	"testing"
 	"time"
 
+	"URL_SHORTENER/geoip"
 	"URL_SHORTENER/models"
+	"URL_SHORTENER/service"
 	"URL_SHORTENER/storage"
 
 	"github.com/golang/mock/gomock"
@@ -74,7 +79,7 @@ func TestCreateShortUrl(t *testing.T) {
 		defer resources.TearDown()
 
 		originalUrl := "http://example.com"
-		createdAt := time.Now().Format(YYYYMMDDhhmmss)
+		createdAt := testNow
 
 		// API request body
 		params := &CreateShortUrlRequestParams{
@@ -114,7 +119,7 @@ func TestRedirectUrl(t *testing.T) {
 
 		shortUrl := "esd87df7"
 		mockErr := errors.New(storage.ErrShortURLDoesNotExist)
-		resources.MockDb.EXPECT().GetOriginalUrl(shortUrl).Times(1).Return(nil, mockErr)
+		resources.MockDb.EXPECT().GetOriginalUrl("", shortUrl).Times(1).Return(nil, mockErr)
 
 		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/short/%s", shortUrl), nil)
 		w := httptest.NewRecorder()
@@ -135,7 +140,7 @@ func TestRedirectUrl(t *testing.T) {
 
 		shortUrl := "esd87df7"
 		originalUrl := "http://example.com"
-		createdAt := time.Now().Format(YYYYMMDDhhmmss)
+		createdAt := testNow
 
 		mockUrlRes := &models.Url{
 			ShortUrl:    shortUrl,
@@ -143,7 +148,8 @@ func TestRedirectUrl(t *testing.T) {
 			CreatedAt:   createdAt,
 		}
 
-		resources.MockDb.EXPECT().GetOriginalUrl(shortUrl).Times(1).Return(mockUrlRes, nil)
+		resources.MockDb.EXPECT().GetOriginalUrl("", shortUrl).Times(1).Return(mockUrlRes, nil)
+		resources.MockDb.EXPECT().RecordClick("", shortUrl, "").Times(1).Return(1, nil)
 
 		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/short/%s", shortUrl), nil)
 		w := httptest.NewRecorder()
@@ -163,6 +169,26 @@ func TestRedirectUrl(t *testing.T) {
 		require.Equal(t, shortUrl, responseBody.ShortUrl)
 	})
 
+	t.Run("Browsers are redirected on the default namespace", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+
+		shortUrl := "esd87df7"
+		mockUrlRes := &models.Url{ShortUrl: shortUrl, OriginalUrl: "http://example.com", CreatedAt: testNow}
+		resources.MockDb.EXPECT().GetOriginalUrl("", shortUrl).Times(1).Return(mockUrlRes, nil)
+		resources.MockDb.EXPECT().RecordClick("", shortUrl, "").Times(1).Return(1, nil)
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/short/%s", shortUrl), nil)
+		req.Header.Set("Accept", "text/html,application/xhtml+xml")
+		w := httptest.NewRecorder()
+		router := mux.NewRouter()
+		router.HandleFunc(endpoint, RedirectUrl)
+		router.ServeHTTP(w, req)
+
+		res := w.Result()
+		require.Equal(t, http.StatusFound, res.StatusCode)
+		require.Equal(t, "http://example.com", res.Header.Get("Location"))
+	})
 }
 
 func TestDeleteShortUrl(t *testing.T) {
@@ -175,7 +201,7 @@ func TestDeleteShortUrl(t *testing.T) {
 		mockShortUrl := "esd87df7"
 		// Setup expectations
 		mockErr := errors.New(storage.ErrShortURLDoesNotExist)
-		resources.MockDb.EXPECT().DeleteShortUrl(mockShortUrl).Times(1).Return(mockErr)
+		resources.MockDb.EXPECT().DeleteShortUrl("", mockShortUrl, int64(0)).Times(1).Return(mockErr)
 		// Create API request
 		req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/short/%s", mockShortUrl), nil)
 		w := httptest.NewRecorder()
@@ -195,7 +221,7 @@ func TestDeleteShortUrl(t *testing.T) {
 
 		mockShortUrl := "esd87df7"
 		// Setup expectations
-		resources.MockDb.EXPECT().DeleteShortUrl(mockShortUrl).Times(1).Return(nil)
+		resources.MockDb.EXPECT().DeleteShortUrl("", mockShortUrl, int64(0)).Times(1).Return(nil)
 		// Create API request
 		req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/short/%s", mockShortUrl), nil)
 		w := httptest.NewRecorder()
@@ -221,7 +247,7 @@ func TestUpdateShortUrl(t *testing.T) {
 		mockShortUrl := "esd87df7"
 		// Setup expectations
 		mockErr := errors.New(storage.ErrShortURLDoesNotExist)
-		resources.MockDb.EXPECT().UpdateShortUrl(gomock.Any(), mockShortUrl, gomock.Any()).Times(1).Return(mockErr)
+		resources.MockDb.EXPECT().GetOriginalUrl("", mockShortUrl).Times(1).Return(nil, mockErr)
 		// Create API request
 		req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/short/%s", mockShortUrl), nil)
 		w := httptest.NewRecorder()
@@ -240,7 +266,8 @@ func TestUpdateShortUrl(t *testing.T) {
 
 		mockShortUrl := "esd87df7"
 		// Setup expectations
-		resources.MockDb.EXPECT().UpdateShortUrl(gomock.Any(), mockShortUrl, gomock.Any()).Times(1).Return(nil)
+		resources.MockDb.EXPECT().GetOriginalUrl("", mockShortUrl).Times(1).Return(&models.Url{ShortUrl: mockShortUrl, Version: 1}, nil)
+		resources.MockDb.EXPECT().UpdateShortUrl(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil)
 		// Create API request
 		req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/short/%s", mockShortUrl), nil)
 		w := httptest.NewRecorder()
@@ -258,25 +285,1501 @@ func TestUpdateShortUrl(t *testing.T) {
 	})
 }
 
-func TestGenerateUniqueShortUrl(t *testing.T) {
-	var length = 8
-	shortUrl1 := generateUniqueShortUrl(length)
-	shortUrl2 := generateUniqueShortUrl(length)
+func TestGetQrCode(t *testing.T) {
+	var endPoint = "/api/short/{short_url}/qr"
+
+	t.Run("Short URL not found", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+
+		mockShortUrl := "esd87df7"
+		resources.MockDb.EXPECT().CheckShortUrlExists("", mockShortUrl).Times(1).Return(false)
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/short/%s/qr", mockShortUrl), nil)
+		w := httptest.NewRecorder()
+		router := mux.NewRouter()
+		router.HandleFunc(endPoint, GetQrCode).Methods("GET")
+		router.ServeHTTP(w, req)
+
+		res := w.Result()
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("Invalid parameters", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+
+		for _, query := range []string{"format=gif", "size=0", "margin=-1", "level=X", "fg=zzzzzz", "bg=fff"} {
+			req := httptest.NewRequest(http.MethodGet, "/api/short/esd87df7/qr?"+query, nil)
+			w := httptest.NewRecorder()
+			router := mux.NewRouter()
+			router.HandleFunc(endPoint, GetQrCode).Methods("GET")
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusBadRequest, w.Result().StatusCode, query)
+		}
+	})
+
+	t.Run("Successful PNG rendering", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+
+		mockShortUrl := "esd87df7"
+		resources.MockDb.EXPECT().CheckShortUrlExists("", mockShortUrl).Times(1).Return(true)
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/short/%s/qr?size=300&margin=2&fg=%%23ff0000", mockShortUrl), nil)
+		w := httptest.NewRecorder()
+		router := mux.NewRouter()
+		router.HandleFunc(endPoint, GetQrCode).Methods("GET")
+		router.ServeHTTP(w, req)
+
+		res := w.Result()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, imagePng, res.Header.Get(contentType))
+		img, err := png.Decode(res.Body)
+		require.NoError(t, err)
+		require.Equal(t, 300, img.Bounds().Dx())
+		// The top left corner is margin, so it has the background color
+		r, g, b, _ := img.At(0, 0).RGBA()
+		require.Equal(t, []uint32{0xffff, 0xffff, 0xffff}, []uint32{r, g, b})
+	})
+
+	t.Run("Successful SVG rendering", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+
+		mockShortUrl := "esd87df7"
+		resources.MockDb.EXPECT().CheckShortUrlExists("", mockShortUrl).Times(1).Return(true)
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/short/%s/qr?format=svg&bg=00ff00", mockShortUrl), nil)
+		w := httptest.NewRecorder()
+		router := mux.NewRouter()
+		router.HandleFunc(endPoint, GetQrCode).Methods("GET")
+		router.ServeHTTP(w, req)
+
+		res := w.Result()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, imageSvg, res.Header.Get(contentType))
+		body := w.Body.String()
+		require.True(t, strings.HasPrefix(body, "<svg"))
+		require.Contains(t, body, `fill="#00ff00"`)
+	})
+
+	t.Run("Size too small for the modules", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+
+		mockShortUrl := "esd87df7"
+		resources.MockDb.EXPECT().CheckShortUrlExists("", mockShortUrl).Times(1).Return(true)
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/short/%s/qr?size=20", mockShortUrl), nil)
+		w := httptest.NewRecorder()
+		router := mux.NewRouter()
+		router.HandleFunc(endPoint, GetQrCode).Methods("GET")
+		router.ServeHTTP(w, req)
+
+		res := w.Result()
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+		var response ErrorResponse
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&response))
+		require.Contains(t, response.Error, "size must be at least")
+	})
+}
+
+func TestPublicRedirectUrl(t *testing.T) {
+	defer SetPublicBaseUrl("")
+	req := httptest.NewRequest(http.MethodGet, "/api/short/esd87df7/qr", nil)
+	branded := &models.Domain{Name: "sho.rt", RedirectType: http.StatusFound}
+
+	require.Equal(t, "http://example.com/esd87df7", publicRedirectUrl(req, &service.DefaultDomain, "esd87df7"))
+	require.Equal(t, "http://sho.rt/esd87df7", publicRedirectUrl(req, branded, "esd87df7"))
 
-	// Check if the length of the generated short URL is correct
-	if len(shortUrl1) != length {
-		t.Errorf("Expected URL length of %d, but got %d", length, len(shortUrl1))
+	// The public host only serves the default namespace, branded domains keep their own host
+	SetPublicBaseUrl("https://go.example.org/")
+	require.Equal(t, "https://go.example.org/esd87df7", publicRedirectUrl(req, &service.DefaultDomain, "esd87df7"))
+	require.Equal(t, "https://sho.rt/esd87df7", publicRedirectUrl(req, branded, "esd87df7"))
+}
+
+func TestPasswordProtectedUrl(t *testing.T) {
+	var endpoint = "/api/short/{short_url}"
+	shortUrl := "esd87df7"
+	password := "s3cret"
+	passwordHash, err := service.HashPassword(password)
+	require.NoError(t, err)
+	mockUrlRes := &models.Url{
+		ShortUrl:     shortUrl,
+		OriginalUrl:  "http://example.com",
+		CreatedAt:    testNow,
+		PasswordHash: passwordHash,
 	}
 
-	// Check if the generated URLs are unique
-	if shortUrl1 == shortUrl2 {
-		t.Errorf("Expected unique URLs, but got identical URLs: %s and %s", shortUrl1, shortUrl2)
+	newRouter := func() *mux.Router {
+		router := mux.NewRouter()
+		router.HandleFunc(endpoint, RedirectUrl).Methods("GET")
+		router.HandleFunc(endpoint+"/unlock", UnlockShortUrl).Methods("POST")
+		return router
 	}
 
-	// Check if the URL contains only allowed characters
-	for _, char := range shortUrl1 {
-		if !strings.ContainsRune(charSet, char) {
-			t.Errorf("Generated URL contains invalid character: %c", char)
+	t.Run("Missing password", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		resources.MockDb.EXPECT().GetOriginalUrl("", shortUrl).Times(1).Return(mockUrlRes, nil)
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/short/%s", shortUrl), nil)
+		w := httptest.NewRecorder()
+		newRouter().ServeHTTP(w, req)
+
+		res := w.Result()
+		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+		require.Equal(t, applicationJson, res.Header.Get(contentType))
+	})
+
+	t.Run("Unlock form for browsers", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		resources.MockDb.EXPECT().GetOriginalUrl("", shortUrl).Times(1).Return(mockUrlRes, nil)
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/short/%s", shortUrl), nil)
+		req.Header.Set("Accept", "text/html,application/xhtml+xml")
+		w := httptest.NewRecorder()
+		newRouter().ServeHTTP(w, req)
+
+		res := w.Result()
+		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+		require.Equal(t, textHtml, res.Header.Get(contentType))
+		require.Contains(t, w.Body.String(), fmt.Sprintf(`action="/api/short/%s/unlock"`, shortUrl))
+	})
+
+	t.Run("Correct password header", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		resources.MockDb.EXPECT().GetOriginalUrl("", shortUrl).Times(1).Return(mockUrlRes, nil)
+		resources.MockDb.EXPECT().CountPasswordAttempt("", shortUrl, maxFailedPasswordAttempts, testNow, passwordLockoutDuration).Times(1).Return(time.Time{}, nil)
+		resources.MockDb.EXPECT().ResetPasswordLockout("", shortUrl).Times(1).Return(nil)
+		resources.MockDb.EXPECT().RecordClick("", shortUrl, "").Times(1).Return(1, nil)
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/short/%s", shortUrl), nil)
+		req.Header.Set(HeaderLinkPassword, password)
+		w := httptest.NewRecorder()
+		newRouter().ServeHTTP(w, req)
+
+		res := w.Result()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var responseBody ShortUrlResponse
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&responseBody))
+		require.True(t, responseBody.PasswordProtected)
+	})
+
+	t.Run("Unlock form submission", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		resources.MockDb.EXPECT().GetOriginalUrl("", shortUrl).Times(1).Return(mockUrlRes, nil)
+		resources.MockDb.EXPECT().CountPasswordAttempt("", shortUrl, maxFailedPasswordAttempts, testNow, passwordLockoutDuration).Times(1).Return(time.Time{}, nil)
+		resources.MockDb.EXPECT().ResetPasswordLockout("", shortUrl).Times(1).Return(nil)
+		resources.MockDb.EXPECT().RecordClick("", shortUrl, "").Times(1).Return(1, nil)
+
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/short/%s/unlock", shortUrl), strings.NewReader("password="+password))
+		req.Header.Set(contentType, "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		newRouter().ServeHTTP(w, req)
+
+		res := w.Result()
+		require.Equal(t, http.StatusSeeOther, res.StatusCode)
+		require.Equal(t, mockUrlRes.OriginalUrl, res.Header.Get("Location"))
+	})
+
+	t.Run("Wrong password is counted", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		resources.MockDb.EXPECT().GetOriginalUrl("", shortUrl).Times(1).Return(mockUrlRes, nil)
+		resources.MockDb.EXPECT().CountPasswordAttempt("", shortUrl, maxFailedPasswordAttempts, testNow, passwordLockoutDuration).Times(1).Return(time.Time{}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/short/%s", shortUrl), nil)
+		req.Header.Set(HeaderLinkPassword, "wrong")
+		w := httptest.NewRecorder()
+		newRouter().ServeHTTP(w, req)
+		require.Equal(t, http.StatusUnauthorized, w.Result().StatusCode)
+	})
+
+	t.Run("Locked after repeated wrong passwords", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		resources.MockDb.EXPECT().GetOriginalUrl("", shortUrl).Times(1).Return(mockUrlRes, nil)
+		resources.MockDb.EXPECT().CountPasswordAttempt("", shortUrl, maxFailedPasswordAttempts, testNow, passwordLockoutDuration).Times(1).Return(testNow.Add(time.Minute), nil)
+
+		// Even the correct password is refused while the link is locked
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/short/%s", shortUrl), nil)
+		req.Header.Set(HeaderLinkPassword, password)
+		w := httptest.NewRecorder()
+		newRouter().ServeHTTP(w, req)
+
+		res := w.Result()
+		require.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+		require.Equal(t, "61", res.Header.Get("Retry-After"))
+	})
+
+	t.Run("Password set on update", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		resources.MockDb.EXPECT().GetOriginalUrl("", shortUrl).Times(1).Return(&models.Url{ShortUrl: shortUrl, OriginalUrl: "http://example.com"}, nil)
+		resources.MockDb.EXPECT().UpdateShortUrl(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+			func(url *models.Url, updatedShortUrl string, createdAt time.Time) error {
+				require.NotEmpty(t, url.PasswordHash)
+				return nil
+			})
+
+		req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/short/%s", shortUrl), strings.NewReader(`{"password": "new"}`))
+		w := httptest.NewRecorder()
+		router := mux.NewRouter()
+		router.HandleFunc(endpoint, UpdateShortUrl).Methods("PUT")
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusCreated, w.Result().StatusCode)
+	})
+}
+
+func TestMaxClicksUrl(t *testing.T) {
+	var endpoint = "/api/short/{short_url}"
+	shortUrl := "esd87df7"
+
+	newMockUrl := func(remainingClicks int) *models.Url {
+		maxClicks := 3
+		return &models.Url{
+			ShortUrl:        shortUrl,
+			OriginalUrl:     "http://example.com",
+			CreatedAt:       testNow,
+			MaxClicks:       &maxClicks,
+			RemainingClicks: &remainingClicks,
 		}
 	}
+
+	t.Run("Invalid max clicks", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+
+		req := httptest.NewRequest(http.MethodPost, "/api/short", strings.NewReader(`{"original_url": "http://example.com", "max_clicks": 0}`))
+		w := httptest.NewRecorder()
+		router := mux.NewRouter()
+		router.HandleFunc("/api/short", CreateShortUrl).Methods("POST")
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+
+	t.Run("Click consumed", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		resources.MockDb.EXPECT().GetOriginalUrl("", shortUrl).Times(1).Return(newMockUrl(2), nil)
+		resources.MockDb.EXPECT().ConsumeClick("", shortUrl).Times(1).Return(1, nil)
+		resources.MockDb.EXPECT().RecordClick("", shortUrl, "").Times(1).Return(1, nil)
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/short/%s", shortUrl), nil)
+		w := httptest.NewRecorder()
+		router := mux.NewRouter()
+		router.HandleFunc(endpoint, RedirectUrl).Methods("GET")
+		router.ServeHTTP(w, req)
+
+		res := w.Result()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var responseBody ShortUrlResponse
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&responseBody))
+		require.Equal(t, 1, *responseBody.RemainingClicks)
+		require.Equal(t, 3, *responseBody.MaxClicks)
+	})
+
+	t.Run("Clicks exhausted", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		resources.MockDb.EXPECT().GetOriginalUrl("", shortUrl).Times(1).Return(newMockUrl(0), nil)
+		resources.MockDb.EXPECT().ConsumeClick("", shortUrl).Times(1).Return(0, errors.New(storage.ErrShortURLExhausted))
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/short/%s", shortUrl), nil)
+		w := httptest.NewRecorder()
+		router := mux.NewRouter()
+		router.HandleFunc(endpoint, RedirectUrl).Methods("GET")
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusGone, w.Result().StatusCode)
+	})
+}
+
+func TestRegisterDomain(t *testing.T) {
+	var endPoint = "/api/admin/domains"
+	adminKey := "admin-key"
+
+	newRouter := func() *mux.Router {
+		router := mux.NewRouter()
+		router.HandleFunc(endPoint, AdminOnly(RegisterDomain)).Methods("POST")
+		return router
+	}
+
+	t.Run("Admin key missing", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		SetAdminApiKey(adminKey)
+		defer SetAdminApiKey("")
+
+		req := httptest.NewRequest(http.MethodPost, endPoint, strings.NewReader(`{"name": "sho.rt"}`))
+		w := httptest.NewRecorder()
+		newRouter().ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusUnauthorized, w.Result().StatusCode)
+	})
+
+	t.Run("Admin API disabled", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+
+		req := httptest.NewRequest(http.MethodPost, endPoint, strings.NewReader(`{"name": "sho.rt"}`))
+		req.Header.Set(HeaderAdminKey, adminKey)
+		w := httptest.NewRecorder()
+		newRouter().ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusForbidden, w.Result().StatusCode)
+	})
+
+	t.Run("Invalid parameters", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		SetAdminApiKey(adminKey)
+		defer SetAdminApiKey("")
+
+		for _, body := range []string{`{}`, `{"name": "https://sho.rt"}`, `{"name": "sho.rt", "code_length": 2}`,
+			`{"name": "sho.rt", "redirect_type": 200}`, `{"name": "sho.rt", "fallback_url": "/home"}`} {
+			req := httptest.NewRequest(http.MethodPost, endPoint, strings.NewReader(body))
+			req.Header.Set(HeaderAdminKey, adminKey)
+			w := httptest.NewRecorder()
+			newRouter().ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusBadRequest, w.Result().StatusCode, body)
+		}
+	})
+
+	t.Run("Successful registration with defaults", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		SetAdminApiKey(adminKey)
+		defer SetAdminApiKey("")
+		resources.MockDb.EXPECT().InsertDomain(gomock.Any()).Times(1).Return(nil)
+
+		req := httptest.NewRequest(http.MethodPost, endPoint, strings.NewReader(`{"name": "Sho.RT"}`))
+		req.Header.Set("Authorization", "Bearer "+adminKey)
+		w := httptest.NewRecorder()
+		newRouter().ServeHTTP(w, req)
+
+		res := w.Result()
+		require.Equal(t, http.StatusCreated, res.StatusCode)
+		var responseBody models.Domain
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&responseBody))
+		require.Equal(t, "sho.rt", responseBody.Name)
+		require.Equal(t, service.DefaultCodeLength, responseBody.CodeLength)
+		require.Equal(t, http.StatusFound, responseBody.RedirectType)
+	})
+}
+
+func TestRedirectUrlOnRegisteredDomain(t *testing.T) {
+	var endpoint = "/{short_url}"
+	domain := &models.Domain{Name: "sho.rt", CodeLength: 6, RedirectType: http.StatusMovedPermanently, FallbackUrl: "https://sho.rt/home"}
+
+	t.Run("Redirect to original url", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		resources.MockDb.EXPECT().GetDomain("sho.rt").Times(1).Return(domain, nil)
+		resources.MockDb.EXPECT().GetOriginalUrl("sho.rt", "abc123").Times(1).Return(&models.Url{
+			Domain: "sho.rt", ShortUrl: "abc123", OriginalUrl: "http://example.com/page",
+		}, nil)
+		resources.MockDb.EXPECT().RecordClick("sho.rt", "abc123", "").Times(1).Return(1, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "http://SHO.RT:8080/abc123", nil)
+		w := httptest.NewRecorder()
+		router := mux.NewRouter()
+		router.HandleFunc(endpoint, RedirectUrl).Methods("GET")
+		router.ServeHTTP(w, req)
+
+		res := w.Result()
+		require.Equal(t, http.StatusMovedPermanently, res.StatusCode)
+		require.Equal(t, "http://example.com/page", res.Header.Get("Location"))
+	})
+
+	t.Run("Unknown short url goes to fallback", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		resources.MockDb.EXPECT().GetDomain("sho.rt").Times(1).Return(domain, nil)
+		resources.MockDb.EXPECT().GetOriginalUrl("sho.rt", "zzz999").Times(1).Return(nil, errors.New(storage.ErrShortURLDoesNotExist))
+
+		req := httptest.NewRequest(http.MethodGet, "http://sho.rt/zzz999", nil)
+		w := httptest.NewRecorder()
+		router := mux.NewRouter()
+		router.HandleFunc(endpoint, RedirectUrl).Methods("GET")
+		router.ServeHTTP(w, req)
+
+		res := w.Result()
+		require.Equal(t, http.StatusMovedPermanently, res.StatusCode)
+		require.Equal(t, domain.FallbackUrl, res.Header.Get("Location"))
+	})
+}
+
+func TestUrlDetails(t *testing.T) {
+	var endpoint = "/api/short/{short_url}"
+	shortUrl := "esd87df7"
+
+	t.Run("Invalid tags on create", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+
+		for _, body := range []string{`{"original_url": "http://example.com", "tags": [" "]}`, `{"original_url": "http://example.com", "tags": ["a,b"]}`} {
+			req := httptest.NewRequest(http.MethodPost, "/api/short", strings.NewReader(body))
+			w := httptest.NewRecorder()
+			router := mux.NewRouter()
+			router.HandleFunc("/api/short", CreateShortUrl).Methods("POST")
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusBadRequest, w.Result().StatusCode, body)
+		}
+	})
+
+	t.Run("Tags normalized on create", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		resources.MockDb.EXPECT().InsertUrl(gomock.Any()).Times(1).DoAndReturn(func(url *models.Url) error {
+			require.Equal(t, []string{"docs", "internal"}, url.Tags)
+			require.Equal(t, "Docs", url.Title)
+			return nil
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/api/short", strings.NewReader(
+			`{"original_url": "http://example.com", "title": "Docs", "tags": ["Internal", "docs", "internal "], "metadata": {"owner": "platform"}}`))
+		w := httptest.NewRecorder()
+		router := mux.NewRouter()
+		router.HandleFunc("/api/short", CreateShortUrl).Methods("POST")
+		router.ServeHTTP(w, req)
+
+		res := w.Result()
+		require.Equal(t, http.StatusCreated, res.StatusCode)
+		var responseBody ShortUrlResponse
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&responseBody))
+		require.Equal(t, map[string]interface{}{"owner": "platform"}, responseBody.Metadata)
+	})
+
+	t.Run("Patch keeps the short url", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		mockUrl := &models.Url{ShortUrl: shortUrl, OriginalUrl: "http://example.com", Title: "Old", Tags: []string{"old"}}
+		resources.MockDb.EXPECT().CheckShortUrlExists("", shortUrl).Times(1).Return(true)
+		resources.MockDb.EXPECT().GetOriginalUrl("", shortUrl).Times(2).Return(mockUrl, nil)
+		resources.MockDb.EXPECT().UpdateUrlDetails(gomock.Any()).Times(1).DoAndReturn(func(url *models.Url) error {
+			require.Equal(t, "Old", url.Title)
+			require.Equal(t, []string{"new"}, url.Tags)
+			return nil
+		})
+
+		req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/short/%s", shortUrl), strings.NewReader(`{"tags": ["NEW"]}`))
+		w := httptest.NewRecorder()
+		router := mux.NewRouter()
+		router.HandleFunc(endpoint, PatchShortUrl).Methods("PATCH")
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	})
+
+	t.Run("Patch unknown short url", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		resources.MockDb.EXPECT().CheckShortUrlExists("", shortUrl).Times(1).Return(false)
+
+		req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/short/%s", shortUrl), strings.NewReader(`{"title": "New"}`))
+		w := httptest.NewRecorder()
+		router := mux.NewRouter()
+		router.HandleFunc(endpoint, PatchShortUrl).Methods("PATCH")
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+}
+
+func TestListShortUrls(t *testing.T) {
+	var endpoint = "/api/short"
+
+	t.Run("Invalid limit", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+
+		req := httptest.NewRequest(http.MethodGet, endpoint+"?limit=0", nil)
+		w := httptest.NewRecorder()
+		router := mux.NewRouter()
+		router.HandleFunc(endpoint, ListShortUrls).Methods("GET")
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+
+	t.Run("Filter by tag", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		resources.MockDb.EXPECT().ListUrls(&storage.UrlFilter{Tag: "docs", Limit: 10, Offset: 20}).Times(1).Return([]models.Url{
+			{ShortUrl: "esd87df7", OriginalUrl: "http://example.com", Tags: []string{"docs"}},
+		}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, endpoint+"?tag=Docs&limit=10&offset=20", nil)
+		w := httptest.NewRecorder()
+		router := mux.NewRouter()
+		router.HandleFunc(endpoint, ListShortUrls).Methods("GET")
+		router.ServeHTTP(w, req)
+
+		res := w.Result()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var responseBody []ShortUrlResponse
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&responseBody))
+		require.Len(t, responseBody, 1)
+		require.Equal(t, []string{"docs"}, responseBody[0].Tags)
+	})
+
+	t.Run("Filter by health", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		health := &models.LinkHealth{Status: models.HealthBroken, StatusCode: 404, CheckedAt: "2024-10-16T21:05:20Z"}
+		resources.MockDb.EXPECT().ListUrls(&storage.UrlFilter{Health: models.HealthBroken, Limit: service.DefaultListLimit}).Times(1).Return([]models.Url{
+			{ShortUrl: "esd87df7", OriginalUrl: "http://example.com/moved", Health: health},
+		}, nil)
+
+		router := mux.NewRouter()
+		router.HandleFunc(endpoint, ListShortUrls).Methods("GET")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, endpoint+"?health=broken", nil))
+		res := w.Result()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var responseBody []ShortUrlResponse
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&responseBody))
+		require.Equal(t, health, responseBody[0].Health)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, endpoint+"?health=rotten", nil))
+		require.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+
+	t.Run("Protected urls hide their destination", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		adminKey := "admin-key"
+		SetAdminApiKey(adminKey)
+		defer SetAdminApiKey("")
+		resources.MockDb.EXPECT().ListUrls(&storage.UrlFilter{Limit: service.DefaultListLimit}).Times(2).DoAndReturn(func(*storage.UrlFilter) ([]models.Url, error) {
+			return []models.Url{
+				{ShortUrl: "esd87df7", OriginalUrl: "http://example.com/secret", PasswordHash: "hash", Page: &models.PageMetadata{Title: "Secret"}},
+				{ShortUrl: "open1234", OriginalUrl: "http://example.com"},
+			}, nil
+		})
+
+		router := mux.NewRouter()
+		router.HandleFunc(endpoint, ListShortUrls).Methods("GET")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, endpoint, nil))
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.NotContains(t, w.Body.String(), "secret")
+		require.NotContains(t, w.Body.String(), "Secret")
+		var responseBody []ShortUrlResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&responseBody))
+		require.True(t, responseBody[0].PasswordProtected)
+		require.Empty(t, responseBody[0].OriginalUrl)
+		require.Equal(t, "http://example.com", responseBody[1].OriginalUrl)
+
+		// Admins see every destination
+		req := httptest.NewRequest(http.MethodGet, endpoint, nil)
+		req.Header.Set(HeaderAdminKey, adminKey)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		responseBody = nil
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&responseBody))
+		require.Equal(t, "http://example.com/secret", responseBody[0].OriginalUrl)
+		require.Equal(t, "Secret", responseBody[0].Page.Title)
+	})
+}
+
+func TestDescribeShortUrl(t *testing.T) {
+	var route = "/api/short/{short_url}/info"
+	resources := SetupTestDB(t)
+	defer resources.TearDown()
+	adminKey := "admin-key"
+	SetAdminApiKey(adminKey)
+	defer SetAdminApiKey("")
+	resources.MockDb.EXPECT().GetOriginalUrl("", "missing1").Times(1).Return(nil, errors.New(storage.ErrShortURLDoesNotExist))
+	// Describing a url neither counts a click nor checks the password
+	resources.MockDb.EXPECT().GetOriginalUrl("", "esd87df7").Times(2).DoAndReturn(func(string, string) (*models.Url, error) {
+		return &models.Url{ShortUrl: "esd87df7", OriginalUrl: "http://example.com/secret", PasswordHash: "hash", ClickCount: 3, Version: 2}, nil
+	})
+
+	router := mux.NewRouter()
+	router.HandleFunc(route, DescribeShortUrl).Methods("GET")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/short/missing1/info", nil))
+	require.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/short/esd87df7/info", nil))
+	res := w.Result()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.True(t, strings.HasPrefix(res.Header.Get(headerETag), `"2-`))
+	require.NotContains(t, w.Body.String(), "secret")
+	var responseBody ShortUrlResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&responseBody))
+	require.True(t, responseBody.PasswordProtected)
+	require.Empty(t, responseBody.OriginalUrl)
+	require.Equal(t, 3, responseBody.ClickCount)
+
+	// Admins see where the url leads
+	req := httptest.NewRequest(http.MethodGet, "/api/short/esd87df7/info", nil)
+	req.Header.Set(HeaderAdminKey, adminKey)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	responseBody = ShortUrlResponse{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&responseBody))
+	require.Equal(t, "http://example.com/secret", responseBody.OriginalUrl)
+}
+
+func TestPreviewUrl(t *testing.T) {
+	shortUrl := "esd87df7"
+	mockUrlRes := &models.Url{
+		ShortUrl:    shortUrl,
+		OriginalUrl: "http://example.com/?a=1&b=<2>",
+		CreatedAt:   testNow,
+		ClickCount:  42,
+		Page:        &models.PageMetadata{Title: "Example Domain", FetchedAt: "2024-10-16T21:05:20Z"},
+	}
+
+	newRouter := func() *mux.Router {
+		router := mux.NewRouter()
+		router.HandleFunc("/{short_url}"+PreviewSuffix, PreviewUrl).Methods("GET")
+		router.HandleFunc("/{short_url}", RedirectUrl).Methods("GET")
+		return router
+	}
+
+	t.Run("Preview with plus suffix", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		SetBrandName("Acme Links")
+		defer SetBrandName("")
+		resources.MockDb.EXPECT().GetOriginalUrl("", shortUrl).Times(1).Return(mockUrlRes, nil)
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/%s+", shortUrl), nil)
+		w := httptest.NewRecorder()
+		newRouter().ServeHTTP(w, req)
+
+		res := w.Result()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, textHtml, res.Header.Get(contentType))
+		body := w.Body.String()
+		require.Contains(t, body, "<header>Acme Links</header>")
+		require.Contains(t, body, "Example Domain")
+		require.Contains(t, body, "2024-10-16 21:05:18 UTC")
+		require.Contains(t, body, "<dd>42</dd>")
+		// The destination is escaped
+		require.Contains(t, body, "http://example.com/?a=1&amp;b=&lt;2&gt;")
+		require.NotContains(t, body, "<2>")
+	})
+
+	t.Run("Preview with query parameter doesn't count a click", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		resources.MockDb.EXPECT().GetOriginalUrl("", shortUrl).Times(1).Return(mockUrlRes, nil)
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/%s?preview=1", shortUrl), nil)
+		w := httptest.NewRecorder()
+		newRouter().ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.Contains(t, w.Body.String(), "<header>"+defaultBrandName+"</header>")
+	})
+
+	t.Run("Fetched page title is preferred", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		titled := *mockUrlRes
+		titled.Title = "Launch campaign"
+		untitled := titled
+		untitled.Page = &models.PageMetadata{FetchError: "timeout"}
+		resources.MockDb.EXPECT().GetOriginalUrl("", shortUrl).Times(1).Return(&titled, nil)
+		resources.MockDb.EXPECT().GetOriginalUrl("", "untitled").Times(1).Return(&untitled, nil)
+
+		w := httptest.NewRecorder()
+		newRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/%s+", shortUrl), nil))
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.Contains(t, w.Body.String(), "Example Domain")
+		require.NotContains(t, w.Body.String(), "Launch campaign")
+
+		// The title of the url is shown until the page has one
+		w = httptest.NewRecorder()
+		newRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/untitled+", nil))
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.Contains(t, w.Body.String(), "Launch campaign")
+	})
+
+	t.Run("Unknown short url", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		resources.MockDb.EXPECT().GetOriginalUrl("", shortUrl).Times(1).Return(nil, errors.New(storage.ErrShortURLDoesNotExist))
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/%s+", shortUrl), nil)
+		w := httptest.NewRecorder()
+		newRouter().ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+}
+
+func TestDestinationUrl(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      models.Url
+		query    string
+		expected string
+	}{
+		{
+			name:     "Nothing to merge",
+			url:      models.Url{OriginalUrl: "http://example.com/page?b=2&a=1"},
+			query:    "ref=x",
+			expected: "http://example.com/page?b=2&a=1",
+		},
+		{
+			name:     "Utm tags replace the original ones",
+			url:      models.Url{OriginalUrl: "http://example.com/page?utm_source=old&b=2#top", Utm: &models.UtmParams{Source: "news letter", Campaign: "fall"}},
+			expected: "http://example.com/page?b=2&utm_campaign=fall&utm_source=news+letter#top",
+		},
+		{
+			name:     "Forwarded parameters don't override the destination",
+			url:      models.Url{OriginalUrl: "http://example.com/?ref=site", Utm: &models.UtmParams{Medium: "email"}, ForwardQuery: true},
+			query:    "ref=x&utm_medium=social&q=a%26b&q=c&preview=0",
+			expected: "http://example.com/?ref=site&q=a%26b&q=c&utm_medium=email",
+		},
+		{
+			name:     "Query forwarded without an original query",
+			url:      models.Url{OriginalUrl: "http://example.com/page", ForwardQuery: true},
+			query:    "ref=x",
+			expected: "http://example.com/page?ref=x",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/abc123?"+test.query, nil)
+			require.Equal(t, test.expected, destinationUrl(&test.url, test.url.OriginalUrl, req))
+		})
+	}
+}
+
+func TestRedirectUrlWithUtmTags(t *testing.T) {
+	domain := &models.Domain{Name: "sho.rt", CodeLength: 6, RedirectType: http.StatusFound}
+
+	t.Run("Invalid utm tags", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+
+		body := fmt.Sprintf(`{"original_url": "http://example.com", "utm": {"source": "%s"}}`, strings.Repeat("x", 201))
+		req := httptest.NewRequest(http.MethodPost, "/api/short", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		CreateShortUrl(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+
+	t.Run("Redirect merges tags and query", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		resources.MockDb.EXPECT().GetDomain("sho.rt").Times(1).Return(domain, nil)
+		resources.MockDb.EXPECT().GetOriginalUrl("sho.rt", "abc123").Times(1).Return(&models.Url{
+			Domain: "sho.rt", ShortUrl: "abc123", OriginalUrl: "http://example.com/page",
+			Utm: &models.UtmParams{Source: "qr"}, ForwardQuery: true,
+		}, nil)
+		resources.MockDb.EXPECT().RecordClick("sho.rt", "abc123", "").Times(1).Return(1, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "http://sho.rt/abc123?ref=x", nil)
+		w := httptest.NewRecorder()
+		router := mux.NewRouter()
+		router.HandleFunc("/{short_url}", RedirectUrl).Methods("GET")
+		router.ServeHTTP(w, req)
+
+		res := w.Result()
+		require.Equal(t, http.StatusFound, res.StatusCode)
+		require.Equal(t, "http://example.com/page?ref=x&utm_source=qr", res.Header.Get("Location"))
+	})
+}
+
+func TestTargetingRules(t *testing.T) {
+	geoDb, err := geoip.Load(strings.NewReader("8.8.8.0,8.8.8.255,US\n"))
+	require.NoError(t, err)
+	SetGeoDatabase(geoDb)
+	defer SetGeoDatabase(nil)
+
+	url := &models.Url{
+		OriginalUrl: "https://example.com",
+		Rules: []models.TargetingRule{
+			{Platforms: []string{"ios"}, Destination: "https://apps.apple.com/app/id1"},
+			{Platforms: []string{"android"}, Destination: "https://play.google.com/store/apps/details?id=app"},
+			{Languages: []string{"de"}, Countries: []string{"US"}, Destination: "https://example.com/de-us"},
+			{StartsAt: "2000-01-01T00:00:00Z", EndsAt: "2000-01-02T00:00:00Z", Destination: "https://example.com/expired"},
+		},
+	}
+	tests := []struct {
+		name           string
+		userAgent      string
+		acceptLanguage string
+		remoteAddr     string
+		expected       string
+	}{
+		{"iOS", "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X)", "", "10.0.0.1:1234", "https://apps.apple.com/app/id1"},
+		{"Android", "Mozilla/5.0 (Linux; Android 14; Pixel 8)", "", "10.0.0.1:1234", "https://play.google.com/store/apps/details?id=app"},
+		{"Language and country", "Mozilla/5.0 (X11; Linux x86_64)", "en;q=0.5, de-AT", "8.8.8.8:1234", "https://example.com/de-us"},
+		{"Language without the country", "Mozilla/5.0 (X11; Linux x86_64)", "de", "10.0.0.1:1234", "https://example.com"},
+		{"Fallback to the original url", "curl/8.0", "", "8.8.8.8:1234", "https://example.com"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/abc123", nil)
+			req.Header.Set("User-Agent", test.userAgent)
+			req.Header.Set("Accept-Language", test.acceptLanguage)
+			req.RemoteAddr = test.remoteAddr
+			target, _ := pickDestination(nil, req, url)
+			require.Equal(t, test.expected, target)
+		})
+	}
+
+	t.Run("Time window", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/abc123", nil)
+		now := time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC)
+		target, ok := targetDestination(url, req, now)
+		require.True(t, ok)
+		require.Equal(t, "https://example.com/expired", target)
+
+		// Followed links check the window on the clock of the handlers
+		SetClock(fixedClock(now))
+		defer SetClock(fixedClock(testNow))
+		target, _ = pickDestination(nil, req, url)
+		require.Equal(t, "https://example.com/expired", target)
+	})
+
+	t.Run("Invalid rules on create", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+
+		for _, rules := range []string{
+			`[{"destination": ""}]`,
+			`[{"platforms": ["beos"], "destination": "https://example.com"}]`,
+			`[{"countries": ["USA"], "destination": "https://example.com"}]`,
+			`[{"starts_at": "2000-01-02T00:00:00Z", "ends_at": "2000-01-01T00:00:00Z", "destination": "https://example.com"}]`,
+			`[{"starts_at": "2000-01-01 00:00:00", "destination": "https://example.com"}]`,
+		} {
+			body := fmt.Sprintf(`{"original_url": "http://example.com", "rules": %s}`, rules)
+			req := httptest.NewRequest(http.MethodPost, "/api/short", strings.NewReader(body))
+			w := httptest.NewRecorder()
+			CreateShortUrl(w, req)
+			require.Equal(t, http.StatusBadRequest, w.Result().StatusCode, rules)
+		}
+	})
+}
+
+func TestVariants(t *testing.T) {
+	url := &models.Url{
+		ShortUrl:    "abc123",
+		OriginalUrl: "https://example.com",
+		VariantMode: models.VariantModeSticky,
+		Variants: []models.Variant{
+			{Name: "A", Destination: "https://example.com/a", Weight: 0},
+			{Name: "B", Destination: "https://example.com/b", Weight: 1},
+		},
+	}
+
+	t.Run("Variants without weight are never served", func(t *testing.T) {
+		for i := 0; i < 20; i++ {
+			require.Equal(t, "B", chooseVariant(url.Variants).Name)
+		}
+	})
+
+	t.Run("Sticky variant remembered in a cookie", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/abc123", nil)
+		w := httptest.NewRecorder()
+		target, variant := pickDestination(w, req, url)
+		require.Equal(t, "https://example.com/b", target)
+		require.Equal(t, "B", variant)
+		cookies := w.Result().Cookies()
+		require.Len(t, cookies, 1)
+		require.Equal(t, variantCookiePrefix+"abc123", cookies[0].Name)
+
+		// A cookie naming a variant that can't be served anymore is replaced
+		req = httptest.NewRequest(http.MethodGet, "/abc123", nil)
+		req.AddCookie(&http.Cookie{Name: variantCookiePrefix + "abc123", Value: "A"})
+		_, variant = pickDestination(httptest.NewRecorder(), req, url)
+		require.Equal(t, "B", variant)
+	})
+
+	t.Run("Invalid variants on create", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+
+		for _, variants := range []string{
+			`"variants": [{"destination": "https://example.com/a", "weight": 0}]`,
+			`"variants": [{"destination": "https://example.com/a", "weight": -1}]`,
+			`"variants": [{"name": "A", "destination": "https://example.com/a", "weight": 1}, {"name": "A", "destination": "https://example.com/b", "weight": 1}]`,
+			`"variants": [{"destination": "", "weight": 1}]`,
+			`"variant_mode": "roundrobin"`,
+		} {
+			body := fmt.Sprintf(`{"original_url": "http://example.com", %s}`, variants)
+			req := httptest.NewRequest(http.MethodPost, "/api/short", strings.NewReader(body))
+			w := httptest.NewRecorder()
+			CreateShortUrl(w, req)
+			require.Equal(t, http.StatusBadRequest, w.Result().StatusCode, variants)
+		}
+	})
+}
+
+func TestCsvImportReader(t *testing.T) {
+	t.Run("Header", func(t *testing.T) {
+		_, err := newCsvImportReader(strings.NewReader("\ufeffShort_Url, original_url\n"))
+		require.NoError(t, err)
+		_, err = newCsvImportReader(strings.NewReader("short_url,url\n"))
+		require.EqualError(t, err, `Unknown CSV column "url"`)
+		_, err = newCsvImportReader(strings.NewReader("short_url,title\n"))
+		require.Error(t, err)
+		_, err = newCsvImportReader(strings.NewReader(""))
+		require.Error(t, err)
+	})
+
+	t.Run("Rows", func(t *testing.T) {
+		reader, err := newCsvImportReader(strings.NewReader("original_url,tags,max_clicks,utm_source,variants,forward_query\n" +
+			`https://example.com,"docs,go",5,mail,"[{""name"": ""A"", ""destination"": ""https://example.com/a"", ""weight"": 1, ""click_count"": 3}]",true` + "\n" +
+			"https://example.com/2,,many,,,\n" +
+			"https://example.com/3,,,,,,extra\n" +
+			"https://example.com/4\n"))
+		require.NoError(t, err)
+
+		line, record, err := reader.next()
+		require.NoError(t, err)
+		require.Equal(t, 2, line)
+		require.Equal(t, "https://example.com", record.OriginalUrl)
+		require.Equal(t, []string{"docs", "go"}, *record.Tags)
+		require.Equal(t, 5, *record.MaxClicks)
+		require.Equal(t, "mail", record.Utm.Source)
+		require.Equal(t, 3, (*record.Variants)[0].ClickCount)
+		require.True(t, record.ForwardQuery)
+
+		line, _, err = reader.next()
+		require.Equal(t, 3, line)
+		require.EqualError(t, err, `Invalid max_clicks "many"`)
+		line, _, err = reader.next()
+		require.Equal(t, 4, line)
+		require.Error(t, err)
+
+		// Missing trailing fields are left unset
+		line, record, err = reader.next()
+		require.NoError(t, err)
+		require.Equal(t, 5, line)
+		require.Nil(t, record.Tags)
+
+		_, _, err = reader.next()
+		require.ErrorIs(t, err, io.EOF)
+	})
+}
+
+func TestDisabledUrl(t *testing.T) {
+	endpoint := "/api/short/{short_url}"
+	disabled := &models.Url{
+		ShortUrl:     "esd87df7",
+		OriginalUrl:  "http://example.com",
+		CreatedAt:    testNow,
+		Status:       models.StatusDisabled,
+		StatusReason: "Under investigation",
+	}
+	follow := func(accept string) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, "/api/short/esd87df7", nil)
+		req.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		router := mux.NewRouter()
+		router.HandleFunc(endpoint, RedirectUrl)
+		router.ServeHTTP(w, req)
+		return w.Result()
+	}
+
+	t.Run("API clients get an error", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		resources.MockDb.EXPECT().GetOriginalUrl("", "esd87df7").Times(1).Return(disabled, nil)
+
+		res := follow("application/json")
+		require.Equal(t, http.StatusUnavailableForLegalReasons, res.StatusCode)
+		var response ErrorResponse
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&response))
+		require.Equal(t, ErrUrlDisabled, response.Error)
+	})
+
+	t.Run("Browsers get the disabled page", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		resources.MockDb.EXPECT().GetOriginalUrl("", "esd87df7").Times(1).Return(disabled, nil)
+
+		res := follow("text/html")
+		require.Equal(t, http.StatusUnavailableForLegalReasons, res.StatusCode)
+		body, _ := io.ReadAll(res.Body)
+		require.Contains(t, string(body), "Under investigation")
+		require.NotContains(t, string(body), "http://example.com")
+	})
+
+	t.Run("Configured status code and page", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		resources.MockDb.EXPECT().GetOriginalUrl("", "esd87df7").Times(1).Return(disabled, nil)
+
+		pagePath := t.TempDir() + "/disabled.html"
+		require.NoError(t, os.WriteFile(pagePath, []byte(`<p>{{.ShortUrl}} is gone: {{.Reason}}</p>`), 0o644))
+		defaultPage := disabledTemplate
+		require.NoError(t, SetDisabledResponse(http.StatusForbidden, pagePath))
+		defer func() {
+			disabledStatusCode, disabledTemplate = http.StatusUnavailableForLegalReasons, defaultPage
+		}()
+
+		res := follow("text/html")
+		require.Equal(t, http.StatusForbidden, res.StatusCode)
+		body, _ := io.ReadAll(res.Body)
+		require.Equal(t, "<p>esd87df7 is gone: Under investigation</p>", string(body))
+
+		require.Error(t, SetDisabledResponse(http.StatusNotFound, ""))
+		require.Error(t, SetDisabledResponse(http.StatusForbidden, pagePath+".missing"))
+	})
+}
+
+func TestSetShortUrlStatus(t *testing.T) {
+	endpoint := "/api/short/{short_url}/status"
+	send := func(shortUrl string, body string) *http.Response {
+		req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/short/%s/status", shortUrl), bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		router := mux.NewRouter()
+		router.HandleFunc(endpoint, SetShortUrlStatus)
+		router.ServeHTTP(w, req)
+		return w.Result()
+	}
+
+	t.Run("Disable a url", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		resources.MockDb.EXPECT().SetUrlStatus("", "esd87df7", models.StatusDisabled, "Reported as spam").Times(1).Return(nil)
+		resources.MockDb.EXPECT().GetOriginalUrl("", "esd87df7").Times(1).Return(&models.Url{
+			ShortUrl: "esd87df7", OriginalUrl: "http://example.com", Status: models.StatusDisabled, StatusReason: "Reported as spam"}, nil)
+
+		res := send("esd87df7", `{"status": "disabled", "reason": "Reported as spam"}`)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var response ShortUrlResponse
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&response))
+		require.Equal(t, models.StatusDisabled, response.Status)
+		require.Equal(t, "Reported as spam", response.StatusReason)
+	})
+
+	t.Run("Reactivating drops the reason", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		resources.MockDb.EXPECT().SetUrlStatus("", "esd87df7", models.StatusActive, "").Times(1).Return(nil)
+		resources.MockDb.EXPECT().GetOriginalUrl("", "esd87df7").Times(1).Return(&models.Url{
+			ShortUrl: "esd87df7", OriginalUrl: "http://example.com", Status: models.StatusActive}, nil)
+
+		res := send("esd87df7", `{"status": "active", "reason": "Looked fine"}`)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+	})
+
+	t.Run("Invalid status", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+
+		res := send("esd87df7", `{"status": "paused"}`)
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("Unknown url", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		resources.MockDb.EXPECT().SetUrlStatus("", "missing", models.StatusDisabled, "").Times(1).
+			Return(errors.New(storage.ErrShortURLDoesNotExist))
+
+		res := send("missing", `{"status": "disabled"}`)
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+}
+
+func TestReportShortUrl(t *testing.T) {
+	endpoint := "/api/short/{short_url}/report"
+	send := func(shortUrl string, body string) *http.Response {
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/short/%s/report", shortUrl), bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		router := mux.NewRouter()
+		router.HandleFunc(endpoint, ReportShortUrl)
+		router.ServeHTTP(w, req)
+		return w.Result()
+	}
+
+	t.Run("Report is queued", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		resources.MockDb.EXPECT().CheckShortUrlExists("", "esd87df7").Times(1).Return(true)
+		resources.MockDb.EXPECT().InsertReport(gomock.Any()).Times(1).DoAndReturn(func(report *models.Report) error {
+			require.Equal(t, "phishing", report.Reason)
+			require.Equal(t, "Asks for my bank login", report.Details)
+			require.Equal(t, "2024-10-16T21:05:18Z", report.CreatedAt)
+			report.Id = 7
+			return nil
+		})
+
+		res := send("esd87df7", `{"reason": "phishing", "details": " Asks for my bank login ", "email": "me@example.com"}`)
+		require.Equal(t, http.StatusAccepted, res.StatusCode)
+		var report models.Report
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&report))
+		require.Equal(t, int64(7), report.Id)
+		require.Equal(t, "me@example.com", report.Email)
+	})
+
+	t.Run("Unknown url", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		resources.MockDb.EXPECT().CheckShortUrlExists("", "missing").Times(1).Return(false)
+
+		res := send("missing", `{"reason": "spam"}`)
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("Invalid reports", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+
+		for _, body := range []string{
+			`{"reason": "boring"}`,
+			`{"reason": "spam", "email": "Me <me@example.com>"}`,
+			fmt.Sprintf(`{"reason": "spam", "details": %q}`, strings.Repeat("a", maxReportDetails+1)),
+			`not json`,
+		} {
+			res := send("esd87df7", body)
+			require.Equal(t, http.StatusBadRequest, res.StatusCode, body)
+		}
+	})
+
+	t.Run("Oversized body", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+
+		// The body is refused while it is read, before the url is looked up
+		res := send("esd87df7", fmt.Sprintf(`{"reason": "spam", "details": %q}`, strings.Repeat("a", maxReportBody)))
+		require.Equal(t, http.StatusRequestEntityTooLarge, res.StatusCode)
+	})
+}
+
+func TestCreateWebhook(t *testing.T) {
+	send := func(body string) *http.Response {
+		req := httptest.NewRequest(http.MethodPost, "/api/admin/webhooks", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		CreateWebhook(w, req)
+		return w.Result()
+	}
+
+	t.Run("Webhook is created with a generated secret", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		resources.MockDb.EXPECT().InsertWebhook(gomock.Any()).Times(1).DoAndReturn(func(hook *models.Webhook) error {
+			require.Equal(t, []int{10, 100}, hook.ClickThresholds)
+			require.Len(t, hook.Secret, 64)
+			require.Equal(t, "2024-10-16T21:05:18Z", hook.CreatedAt)
+			hook.Id = 3
+			return nil
+		})
+
+		res := send(`{"url": "https://hooks.example.com", "events": ["link.created", "link.click_threshold"], "click_thresholds": [100, 10]}`)
+		require.Equal(t, http.StatusCreated, res.StatusCode)
+		var created CreateWebhookResponse
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&created))
+		require.Equal(t, int64(3), created.Id)
+		require.Len(t, created.Secret, 64)
+	})
+
+	t.Run("Invalid webhooks", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+
+		for _, body := range []string{
+			`{"url": "ftp://hooks.example.com", "events": ["link.created"]}`,
+			`{"url": "https://hooks.example.com", "events": []}`,
+			`{"url": "https://hooks.example.com", "events": ["link.visited"]}`,
+			`{"url": "https://hooks.example.com", "events": ["link.created", "link.created"]}`,
+			`{"url": "https://hooks.example.com", "events": ["link.click_threshold"]}`,
+			`{"url": "https://hooks.example.com", "events": ["link.created"], "click_thresholds": [10]}`,
+			`{"url": "https://hooks.example.com", "events": ["link.click_threshold"], "click_thresholds": [0]}`,
+			`{"url": "https://hooks.example.com", "events": ["link.created"], "secret": "short"}`,
+			`not json`,
+		} {
+			res := send(body)
+			require.Equal(t, http.StatusBadRequest, res.StatusCode, body)
+		}
+	})
+}
+
+func TestRetryDeadLetter(t *testing.T) {
+	send := func(id string) *http.Response {
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/admin/webhooks/dead-letters/%s/retry", id), nil)
+		w := httptest.NewRecorder()
+		router := mux.NewRouter()
+		router.HandleFunc("/api/admin/webhooks/dead-letters/{dead_letter_id}/retry", RetryDeadLetter)
+		router.ServeHTTP(w, req)
+		return w.Result()
+	}
+
+	t.Run("Dead letter is queued", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		resources.MockDb.EXPECT().RetryDeadLetter(int64(4), testNow).Times(1).Return(nil)
+
+		res := send("4")
+		require.Equal(t, http.StatusAccepted, res.StatusCode)
+	})
+
+	t.Run("Unknown dead letter", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		resources.MockDb.EXPECT().RetryDeadLetter(int64(5), gomock.Any()).Times(1).Return(errors.New(storage.ErrDeadLetterDoesNotExist))
+
+		res := send("5")
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("Invalid id", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+
+		res := send("first")
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+}
+
+func TestCheckSelfLink(t *testing.T) {
+	resources := SetupTestDB(t)
+	defer resources.TearDown()
+	SetPublicBaseUrl("https://go.example.net")
+	defer SetPublicBaseUrl("")
+	resources.MockDb.EXPECT().GetDomain(gomock.Any()).AnyTimes().DoAndReturn(func(name string) (*models.Domain, error) {
+		if name == "sho.rt" {
+			return &models.Domain{Name: "sho.rt"}, nil
+		}
+		return nil, errors.New(storage.ErrDomainDoesNotExist)
+	})
+	resources.MockDb.EXPECT().CheckShortUrlExists(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(domain string, shortUrl string) bool {
+		return (domain == "" && shortUrl == "esd87df7") || (domain == "sho.rt" && shortUrl == "28b6N")
+	})
+
+	for destination, expected := range map[string]string{
+		"https://example.com/esd87df7":           ErrSelfLink,
+		"http://EXAMPLE.com:8080/esd87df7+":      ErrSelfLink,
+		"https://go.example.net/esd87df7":        ErrSelfLink,
+		"https://sho.rt/28b6N":                   ErrSelfLink,
+		"https://example.com/api/short/esd87df7": ErrSelfRoute,
+		"https://sho.rt/openapi.json":            ErrSelfRoute,
+		"https://example.com/missing1":           "",
+		"https://example.com/":                   "",
+		"https://example.com/blog/esd87df7":      "",
+		"https://example.org/esd87df7":           "",
+		"https://example.org/api/short/esd87df7": "",
+		"https://sho.rt/esd87df7":                "",
+		"not a url":                              "",
+	} {
+		err := checkSelfLink(defaultTestHost, destination)
+		if expected == "" {
+			require.NoError(t, err, destination)
+		} else {
+			require.EqualError(t, err, expected, destination)
+		}
+	}
+}
+
+func TestConditionalRequests(t *testing.T) {
+	endpoint := "/api/short/{short_url}"
+	shortUrl := "esd87df7"
+	newUrl := func() *models.Url {
+		return &models.Url{ShortUrl: shortUrl, OriginalUrl: "http://example.com", CreatedAt: testNow, Version: 3}
+	}
+	serve := func(method string, header string, value string) *http.Response {
+		req := httptest.NewRequest(method, fmt.Sprintf("/api/short/%s", shortUrl), strings.NewReader(`{"title": "Docs"}`))
+		req.Header.Set(header, value)
+		w := httptest.NewRecorder()
+		router := mux.NewRouter()
+		router.HandleFunc(endpoint, RedirectUrl).Methods("GET")
+		router.HandleFunc(endpoint, UpdateShortUrl).Methods("PUT")
+		router.HandleFunc(endpoint, PatchShortUrl).Methods("PATCH")
+		router.HandleFunc(endpoint, DeleteShortUrl).Methods("DELETE")
+		router.ServeHTTP(w, req)
+		return w.Result()
+	}
+
+	t.Run("ETag of the version and clicks", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		clicked := newUrl()
+		clicked.ClickCount = 1
+		gomock.InOrder(
+			resources.MockDb.EXPECT().GetOriginalUrl("", shortUrl).Times(1).Return(newUrl(), nil),
+			resources.MockDb.EXPECT().RecordClick("", shortUrl, "").Times(1).Return(1, nil),
+			resources.MockDb.EXPECT().GetOriginalUrl("", shortUrl).Times(1).Return(clicked, nil),
+			resources.MockDb.EXPECT().GetOriginalUrl("", shortUrl).Times(1).DoAndReturn(func(string, string) (*models.Url, error) {
+				// Someone else followed the url since
+				clicked.ClickCount = 2
+				return clicked, nil
+			}),
+			resources.MockDb.EXPECT().RecordClick("", shortUrl, "").Times(1).Return(3, nil),
+		)
+
+		res := serve(http.MethodGet, "If-None-Match", `"2"`)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		tag := res.Header.Get("ETag")
+		require.True(t, strings.HasPrefix(tag, `"3-`), tag)
+
+		// The current state is not sent again and no click is counted
+		res = serve(http.MethodGet, "If-None-Match", `"2", W/`+tag)
+		require.Equal(t, http.StatusNotModified, res.StatusCode)
+		require.Equal(t, tag, res.Header.Get("ETag"))
+
+		// Clicks don't change the version but do change the ETag
+		res = serve(http.MethodGet, "If-None-Match", tag)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.NotEqual(t, tag, res.Header.Get("ETag"))
+		require.True(t, strings.HasPrefix(res.Header.Get("ETag"), `"3-`))
+	})
+
+	t.Run("If-Match of another version", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		resources.MockDb.EXPECT().CheckShortUrlExists("", shortUrl).Times(1).Return(true)
+		// PATCH and PUT compare the version of the url they read
+		resources.MockDb.EXPECT().GetOriginalUrl("", shortUrl).Times(2).Return(newUrl(), nil)
+		resources.MockDb.EXPECT().DeleteShortUrl("", shortUrl, int64(2)).Times(1).Return(errors.New(storage.ErrVersionMismatch))
+
+		for _, method := range []string{http.MethodPatch, http.MethodPut, http.MethodDelete} {
+			res := serve(method, "If-Match", `"2"`)
+			require.Equal(t, http.StatusPreconditionFailed, res.StatusCode, method)
+		}
+		// Weak ETags never match
+		res := serve(http.MethodPatch, "If-Match", `W/"3"`)
+		require.Equal(t, http.StatusPreconditionFailed, res.StatusCode)
+	})
+
+	t.Run("If-Match of the current version", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		updated := newUrl()
+		updated.Version = 4
+		resources.MockDb.EXPECT().CheckShortUrlExists("", shortUrl).Times(1).Return(true)
+		gomock.InOrder(
+			resources.MockDb.EXPECT().GetOriginalUrl("", shortUrl).Times(1).Return(newUrl(), nil),
+			resources.MockDb.EXPECT().UpdateUrlDetails(gomock.Any()).Times(1).DoAndReturn(func(url *models.Url) error {
+				require.Equal(t, int64(3), url.Version)
+				return nil
+			}),
+			resources.MockDb.EXPECT().GetOriginalUrl("", shortUrl).Times(1).Return(updated, nil),
+		)
+
+		res := serve(http.MethodPatch, "If-Match", `"3-1a2b"`)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, etag(updated), res.Header.Get("ETag"))
+		require.True(t, strings.HasPrefix(res.Header.Get("ETag"), `"4-`))
+	})
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	routePrefix := "/api/short"
+	body := `{"original_url": "http://example.com"}`
+	serve := func(key string, body string) *http.Response {
+		req := httptest.NewRequest(http.MethodPost, routePrefix, strings.NewReader(body))
+		req.Header.Set("Idempotency-Key", key)
+		w := httptest.NewRecorder()
+		router := mux.NewRouter()
+		router.HandleFunc(routePrefix, CreateShortUrl).Methods("POST")
+		router.ServeHTTP(w, req)
+		return w.Result()
+	}
+
+	t.Run("First request", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		resources.MockDb.EXPECT().ReserveIdempotencyKey(gomock.Any(), testNow.Add(-DefaultIdempotencyWindow), testNow.Add(-time.Minute)).Times(1).
+			DoAndReturn(func(key *models.IdempotencyKey, expiredBefore time.Time, abandonedBefore time.Time) (*models.IdempotencyKey, error) {
+				require.Equal(t, "retry-1", key.Key)
+				require.Len(t, key.RequestHash, 64)
+				require.Equal(t, testNow, key.CreatedAt)
+				return nil, nil
+			})
+		resources.MockDb.EXPECT().InsertUrl(gomock.Any()).Times(1).Return(nil)
+		resources.MockDb.EXPECT().SaveIdempotentResponse(gomock.Any()).Times(1).
+			DoAndReturn(func(key *models.IdempotencyKey) error {
+				require.Equal(t, "retry-1", key.Key)
+				require.Equal(t, testNow, key.CreatedAt)
+				require.Equal(t, http.StatusCreated, key.Status)
+				require.Equal(t, []string{"application/json"}, key.Headers["Content-Type"])
+				require.Contains(t, key.Response, `"original_url":"http://example.com"`)
+				return nil
+			})
+
+		res := serve("retry-1", body)
+		require.Equal(t, http.StatusCreated, res.StatusCode)
+		require.Empty(t, res.Header.Get("Idempotent-Replayed"))
+	})
+
+	t.Run("Retry replays the response", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		var requestHash string
+		resources.MockDb.EXPECT().ReserveIdempotencyKey(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).
+			DoAndReturn(func(key *models.IdempotencyKey, expiredBefore time.Time, abandonedBefore time.Time) (*models.IdempotencyKey, error) {
+				if requestHash == "" {
+					requestHash = key.RequestHash
+				}
+				return &models.IdempotencyKey{Key: key.Key, RequestHash: requestHash, Status: http.StatusCreated,
+					Headers:  map[string][]string{"Content-Type": {"application/json"}, "Etag": {`"1-abc"`}, "Location": {"/api/short/esd87df7"}},
+					Response: `{"short_url":"esd87df7"}` + "\n"}, nil
+			})
+
+		res := serve("retry-1", body)
+		require.Equal(t, http.StatusCreated, res.StatusCode)
+		require.Equal(t, "true", res.Header.Get("Idempotent-Replayed"))
+		require.Equal(t, "application/json", res.Header.Get("Content-Type"))
+		require.Equal(t, `"1-abc"`, res.Header.Get("ETag"))
+		require.Equal(t, "/api/short/esd87df7", res.Header.Get("Location"))
+		response, _ := io.ReadAll(res.Body)
+		require.Equal(t, `{"short_url":"esd87df7"}`+"\n", string(response))
+
+		// The same key with another body is rejected
+		res = serve("retry-1", `{"original_url": "http://example.org"}`)
+		require.Equal(t, http.StatusUnprocessableEntity, res.StatusCode)
+	})
+
+	t.Run("Retry while the request is handled", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		resources.MockDb.EXPECT().ReserveIdempotencyKey(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+			DoAndReturn(func(key *models.IdempotencyKey, expiredBefore time.Time, abandonedBefore time.Time) (*models.IdempotencyKey, error) {
+				return &models.IdempotencyKey{Key: key.Key, RequestHash: key.RequestHash}, nil
+			})
+
+		res := serve("retry-1", body)
+		require.Equal(t, http.StatusConflict, res.StatusCode)
+	})
+
+	t.Run("Server errors release the key", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+		resources.MockDb.EXPECT().ReserveIdempotencyKey(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil, nil)
+		resources.MockDb.EXPECT().InsertUrl(gomock.Any()).Times(1).Return(errors.New("disk I/O error"))
+		resources.MockDb.EXPECT().ReleaseIdempotencyKey(gomock.Any()).Times(1).DoAndReturn(func(key *models.IdempotencyKey) error {
+			require.Equal(t, "retry-1", key.Key)
+			require.Equal(t, testNow, key.CreatedAt)
+			return nil
+		})
+
+		res := serve("retry-1", body)
+		require.Equal(t, http.StatusInternalServerError, res.StatusCode)
+	})
+
+	t.Run("Invalid key", func(t *testing.T) {
+		resources := SetupTestDB(t)
+		defer resources.TearDown()
+
+		res := serve(strings.Repeat("k", 256), body)
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
 }
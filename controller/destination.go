@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"net/http"
+	neturl "net/url"
+	"strings"
+
+	"URL_SHORTENER/models"
+)
+
+// reservedQueryParams are read by the shortener itself and never forwarded
+var reservedQueryParams = map[string]bool{
+	queryParamPreview: true,
+}
+
+// destinationUrl builds the url the visitor of the request is sent to from the
+// target chosen by pickDestination. The utm tags of the url replace parameters of
+// the same name in the target. With query forwarding the request parameters are
+// appended, except those the target already sets. The query string of the target
+// is kept as written, new parameters are added in key order.
+func destinationUrl(url *models.Url, target string, r *http.Request) string {
+	var utmParams map[string]string
+	if url.Utm != nil {
+		utmParams = url.Utm.QueryParams()
+	}
+	forwarded := make(neturl.Values)
+	if url.ForwardQuery {
+		for key, values := range r.URL.Query() {
+			if !reservedQueryParams[key] {
+				forwarded[key] = values
+			}
+		}
+	}
+	if len(utmParams) == 0 && len(forwarded) == 0 {
+		return target
+	}
+	destination, err := neturl.Parse(target)
+	if err != nil {
+		return target
+	}
+
+	var pairs []string
+	present := make(map[string]bool)
+	for _, pair := range strings.Split(destination.RawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		key, _, _ := strings.Cut(pair, "=")
+		if unescaped, err := neturl.QueryUnescape(key); err == nil {
+			key = unescaped
+		}
+		if _, ok := utmParams[key]; ok {
+			continue
+		}
+		present[key] = true
+		pairs = append(pairs, pair)
+	}
+	added := make(neturl.Values)
+	for key, value := range utmParams {
+		added.Set(key, value)
+		present[key] = true
+	}
+	for key, values := range forwarded {
+		if !present[key] {
+			added[key] = values
+		}
+	}
+	if encoded := added.Encode(); encoded != "" {
+		pairs = append(pairs, encoded)
+	}
+	destination.RawQuery = strings.Join(pairs, "&")
+	return destination.String()
+}
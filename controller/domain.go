@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"URL_SHORTENER/models"
+	"URL_SHORTENER/service"
+)
+
+const PathParamDomain = "domain"
+
+// RegisterDomainRequestParams is the body of POST /api/admin/domains
+type RegisterDomainRequestParams = service.DomainParams
+
+// resolveDomain returns the registered domain matching the request Host,
+// or the default namespace
+func resolveDomain(r *http.Request) (*models.Domain, error) {
+	return shortener.Domain(r.Host)
+}
+
+// requestDomain resolves the namespace of the request and writes the error response if that fails
+func requestDomain(w http.ResponseWriter, r *http.Request) (*models.Domain, bool) {
+	domain, err := resolveDomain(r)
+	if err != nil {
+		ServerResponse(w, http.StatusInternalServerError, ErrorResponse{Error: "Error resolving domain."})
+		return nil, false
+	}
+	return domain, true
+}
+
+func RegisterDomain(w http.ResponseWriter, r *http.Request) {
+	params := new(RegisterDomainRequestParams)
+	err := json.NewDecoder(r.Body).Decode(params)
+	if err != nil {
+		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: "Invalid request parameters"})
+		return
+	}
+	domain, err := shortener.RegisterDomain(params)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	ServerResponse(w, http.StatusCreated, domain)
+}
+
+func ListDomains(w http.ResponseWriter, r *http.Request) {
+	domains, err := shortener.ListDomains()
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	ServerResponse(w, http.StatusOK, domains)
+}
+
+func DeleteDomain(w http.ResponseWriter, r *http.Request) {
+	name, err := ParsePathParam(r, PathParamDomain)
+	if err != nil {
+		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	if err = shortener.DeleteDomain(name); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	ServerResponse(w, http.StatusOK, "Deletion Successful.")
+}
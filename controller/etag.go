@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"URL_SHORTENER/models"
+	"URL_SHORTENER/storage"
+)
+
+const (
+	headerETag        = "ETag"
+	headerIfMatch     = "If-Match"
+	headerIfNoneMatch = "If-None-Match"
+)
+
+// etag is the entity tag of a url, its version followed by a hash of the url as it is described.
+// Clicks, page fetches and health checks don't change the version but do change the hash.
+func etag(url *models.Url) string {
+	tag := strconv.FormatInt(url.Version, 10)
+	body, err := json.Marshal(toShortUrlResponse(url))
+	if err == nil {
+		hash := fnv.New64a()
+		hash.Write(body)
+		tag += "-" + strconv.FormatUint(hash.Sum64(), 36)
+	}
+	return `"` + tag + `"`
+}
+
+// parseETag returns the version of a strong entity tag, with or without the hash
+func parseETag(tag string) (int64, bool) {
+	if len(tag) < 2 || !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) {
+		return 0, false
+	}
+	value, _, _ := strings.Cut(tag[1:len(tag)-1], "-")
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || version < 1 {
+		return 0, false
+	}
+	return version, true
+}
+
+// requestVersion returns the version If-Match requires, 0 for any. Clicks don't fail it, as only the version is compared.
+// A header no version can match, like a weak ETag, is answered with 412 and false is returned.
+func requestVersion(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	ifMatch := strings.TrimSpace(r.Header.Get(headerIfMatch))
+	if ifMatch == "" || ifMatch == "*" {
+		return 0, true
+	}
+	version, ok := parseETag(ifMatch)
+	if !ok {
+		ServerResponse(w, http.StatusPreconditionFailed, ErrorResponse{Error: storage.ErrVersionMismatch})
+		return 0, false
+	}
+	return version, true
+}
+
+// notModified reports whether the If-None-Match header of the request names the current ETag of the url.
+// The comparison is weak, W/"1-abc" matches "1-abc" too.
+func notModified(r *http.Request, url *models.Url) bool {
+	ifNoneMatch := r.Header.Get(headerIfNoneMatch)
+	if ifNoneMatch == "" {
+		return false
+	}
+	current := etag(url)
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == current {
+			return true
+		}
+	}
+	return false
+}
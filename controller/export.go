@@ -0,0 +1,156 @@
+package controller
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"URL_SHORTENER/models"
+	"URL_SHORTENER/storage"
+)
+
+const (
+	transferFormatCsv    = "csv"
+	transferFormatNdjson = "ndjson"
+	textCsv              = "text/csv"
+	applicationNdjson    = "application/x-ndjson"
+)
+
+// transferColumns are the columns of CSV exports, imports accept them in any order plus password
+var transferColumns = []string{"domain", "short_url", "original_url", "created_at", "password_hash", "max_clicks",
+	"remaining_clicks", "click_count", "title", "description", "tags", "metadata", "utm_source", "utm_medium",
+	"utm_campaign", "utm_term", "utm_content", "forward_query", "rules", "variants", "variant_mode",
+	"status", "status_reason"}
+
+// ExportRecord is one line of an NDJSON export. It carries the password hash
+// so protected urls stay protected when imported elsewhere.
+type ExportRecord struct {
+	ShortUrlResponse
+	PasswordHash string `json:"password_hash,omitempty"`
+}
+
+// transferFormat returns the format named by the format query parameter, else the one of the content type
+func transferFormat(format string, mediaType string) (string, error) {
+	switch format {
+	case transferFormatCsv, transferFormatNdjson:
+		return format, nil
+	case "":
+		if strings.HasPrefix(mediaType, textCsv) {
+			return transferFormatCsv, nil
+		}
+		return transferFormatNdjson, nil
+	}
+	return "", fmt.Errorf("format must be one of %s, %s", transferFormatCsv, transferFormatNdjson)
+}
+
+// ExportShortUrls streams the urls of the request domain as CSV or NDJSON, newest first.
+// The urls are written while they are read, so the export is never held in memory.
+func ExportShortUrls(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	format, err := transferFormat(query.Get("format"), "")
+	if err != nil {
+		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	domain, ok := requestDomain(w, r)
+	if !ok {
+		return
+	}
+
+	var csvWriter *csv.Writer
+	var encoder *json.Encoder
+	started := false
+	// The headers are only written with the first url, so a failing query can still answer with an error
+	start := func() error {
+		started = true
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="short-urls.%s"`, format))
+		if format == transferFormatCsv {
+			SetHeader(w, contentType, textCsv)
+			csvWriter = csv.NewWriter(w)
+			return csvWriter.Write(transferColumns)
+		}
+		SetHeader(w, contentType, applicationNdjson)
+		encoder = json.NewEncoder(w)
+		return nil
+	}
+	err = store.ExportUrls(&storage.UrlFilter{
+		Domain: domain.Name,
+		Tag:    strings.ToLower(strings.TrimSpace(query.Get("tag"))),
+	}, func(url *models.Url) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if format == transferFormatCsv {
+			record, err := urlCsvRecord(url)
+			if err != nil {
+				return err
+			}
+			return csvWriter.Write(record)
+		}
+		return encoder.Encode(ExportRecord{ShortUrlResponse: toShortUrlResponse(url), PasswordHash: url.PasswordHash})
+	})
+	if err == nil && !started {
+		err = start()
+	}
+	if err == nil && csvWriter != nil {
+		csvWriter.Flush()
+		err = csvWriter.Error()
+	}
+	if err != nil {
+		if !started {
+			ServerResponse(w, http.StatusInternalServerError, ErrorResponse{Error: "Error exporting urls."})
+			return
+		}
+		// The status has been sent already, the client sees a truncated export
+		log.Printf("exporting urls of %q failed: %v", domain.Name, err)
+	}
+}
+
+// urlCsvRecord returns the transferColumns of the url. Lists and objects are encoded as JSON, except for the tags.
+func urlCsvRecord(url *models.Url) ([]string, error) {
+	utm := url.Utm
+	if utm == nil {
+		utm = &models.UtmParams{}
+	}
+	metadata, err := csvJson(url.Metadata, len(url.Metadata) == 0)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := csvJson(url.Rules, len(url.Rules) == 0)
+	if err != nil {
+		return nil, err
+	}
+	variants, err := csvJson(url.Variants, len(url.Variants) == 0)
+	if err != nil {
+		return nil, err
+	}
+	return []string{url.Domain, url.ShortUrl, url.OriginalUrl, url.CreatedAt.Format(time.RFC3339), url.PasswordHash, csvInt(url.MaxClicks),
+		csvInt(url.RemainingClicks), strconv.Itoa(url.ClickCount), url.Title, url.Description, strings.Join(url.Tags, ","),
+		metadata, utm.Source, utm.Medium, utm.Campaign, utm.Term, utm.Content, strconv.FormatBool(url.ForwardQuery),
+		rules, variants, url.VariantMode, url.Status, url.StatusReason}, nil
+}
+
+func csvInt(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
+
+func csvJson(value interface{}, empty bool) (string, error) {
+	if empty {
+		return "", nil
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
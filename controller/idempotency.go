@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"URL_SHORTENER/models"
+	"URL_SHORTENER/service"
+)
+
+const (
+	headerIdempotencyKey      = "Idempotency-Key"
+	headerIdempotentReplayed  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	DefaultIdempotencyWindow  = 24 * time.Hour
+	ErrIdempotencyKeyInvalid  = "The Idempotency-Key header must be 1 to 255 printable ASCII characters."
+	ErrIdempotencyKeyReused   = "The Idempotency-Key was already used for a different request."
+	ErrIdempotencyKeyInFlight = "A request with the same Idempotency-Key is still being handled, retry it later."
+)
+
+// idempotencyWindow is how long the response of a request sent with an Idempotency-Key is replayed
+var idempotencyWindow = DefaultIdempotencyWindow
+
+// idempotencyReservationTimeout is how long a key waits for the response of its request. A request still
+// without one, like that of a crashed process, no longer holds the key and a retry is handled again.
+var idempotencyReservationTimeout = time.Minute
+
+// SetIdempotencyWindow sets how long idempotency keys are kept
+func SetIdempotencyWindow(window time.Duration) {
+	idempotencyWindow = window
+}
+
+// replayedHeaders are the response headers stored with an idempotent response and sent again with it
+var replayedHeaders = []string{contentType, headerETag, "Location"}
+
+// responseRecorder passes a response on while keeping a copy of its status, headers and body
+type responseRecorder struct {
+	http.ResponseWriter
+	status  int
+	headers map[string][]string
+	body    bytes.Buffer
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	rec.status = status
+	rec.headers = make(map[string][]string)
+	for _, name := range replayedHeaders {
+		if values := rec.Header().Values(name); len(values) > 0 {
+			rec.headers[name] = values
+		}
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.WriteHeader(http.StatusOK)
+	}
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
+
+// validIdempotencyKey reports whether the key is made of visible ASCII characters and isn't too long
+func validIdempotencyKey(key string) bool {
+	if key == "" || len(key) > maxIdempotencyKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// idempotent handles a request sent with an Idempotency-Key once. Retries with the same key and body
+// get the stored response again, with the Idempotent-Replayed header set, until the window has passed.
+// Reusing the key for another request gets a 422 response, a retry while the first request is still
+// being handled a 409, until the reservation times out. Server errors aren't kept, so the request can be
+// retried with the same key.
+func idempotent(w http.ResponseWriter, r *http.Request, handler http.HandlerFunc) {
+	key := r.Header.Get(headerIdempotencyKey)
+	if key == "" {
+		handler(w, r)
+		return
+	}
+	if !validIdempotencyKey(key) {
+		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: ErrIdempotencyKeyInvalid})
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: "Invalid request parameters"})
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	// The key only replays requests to the same route and namespace with the same body
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.Path + "\n" + service.NormalizeHost(r.Host) + "\n"))
+	hash.Write(body)
+	now := clock.Now().UTC()
+	reserved := &models.IdempotencyKey{Key: key, RequestHash: hex.EncodeToString(hash.Sum(nil)), CreatedAt: now}
+	existing, err := store.ReserveIdempotencyKey(reserved, now.Add(-idempotencyWindow), now.Add(-idempotencyReservationTimeout))
+	if err != nil {
+		ServerResponse(w, http.StatusInternalServerError, ErrorResponse{Error: "Error reserving the Idempotency-Key."})
+		return
+	}
+	switch {
+	case existing == nil:
+	case existing.RequestHash != reserved.RequestHash:
+		ServerResponse(w, http.StatusUnprocessableEntity, ErrorResponse{Error: ErrIdempotencyKeyReused})
+		return
+	case existing.Status == 0:
+		ServerResponse(w, http.StatusConflict, ErrorResponse{Error: ErrIdempotencyKeyInFlight})
+		return
+	default:
+		// Keys stored before their headers were get the content type of every response
+		if len(existing.Headers) == 0 {
+			SetHeader(w, contentType, applicationJson)
+		}
+		for name, values := range existing.Headers {
+			for _, value := range values {
+				w.Header().Add(name, value)
+			}
+		}
+		SetHeader(w, headerIdempotentReplayed, "true")
+		w.WriteHeader(existing.Status)
+		_, _ = io.WriteString(w, existing.Response)
+		return
+	}
+
+	recorder := &responseRecorder{ResponseWriter: w}
+	handler(recorder, r)
+	if recorder.status >= http.StatusInternalServerError {
+		err = store.ReleaseIdempotencyKey(reserved)
+	} else {
+		reserved.Status = recorder.status
+		reserved.Headers = recorder.headers
+		reserved.Response = recorder.body.String()
+		err = store.SaveIdempotentResponse(reserved)
+	}
+	if err != nil {
+		log.Printf("storing the response of idempotency key %q: %v", key, err)
+	}
+}
@@ -0,0 +1,299 @@
+package controller
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"URL_SHORTENER/models"
+	"URL_SHORTENER/service"
+)
+
+const (
+	// maxImportResults bounds the rows listed in the import response, the counts are always complete
+	maxImportResults = 1000
+	// maxImportLineLength bounds a single NDJSON line
+	maxImportLineLength = 1 << 20
+)
+
+// ImportRecord is one url of an import, a line of NDJSON or a row of CSV
+type ImportRecord = service.ImportRecord
+
+type ImportResponse struct {
+	DryRun bool `json:"dry_run"`
+	// Imported counts the renamed urls too
+	Imported int `json:"imported"`
+	Skipped  int `json:"skipped"`
+	Failed   int `json:"failed"`
+	// Rows lists the rows that weren't imported as given, up to maxImportResults of them
+	Rows      []ImportRowResult `json:"rows"`
+	Truncated bool              `json:"truncated,omitempty"`
+}
+
+type ImportRowResult struct {
+	// Row is the line of the row in the file
+	Row int `json:"row"`
+	service.ImportResult
+}
+
+func (r *ImportResponse) add(result ImportRowResult) {
+	switch result.Status {
+	case service.ImportStatusImported:
+		r.Imported++
+		return
+	case service.ImportStatusRenamed:
+		r.Imported++
+	case service.ImportStatusSkipped:
+		r.Skipped++
+	default:
+		r.Failed++
+	}
+	if len(r.Rows) == maxImportResults {
+		r.Truncated = true
+		return
+	}
+	r.Rows = append(r.Rows, result)
+}
+
+// ImportShortUrls creates urls from a CSV or NDJSON body, keeping their short urls and creation dates.
+// The rows are read and stored one at a time so large imports are never held in memory. A row that
+// fails is reported and doesn't stop the import. on_conflict decides what happens to rows whose
+// short url or original url is in use: error fails them, skip keeps the existing url, overwrite
+// replaces it and rename gives the row a new short url. dry_run only checks the rows.
+func ImportShortUrls(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	format, err := transferFormat(query.Get("format"), r.Header.Get(contentType))
+	if err != nil {
+		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	dryRun := false
+	if value := query.Get("dry_run"); value != "" {
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: "dry_run must be true or false"})
+			return
+		}
+	}
+	importer, err := shortener.NewImporter(r.Host, query.Get("on_conflict"), dryRun)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	var reader importReader
+	if format == transferFormatCsv {
+		if reader, err = newCsvImportReader(r.Body); err != nil {
+			ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+	} else {
+		reader = newNdjsonImportReader(r.Body)
+	}
+	response := ImportResponse{DryRun: dryRun, Rows: make([]ImportRowResult, 0)}
+	for {
+		line, record, err := reader.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			response.add(ImportRowResult{Row: line, ImportResult: service.ImportResult{Status: service.ImportStatusFailed, Error: err.Error()}})
+			// Only the row is lost unless the body itself can't be read any further
+			var rowErr *importRowError
+			if !errors.As(err, &rowErr) {
+				break
+			}
+			continue
+		}
+		response.add(ImportRowResult{Row: line, ImportResult: importer.Import(record)})
+	}
+	ServerResponse(w, http.StatusOK, response)
+}
+
+// importRowError spoils a single row of an import
+type importRowError struct {
+	message string
+}
+
+func (e *importRowError) Error() string {
+	return e.message
+}
+
+// importReader reads the records of an import one at a time
+type importReader interface {
+	// next returns the next record and its line. An *importRowError only affects that line,
+	// other errors end the import and io.EOF is returned after the last record.
+	next() (int, *ImportRecord, error)
+}
+
+type ndjsonImportReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func newNdjsonImportReader(body io.Reader) *ndjsonImportReader {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportLineLength)
+	return &ndjsonImportReader{scanner: scanner}
+}
+
+func (r *ndjsonImportReader) next() (int, *ImportRecord, error) {
+	for r.scanner.Scan() {
+		r.line++
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		record := new(ImportRecord)
+		if err := json.Unmarshal(line, record); err != nil {
+			return r.line, nil, &importRowError{"Invalid JSON: " + err.Error()}
+		}
+		return r.line, record, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return r.line + 1, nil, fmt.Errorf("Error reading the import: %v", err)
+	}
+	return r.line, nil, io.EOF
+}
+
+type csvImportReader struct {
+	reader  *csv.Reader
+	columns []string
+}
+
+// newCsvImportReader reads the header, which names the transferColumns present and password
+func newCsvImportReader(body io.Reader) (*csvImportReader, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("Can not read the CSV header: %v", err)
+	}
+	known := map[string]bool{"password": true}
+	for _, column := range transferColumns {
+		known[column] = true
+	}
+	columns := make([]string, len(header))
+	hasOriginalUrl := false
+	for i, name := range header {
+		// Spreadsheets like to start the file with a byte order mark
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !known[name] {
+			return nil, fmt.Errorf("Unknown CSV column %q", name)
+		}
+		hasOriginalUrl = hasOriginalUrl || name == "original_url"
+		columns[i] = name
+	}
+	if !hasOriginalUrl {
+		return nil, errors.New("The CSV header needs an original_url column")
+	}
+	return &csvImportReader{reader: reader, columns: columns}, nil
+}
+
+func (r *csvImportReader) next() (int, *ImportRecord, error) {
+	fields, err := r.reader.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return parseErr.Line, nil, &importRowError{parseErr.Err.Error()}
+		}
+		if errors.Is(err, io.EOF) {
+			return 0, nil, err
+		}
+		return 0, nil, fmt.Errorf("Error reading the import: %v", err)
+	}
+	line, _ := r.reader.FieldPos(0)
+	if len(fields) > len(r.columns) {
+		return line, nil, &importRowError{"The row has more fields than the header"}
+	}
+	record := new(ImportRecord)
+	for i, value := range fields {
+		if err = setCsvField(record, r.columns[i], strings.TrimSpace(value)); err != nil {
+			return line, nil, &importRowError{err.Error()}
+		}
+	}
+	return line, record, nil
+}
+
+// setCsvField sets the field of the record named by a CSV column, empty values are left unset
+func setCsvField(record *ImportRecord, column string, value string) error {
+	if value == "" {
+		return nil
+	}
+	utm := func() *models.UtmParams {
+		if record.Utm == nil {
+			record.Utm = &models.UtmParams{}
+		}
+		return record.Utm
+	}
+	var err error
+	switch column {
+	case "domain":
+		record.Domain = value
+	case "short_url":
+		record.ShortUrl = value
+	case "original_url":
+		record.OriginalUrl = value
+	case "created_at":
+		record.CreatedAt = value
+	case "password":
+		record.Password = value
+	case "password_hash":
+		record.PasswordHash = value
+	case "max_clicks":
+		record.MaxClicks, err = parseCsvInt(value)
+	case "remaining_clicks":
+		record.RemainingClicks, err = parseCsvInt(value)
+	case "click_count":
+		record.ClickCount, err = strconv.Atoi(value)
+	case "title":
+		record.Title = &value
+	case "description":
+		record.Description = &value
+	case "tags":
+		tags := strings.Split(value, ",")
+		record.Tags = &tags
+	case "metadata":
+		err = json.Unmarshal([]byte(value), &record.Metadata)
+	case "utm_source":
+		utm().Source = value
+	case "utm_medium":
+		utm().Medium = value
+	case "utm_campaign":
+		utm().Campaign = value
+	case "utm_term":
+		utm().Term = value
+	case "utm_content":
+		utm().Content = value
+	case "forward_query":
+		record.ForwardQuery, err = strconv.ParseBool(value)
+	case "rules":
+		err = json.Unmarshal([]byte(value), &record.Rules)
+	case "variants":
+		err = json.Unmarshal([]byte(value), &record.Variants)
+	case "variant_mode":
+		record.VariantMode = &value
+	case "status":
+		record.Status = value
+	case "status_reason":
+		record.StatusReason = value
+	}
+	if err != nil {
+		return fmt.Errorf("Invalid %s %q", column, value)
+	}
+	return nil
+}
+
+func parseCsvInt(value string) (*int, error) {
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
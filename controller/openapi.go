@@ -0,0 +1,17 @@
+package controller
+
+import (
+	"net/http"
+
+	"URL_SHORTENER/api"
+)
+
+// OpenApiPath is where the OpenAPI document of the API is served
+const OpenApiPath = "/openapi.json"
+
+// OpenApiSpec serves the OpenAPI document describing every route of the API
+func OpenApiSpec(w http.ResponseWriter, r *http.Request) {
+	SetHeader(w, contentType, applicationJson)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(api.Spec)
+}
@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"html/template"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"URL_SHORTENER/models"
+	"URL_SHORTENER/storage"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	HeaderLinkPassword        = "X-Link-Password"
+	formFieldPassword         = "password"
+	unlockPathSuffix          = "/unlock"
+	textHtml                  = "text/html; charset=utf-8"
+	maxFailedPasswordAttempts = 5
+	passwordLockoutDuration   = 15 * time.Minute
+	ErrPasswordRequired       = "This link is password protected."
+	ErrIncorrectPassword      = "Incorrect password."
+	ErrTooManyPasswordGuesses = "Too many incorrect passwords, try again later."
+)
+
+var unlockFormTemplate = template.Must(template.New("unlock").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Password required</title></head>
+<body>
+<form method="POST" action="{{.Action}}">
+<p>{{.Message}}</p>
+<input type="password" name="password" autofocus required>
+<button type="submit">Unlock</button>
+</form>
+</body>
+</html>
+`))
+
+// checkLinkPassword verifies the password for a protected link and writes the
+// error response if it is missing or wrong. It reports whether access is allowed.
+func checkLinkPassword(w http.ResponseWriter, r *http.Request, url *models.Url, password string) bool {
+	if url.PasswordHash == "" {
+		return true
+	}
+	if password == "" {
+		passwordRequiredResponse(w, r, url, ErrPasswordRequired)
+		return false
+	}
+	// The attempt is counted before the password is compared, so concurrent guesses can't outrun the lock.
+	// The lockouts are kept in the database, so guesses spread over several processes add up.
+	lockedUntil, err := store.CountPasswordAttempt(url.Domain, url.ShortUrl, maxFailedPasswordAttempts, clock.Now(), passwordLockoutDuration)
+	if err != nil {
+		ServerResponse(w, http.StatusInternalServerError, ErrorResponse{Error: "Error checking the password."})
+		return false
+	}
+	if lockedFor := lockedUntil.Sub(clock.Now()); lockedFor > 0 {
+		SetHeader(w, "Retry-After", strconv.Itoa(int(lockedFor.Seconds())+1))
+		ServerResponse(w, http.StatusTooManyRequests, ErrorResponse{Error: ErrTooManyPasswordGuesses})
+		return false
+	}
+	if bcrypt.CompareHashAndPassword([]byte(url.PasswordHash), []byte(password)) != nil {
+		passwordRequiredResponse(w, r, url, ErrIncorrectPassword)
+		return false
+	}
+	if err = store.ResetPasswordLockout(url.Domain, url.ShortUrl); err != nil {
+		log.Printf("failed to reset the password lockout of %s/%s: %v", url.Domain, url.ShortUrl, err)
+	}
+	return true
+}
+
+// passwordRequiredResponse serves the unlock form to browsers and a JSON error to API clients
+func passwordRequiredResponse(w http.ResponseWriter, r *http.Request, url *models.Url, message string) {
+	if !acceptsHtml(r) {
+		ServerResponse(w, http.StatusUnauthorized, ErrorResponse{Error: message})
+		return
+	}
+	// The preview page of a link is served at the short url followed by a +, which the unlock route doesn't take.
+	// The query string is kept so that it can be forwarded once the link is unlocked.
+	action := strings.TrimSuffix(strings.TrimSuffix(r.URL.Path, unlockPathSuffix), PreviewSuffix) + unlockPathSuffix
+	if r.URL.RawQuery != "" {
+		action += "?" + r.URL.RawQuery
+	}
+	SetHeader(w, contentType, textHtml)
+	w.WriteHeader(http.StatusUnauthorized)
+	_ = unlockFormTemplate.Execute(w, struct{ Action, Message string }{action, message})
+}
+
+// UnlockShortUrl accepts the password submitted by the unlock form and
+// redirects to the original url once it is correct
+func UnlockShortUrl(w http.ResponseWriter, r *http.Request) {
+	shortUrl, err := ParsePathParam(r, PathParamShortUrlId)
+	if err != nil {
+		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	domain, ok := requestDomain(w, r)
+	if !ok {
+		return
+	}
+	url, err := store.GetOriginalUrl(domain.Name, shortUrl)
+	if err != nil {
+		ServerResponse(w, http.StatusNotFound, ErrorResponse{Error: storage.ErrShortURLDoesNotExist})
+		return
+	}
+	if !checkUrlStatus(w, r, url) {
+		return
+	}
+	if !checkLinkPassword(w, r, url, r.PostFormValue(formFieldPassword)) {
+		return
+	}
+	target, variant := pickDestination(w, r, url)
+	if !followUrl(w, url, variant) {
+		return
+	}
+	http.Redirect(w, r, destinationUrl(url, target, r), http.StatusSeeOther)
+}
@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"embed"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"URL_SHORTENER/storage"
+)
+
+const (
+	queryParamPreview = "preview"
+	// PreviewSuffix appended to a short url shows its preview page instead of following it
+	PreviewSuffix    = "+"
+	defaultBrandName = "URL Shortener"
+)
+
+//go:embed templates/*.html
+var templateFiles embed.FS
+
+var previewTemplate = template.Must(template.ParseFS(templateFiles, "templates/preview.html"))
+
+// brandName is shown in the header of the HTML pages
+var brandName = defaultBrandName
+
+// SetBrandName sets the header of the HTML pages, empty restores the default
+func SetBrandName(name string) {
+	if name == "" {
+		name = defaultBrandName
+	}
+	brandName = name
+}
+
+type previewPage struct {
+	Brand       string
+	OriginalUrl string
+	Title       string
+	CreatedAt   string
+	ClickCount  int
+}
+
+// PreviewUrl shows where a short url leads without following it. It is served
+// for the short url followed by a + or with ?preview=1.
+func PreviewUrl(w http.ResponseWriter, r *http.Request) {
+	shortUrl, err := ParsePathParam(r, PathParamShortUrlId)
+	if err != nil {
+		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	shortUrl = strings.TrimSuffix(shortUrl, PreviewSuffix)
+	domain, ok := requestDomain(w, r)
+	if !ok {
+		return
+	}
+	url, err := store.GetOriginalUrl(domain.Name, shortUrl)
+	if err != nil {
+		ServerResponse(w, http.StatusNotFound, ErrorResponse{Error: storage.ErrShortURLDoesNotExist})
+		return
+	}
+	if !checkUrlStatus(w, r, url) {
+		return
+	}
+	// The destination of a protected link is not revealed without its password
+	if !checkLinkPassword(w, r, url, r.Header.Get(HeaderLinkPassword)) {
+		return
+	}
+
+	target, _ := pickDestination(nil, r, url)
+	page := previewPage{
+		Brand:       brandName,
+		OriginalUrl: destinationUrl(url, target, r),
+		Title:       url.Title,
+		CreatedAt:   url.CreatedAt.Format(YYYYMMDDhhmmss) + " UTC",
+		ClickCount:  url.ClickCount,
+	}
+	// The title of the fetched page tells visitors more about the destination than the one given to the url
+	if url.Page != nil && url.Page.Title != "" {
+		page.Title = url.Page.Title
+	}
+	SetHeader(w, contentType, textHtml)
+	w.WriteHeader(http.StatusOK)
+	_ = previewTemplate.Execute(w, page)
+}
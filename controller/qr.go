@@ -0,0 +1,272 @@
+package controller
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	neturl "net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"URL_SHORTENER/models"
+	"URL_SHORTENER/storage"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+const (
+	qrFormatPng       = "png"
+	qrFormatSvg       = "svg"
+	imagePng          = "image/png"
+	imageSvg          = "image/svg+xml"
+	defaultQrSize     = 256
+	maxQrSize         = 2048
+	defaultQrMargin   = 4
+	maxQrMargin       = 32
+	defaultQrLevel    = "M"
+	defaultQrFgColor  = "000000"
+	defaultQrBgColor  = "ffffff"
+	maxQrCacheEntries = 1000
+)
+
+var qrRecoveryLevels = map[string]qrcode.RecoveryLevel{
+	"L": qrcode.Low,
+	"M": qrcode.Medium,
+	"Q": qrcode.High,
+	"H": qrcode.Highest,
+}
+
+// publicBaseUrl is the scheme and host short links are served on, e.g. https://sho.rt.
+// When empty it is derived from the incoming request.
+var publicBaseUrl string
+
+// SetPublicBaseUrl sets the scheme and host used to build public short links
+func SetPublicBaseUrl(baseUrl string) {
+	publicBaseUrl = strings.TrimSuffix(baseUrl, "/")
+}
+
+type QrCodeParams struct {
+	Format  string
+	Size    int
+	Margin  int
+	Level   string
+	FgColor color.RGBA
+	BgColor color.RGBA
+}
+
+// qrCache keeps rendered images keyed by content and render parameters
+type qrCache struct {
+	mutex   sync.RWMutex
+	entries map[string][]byte
+}
+
+var renderedQrCodes = &qrCache{entries: make(map[string][]byte)}
+
+func (c *qrCache) get(key string) ([]byte, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	body, ok := c.entries[key]
+	return body, ok
+}
+
+func (c *qrCache) set(key string, body []byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	// Start over instead of tracking recency, the cache is cheap to refill
+	if len(c.entries) >= maxQrCacheEntries {
+		c.entries = make(map[string][]byte)
+	}
+	c.entries[key] = body
+}
+
+func GetQrCode(w http.ResponseWriter, r *http.Request) {
+	shortUrl, err := ParsePathParam(r, PathParamShortUrlId)
+	if err != nil {
+		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	params, err := parseQrCodeParams(r)
+	if err != nil {
+		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	domain, ok := requestDomain(w, r)
+	if !ok {
+		return
+	}
+	if !store.CheckShortUrlExists(domain.Name, shortUrl) {
+		ServerResponse(w, http.StatusNotFound, ErrorResponse{Error: storage.ErrShortURLDoesNotExist})
+		return
+	}
+
+	content := publicRedirectUrl(r, domain, shortUrl)
+	cacheKey := fmt.Sprintf("%s|%s|%d|%d|%s|%v|%v", content, params.Format, params.Size, params.Margin, params.Level, params.FgColor, params.BgColor)
+	body, ok := renderedQrCodes.get(cacheKey)
+	if !ok {
+		qr, err := qrcode.New(content, qrRecoveryLevels[params.Level])
+		if err != nil {
+			ServerResponse(w, http.StatusInternalServerError, ErrorResponse{Error: "Error generating QR code."})
+			return
+		}
+		// The margin is drawn here so that it can be configured
+		qr.DisableBorder = true
+		modules := qr.Bitmap()
+		// Scanners need at least a pixel per module
+		if minSize := len(modules) + 2*params.Margin; params.Size < minSize {
+			ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("size must be at least %d for this QR code", minSize)})
+			return
+		}
+		body, err = renderQrCode(modules, params)
+		if err != nil {
+			ServerResponse(w, http.StatusInternalServerError, ErrorResponse{Error: "Error generating QR code."})
+			return
+		}
+		renderedQrCodes.set(cacheKey, body)
+	}
+
+	if params.Format == qrFormatSvg {
+		SetHeader(w, contentType, imageSvg)
+	} else {
+		SetHeader(w, contentType, imagePng)
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+// publicRedirectUrl builds the absolute URL visitors are redirected from. Urls of a registered domain
+// are served on that domain, urls of the default namespace on the public host.
+func publicRedirectUrl(r *http.Request, domain *models.Domain, shortUrl string) string {
+	if domain.Name == "" && publicBaseUrl != "" {
+		return publicBaseUrl + "/" + shortUrl
+	}
+	scheme, host := "http", r.Host
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if public, err := neturl.Parse(publicBaseUrl); err == nil && public.Scheme != "" {
+		scheme = public.Scheme
+	}
+	if domain.Name != "" {
+		host = domain.Name
+	}
+	return scheme + "://" + host + "/" + shortUrl
+}
+
+func parseQrCodeParams(r *http.Request) (*QrCodeParams, error) {
+	query := r.URL.Query()
+	params := &QrCodeParams{
+		Format: strings.ToLower(queryOrDefault(query.Get("format"), qrFormatPng)),
+		Level:  strings.ToUpper(queryOrDefault(query.Get("level"), defaultQrLevel)),
+	}
+	if params.Format != qrFormatPng && params.Format != qrFormatSvg {
+		return nil, errors.New("format must be one of png, svg")
+	}
+	if _, ok := qrRecoveryLevels[params.Level]; !ok {
+		return nil, errors.New("level must be one of L, M, Q, H")
+	}
+
+	var err error
+	params.Size, err = parseIntParam(query.Get("size"), defaultQrSize, 1, maxQrSize)
+	if err != nil {
+		return nil, fmt.Errorf("size %s", err.Error())
+	}
+	params.Margin, err = parseIntParam(query.Get("margin"), defaultQrMargin, 0, maxQrMargin)
+	if err != nil {
+		return nil, fmt.Errorf("margin %s", err.Error())
+	}
+	params.FgColor, err = parseHexColor(queryOrDefault(query.Get("fg"), defaultQrFgColor))
+	if err != nil {
+		return nil, fmt.Errorf("fg %s", err.Error())
+	}
+	params.BgColor, err = parseHexColor(queryOrDefault(query.Get("bg"), defaultQrBgColor))
+	if err != nil {
+		return nil, fmt.Errorf("bg %s", err.Error())
+	}
+	return params, nil
+}
+
+func queryOrDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+func parseIntParam(value string, defaultValue, min, max int) (int, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < min || parsed > max {
+		return 0, fmt.Errorf("must be an integer between %d and %d", min, max)
+	}
+	return parsed, nil
+}
+
+// parseHexColor parses colors of the form RRGGBB, with or without a leading #
+func parseHexColor(value string) (color.RGBA, error) {
+	value = strings.TrimPrefix(value, "#")
+	if len(value) != 6 {
+		return color.RGBA{}, errors.New("must be a hex color like 000000")
+	}
+	rgb, err := strconv.ParseUint(value, 16, 32)
+	if err != nil {
+		return color.RGBA{}, errors.New("must be a hex color like 000000")
+	}
+	return color.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 0xff}, nil
+}
+
+func renderQrCode(modules [][]bool, params *QrCodeParams) ([]byte, error) {
+	if params.Format == qrFormatSvg {
+		return renderQrSvg(modules, params), nil
+	}
+	return renderQrPng(modules, params)
+}
+
+func renderQrPng(modules [][]bool, params *QrCodeParams) ([]byte, error) {
+	totalModules := len(modules) + 2*params.Margin
+	img := image.NewRGBA(image.Rect(0, 0, params.Size, params.Size))
+	for y := 0; y < params.Size; y++ {
+		row := y*totalModules/params.Size - params.Margin
+		for x := 0; x < params.Size; x++ {
+			col := x*totalModules/params.Size - params.Margin
+			if row >= 0 && row < len(modules) && col >= 0 && col < len(modules) && modules[row][col] {
+				img.SetRGBA(x, y, params.FgColor)
+			} else {
+				img.SetRGBA(x, y, params.BgColor)
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func renderQrSvg(modules [][]bool, params *QrCodeParams) []byte {
+	totalModules := len(modules) + 2*params.Margin
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		params.Size, params.Size, totalModules, totalModules)
+	fmt.Fprintf(&buf, `<rect width="100%%" height="100%%" fill="%s"/>`, hexColor(params.BgColor))
+	fmt.Fprintf(&buf, `<path fill="%s" d="`, hexColor(params.FgColor))
+	for row := range modules {
+		for col, dark := range modules[row] {
+			if dark {
+				fmt.Fprintf(&buf, "M%d %dh1v1h-1z", col+params.Margin, row+params.Margin)
+			}
+		}
+	}
+	buf.WriteString(`"/></svg>`)
+	return buf.Bytes()
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
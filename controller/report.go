@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+
+	"URL_SHORTENER/models"
+	"URL_SHORTENER/storage"
+)
+
+const (
+	PathParamReportId = "report_id"
+	maxReportDetails  = 2000
+	// maxReportBody bounds the request body, it leaves room for escaped details
+	maxReportBody = 16 << 10
+)
+
+// reportReasons are the kinds of abuse a short url can be reported for
+var reportReasons = map[string]bool{
+	"spam":     true,
+	"phishing": true,
+	"malware":  true,
+	"illegal":  true,
+	"other":    true,
+}
+
+type ReportRequestParams struct {
+	Reason  string `json:"reason"`
+	Details string `json:"details,omitempty"`
+	Email   string `json:"email,omitempty"`
+}
+
+// ReportShortUrl queues an abuse report of a short url of the request host for review
+func ReportShortUrl(w http.ResponseWriter, r *http.Request) {
+	shortUrl, err := ParsePathParam(r, PathParamShortUrlId)
+	if err != nil {
+		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	params := new(ReportRequestParams)
+	if err = json.NewDecoder(http.MaxBytesReader(w, r.Body, maxReportBody)).Decode(params); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			ServerResponse(w, http.StatusRequestEntityTooLarge, ErrorResponse{Error: "Request body is too large"})
+			return
+		}
+		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: "Invalid request parameters"})
+		return
+	}
+	if err = validateReportParams(params); err != nil {
+		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	domain, ok := requestDomain(w, r)
+	if !ok {
+		return
+	}
+	if !store.CheckShortUrlExists(domain.Name, shortUrl) {
+		ServerResponse(w, http.StatusNotFound, ErrorResponse{Error: storage.ErrShortURLDoesNotExist})
+		return
+	}
+	report := &models.Report{
+		Domain:    domain.Name,
+		ShortUrl:  shortUrl,
+		Reason:    params.Reason,
+		Details:   strings.TrimSpace(params.Details),
+		Email:     params.Email,
+		CreatedAt: models.FormatTimestamp(clock.Now()),
+	}
+	if err = store.InsertReport(report); err != nil {
+		ServerResponse(w, http.StatusInternalServerError, ErrorResponse{Error: "Error saving report."})
+		return
+	}
+	ServerResponse(w, http.StatusAccepted, report)
+}
+
+// ListReports lists the abuse reports oldest first, optionally only the reviewed or pending ones
+func ListReports(w http.ResponseWriter, r *http.Request) {
+	limit, offset, ok := parsePageParams(w, r)
+	if !ok {
+		return
+	}
+	filter := &storage.ReportFilter{Limit: limit, Offset: offset}
+	if value := r.URL.Query().Get("reviewed"); value != "" {
+		reviewed, err := strconv.ParseBool(value)
+		if err != nil {
+			ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: "reviewed must be true or false"})
+			return
+		}
+		filter.Reviewed = &reviewed
+	}
+	reports, err := store.ListReports(filter)
+	if err != nil {
+		ServerResponse(w, http.StatusInternalServerError, ErrorResponse{Error: "Error listing reports."})
+		return
+	}
+	ServerResponse(w, http.StatusOK, reports)
+}
+
+// ReviewReport takes an abuse report off the review queue
+func ReviewReport(w http.ResponseWriter, r *http.Request) {
+	value, err := ParsePathParam(r, PathParamReportId)
+	if err != nil {
+		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: "Report id must be a number"})
+		return
+	}
+	err = store.ReviewReport(id, models.FormatTimestamp(clock.Now()))
+	if err != nil {
+		if err.Error() == storage.ErrReportDoesNotExist {
+			ServerResponse(w, http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		} else {
+			ServerResponse(w, http.StatusInternalServerError, ErrorResponse{Error: "Error reviewing report."})
+		}
+		return
+	}
+	ServerResponse(w, http.StatusOK, "Review Successful.")
+}
+
+func validateReportParams(params *ReportRequestParams) error {
+	if !reportReasons[params.Reason] {
+		return errors.New("Reason must be one of spam, phishing, malware, illegal, other")
+	}
+	if len(params.Details) > maxReportDetails {
+		return errors.New("Details can be at most 2000 characters")
+	}
+	if params.Email != "" {
+		address, err := mail.ParseAddress(params.Email)
+		if err != nil || address.Address != params.Email {
+			return errors.New("Email must be a plain email address")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"log"
+	neturl "net/url"
+	"strings"
+
+	"URL_SHORTENER/fetcher"
+	"URL_SHORTENER/models"
+	"URL_SHORTENER/service"
+	"URL_SHORTENER/storage"
+)
+
+const (
+	ErrSelfLink   = "The destination is a short url of this service, short urls can not lead to each other."
+	ErrSelfRoute  = "The destination is an API route of this service."
+	apiPathPrefix = "/api/"
+)
+
+// redirectResolver stores the end of the redirect chain of new urls, destinations are kept as given while it is nil
+var redirectResolver *fetcher.Resolver
+
+// SetRedirectResolver sets the resolver following the destinations of new urls
+func SetRedirectResolver(resolver *fetcher.Resolver) {
+	redirectResolver = resolver
+}
+
+// checkSelfLink rejects a destination served by this service: an API route or a short url
+// on the request host, the public host or a registered domain. Following such a short url
+// would chain redirects, or loop once the target points back.
+func checkSelfLink(requestHost string, destination string) error {
+	parsed, err := neturl.Parse(destination)
+	if err != nil || parsed.Host == "" {
+		return nil
+	}
+	path := parsed.Path
+	apiRoute := strings.HasPrefix(path, apiPathPrefix) || path == OpenApiPath
+	shortUrl := strings.TrimSuffix(strings.TrimPrefix(path, "/"), PreviewSuffix)
+	if !apiRoute && (shortUrl == "" || strings.Contains(shortUrl, "/")) {
+		return nil
+	}
+
+	host := service.NormalizeHost(parsed.Host)
+	ownHost := host == service.NormalizeHost(requestHost)
+	if publicBaseUrl != "" {
+		if public, err := neturl.Parse(publicBaseUrl); err == nil && host == service.NormalizeHost(public.Host) {
+			ownHost = true
+		}
+	}
+	namespace := service.DefaultDomain.Name
+	domain, err := store.GetDomain(host)
+	if err == nil {
+		ownHost = true
+		namespace = domain.Name
+	} else if err.Error() != storage.ErrDomainDoesNotExist {
+		return errors.New("Error resolving domain.")
+	}
+	switch {
+	case !ownHost:
+		return nil
+	case apiRoute:
+		return errors.New(ErrSelfRoute)
+	case store.CheckShortUrlExists(namespace, shortUrl):
+		return errors.New(ErrSelfLink)
+	}
+	return nil
+}
+
+// checkSelfLinks rejects urls with a destination served by this service
+func checkSelfLinks(requestHost string, url *models.Url) error {
+	destinations := []string{url.OriginalUrl}
+	for _, rule := range url.Rules {
+		destinations = append(destinations, rule.Destination)
+	}
+	for _, variant := range url.Variants {
+		destinations = append(destinations, variant.Destination)
+	}
+	for _, destination := range destinations {
+		if err := checkSelfLink(requestHost, destination); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveDestination replaces the original url with the end of its redirect chain. Chains
+// through this service, loops and chains longer than the hop limit are rejected. A destination
+// that can't be reached is kept as given.
+func resolveDestination(ctx context.Context, requestHost string, url *models.Url) error {
+	if redirectResolver == nil {
+		return nil
+	}
+	var selfLinkErr error
+	final, err := redirectResolver.Resolve(ctx, url.OriginalUrl, func(hop string) error {
+		selfLinkErr = checkSelfLink(requestHost, hop)
+		return selfLinkErr
+	})
+	switch {
+	case err == nil:
+		url.OriginalUrl = final
+	case selfLinkErr != nil:
+		return selfLinkErr
+	case errors.Is(err, fetcher.ErrRedirectLoop), errors.Is(err, fetcher.ErrTooManyRedirects):
+		return errors.New("The destination can not be resolved, " + err.Error())
+	default:
+		log.Printf("resolving the redirects of %s: %v", url.OriginalUrl, err)
+	}
+	return nil
+}
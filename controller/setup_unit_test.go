@@ -1,13 +1,28 @@
 package controller
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"URL_SHORTENER/storage"
 
 	"github.com/golang/mock/gomock"
 )
 
+// defaultTestHost is the Host of requests built by httptest.NewRequest
+const defaultTestHost = "example.com"
+
+// testNow is the time of the clock the handlers run on in tests
+var testNow = time.Date(2024, 10, 16, 21, 5, 18, 0, time.UTC)
+
+// fixedClock always tells the same time
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
 type Resources struct {
 	ctl    *gomock.Controller
 	MockDb *storage.MockURLOperations
@@ -18,7 +33,10 @@ func SetupTestDB(t *testing.T) *Resources {
 	r := new(Resources)
 	r.ctl = gomock.NewController(t)
 	r.MockDb = storage.NewMockURLOperations(r.ctl)
+	SetClock(fixedClock(testNow))
 	Init(r.MockDb)
+	// Requests to the default test host resolve to the default namespace
+	r.MockDb.EXPECT().GetDomain(defaultTestHost).AnyTimes().Return(nil, errors.New(storage.ErrDomainDoesNotExist))
 	return r
 }
 
@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"URL_SHORTENER/fetcher"
+	"URL_SHORTENER/models"
+	"URL_SHORTENER/service"
+	"URL_SHORTENER/webhook"
+)
+
+// linkScreener checks the destinations of the urls stored through the service
+// against this service itself, the redirect resolver and the blocklist
+type linkScreener struct{}
+
+func (linkScreener) ScreenUrl(ctx context.Context, host string, url *models.Url) error {
+	err := checkSelfLinks(host, url)
+	if err == nil {
+		err = resolveDestination(ctx, host, url)
+	}
+	if err == nil {
+		err = screenUrl(url)
+	}
+	return err
+}
+
+func (linkScreener) ScreenDestinations(host string, url *models.Url) error {
+	err := checkSelfLinks(host, url)
+	if err == nil {
+		err = screenUrl(url)
+	}
+	return err
+}
+
+// linkEvents fetches the pages of new urls and publishes the link events of the service
+type linkEvents struct{}
+
+func (linkEvents) UrlCreated(url *models.Url) {
+	if pageFetcher != nil {
+		pageFetcher.Enqueue(fetcher.Job{Domain: url.Domain, OriginalUrl: url.OriginalUrl})
+	}
+	publishLinkEvent(webhook.EventLinkCreated, url)
+}
+
+func (linkEvents) UrlUpdated(domain string, shortUrl string) {
+	publishLinkUpdated(domain, shortUrl)
+}
+
+func (linkEvents) UrlDeleted(domain string, shortUrl string) {
+	publishLinkDeleted(domain, shortUrl)
+}
+
+// writeServiceError writes the response of an error returned by the service
+func writeServiceError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, service.ErrInvalid):
+		status = http.StatusBadRequest
+	case errors.Is(err, service.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, service.ErrConflict):
+		status = http.StatusConflict
+	case errors.Is(err, service.ErrPreconditionFailed):
+		status = http.StatusPreconditionFailed
+	}
+	ServerResponse(w, status, ErrorResponse{Error: err.Error()})
+}
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+
+	"URL_SHORTENER/models"
+	"URL_SHORTENER/service"
+)
+
+const (
+	ErrUrlDisabled = "The short url was disabled by an administrator."
+	ErrUrlBlocked  = "The short url was disabled because its destination is on the malware and phishing blocklist."
+)
+
+var blockedTemplate = template.Must(template.ParseFS(templateFiles, "templates/blocked.html"))
+
+// disabledStatusCode and disabledTemplate answer browsers following a disabled url
+var (
+	disabledStatusCode = http.StatusUnavailableForLegalReasons
+	disabledTemplate   = template.Must(template.ParseFS(templateFiles, "templates/disabled.html"))
+)
+
+// SetStatusRequestParams is the body of PUT /api/short/{short_url}/status
+type SetStatusRequestParams = service.StatusParams
+
+// statusPage is what the disabled and blocked pages are rendered with
+type statusPage struct {
+	Brand    string
+	ShortUrl string
+	Reason   string
+}
+
+// SetDisabledResponse sets the status code of disabled urls, 451 or 403, and the HTML template
+// of the page shown to browsers. The default page is kept when pagePath is empty.
+func SetDisabledResponse(statusCode int, pagePath string) error {
+	if statusCode != http.StatusUnavailableForLegalReasons && statusCode != http.StatusForbidden {
+		return errors.New("The status code of disabled urls must be 451 or 403")
+	}
+	if pagePath != "" {
+		page, err := template.ParseFiles(pagePath)
+		if err != nil {
+			return err
+		}
+		disabledTemplate = page
+	}
+	disabledStatusCode = statusCode
+	return nil
+}
+
+// checkUrlStatus answers for a url that isn't active, with a page for browsers and a JSON error
+// for API clients. It reports whether the url can be followed.
+func checkUrlStatus(w http.ResponseWriter, r *http.Request, url *models.Url) bool {
+	var statusCode int
+	var message string
+	var page *template.Template
+	switch url.Status {
+	case models.StatusDisabled:
+		statusCode, message, page = disabledStatusCode, ErrUrlDisabled, disabledTemplate
+	case models.StatusBlocked:
+		statusCode, message, page = http.StatusForbidden, ErrUrlBlocked, blockedTemplate
+	default:
+		return true
+	}
+	if !acceptsHtml(r) {
+		ServerResponse(w, statusCode, ErrorResponse{Error: message})
+		return false
+	}
+	SetHeader(w, contentType, textHtml)
+	w.WriteHeader(statusCode)
+	err := page.Execute(w, statusPage{Brand: brandName, ShortUrl: url.ShortUrl, Reason: url.StatusReason})
+	if err != nil {
+		log.Printf("rendering the %s page of %s failed: %v", url.Status, url.ShortUrl, err)
+	}
+	return false
+}
+
+// SetShortUrlStatus disables, blocks or reactivates a short url of the request host
+func SetShortUrlStatus(w http.ResponseWriter, r *http.Request) {
+	shortUrl, err := ParsePathParam(r, PathParamShortUrlId)
+	if err != nil {
+		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	params := new(SetStatusRequestParams)
+	if err = json.NewDecoder(r.Body).Decode(params); err != nil {
+		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: "Invalid request parameters"})
+		return
+	}
+	url, err := shortener.SetStatus(r.Host, shortUrl, params)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	ServerResponse(w, http.StatusOK, toShortUrlResponse(url))
+}
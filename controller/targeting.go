@@ -0,0 +1,171 @@
+package controller
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"URL_SHORTENER/geoip"
+	"URL_SHORTENER/models"
+	"URL_SHORTENER/service"
+)
+
+// geoDatabase resolves visitor countries, country conditions never match without it
+var geoDatabase *geoip.Database
+
+// SetGeoDatabase sets the GeoIP database used by the country conditions of targeting rules
+func SetGeoDatabase(db *geoip.Database) {
+	geoDatabase = db
+}
+
+// targetDestination returns the destination of the first rule matching the
+// visitor. It reports false when none does.
+func targetDestination(url *models.Url, r *http.Request, now time.Time) (string, bool) {
+	if len(url.Rules) == 0 {
+		return "", false
+	}
+	visitor := visitorOf(r)
+	for i := range url.Rules {
+		if visitor.matches(&url.Rules[i], now) {
+			return url.Rules[i].Destination, true
+		}
+	}
+	return "", false
+}
+
+// visitor holds the request properties the rule conditions look at
+type visitor struct {
+	platform string
+	language string
+	country  string
+}
+
+func visitorOf(r *http.Request) visitor {
+	return visitor{
+		platform: detectPlatform(r.UserAgent()),
+		language: preferredLanguage(r.Header.Get("Accept-Language")),
+		country:  visitorCountry(r),
+	}
+}
+
+func (v visitor) matches(rule *models.TargetingRule, now time.Time) bool {
+	if len(rule.Platforms) > 0 && !containsString(rule.Platforms, v.platform) {
+		return false
+	}
+	if len(rule.Languages) > 0 && !matchesLanguage(rule.Languages, v.language) {
+		return false
+	}
+	if len(rule.Countries) > 0 && !containsString(rule.Countries, v.country) {
+		return false
+	}
+	startsAt, endsAt, err := service.RuleWindow(rule)
+	if err != nil {
+		return false
+	}
+	if !startsAt.IsZero() && now.Before(startsAt) {
+		return false
+	}
+	if !endsAt.IsZero() && !now.Before(endsAt) {
+		return false
+	}
+	return true
+}
+
+func containsString(values []string, value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesLanguage matches exact tags and their regional variants, "en" matches "en-us"
+func matchesLanguage(languages []string, language string) bool {
+	if language == "" {
+		return false
+	}
+	for _, l := range languages {
+		if language == l || strings.HasPrefix(language, l+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// detectPlatform returns the operating system named in the user agent, empty if unknown.
+// The order matters since Android user agents mention Linux and iPad ones Mac OS X.
+func detectPlatform(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+	switch {
+	case strings.Contains(ua, "iphone") || strings.Contains(ua, "ipad") || strings.Contains(ua, "ipod"):
+		return "ios"
+	case strings.Contains(ua, "android"):
+		return "android"
+	case strings.Contains(ua, "windows"):
+		return "windows"
+	case strings.Contains(ua, "macintosh") || strings.Contains(ua, "mac os x"):
+		return "macos"
+	case strings.Contains(ua, "linux") || strings.Contains(ua, "x11"):
+		return "linux"
+	}
+	return ""
+}
+
+// preferredLanguage returns the lower cased language with the highest quality in
+// an Accept-Language header, the first one listed on ties
+func preferredLanguage(header string) string {
+	type weightedLanguage struct {
+		tag     string
+		quality float64
+	}
+	var languages []weightedLanguage
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" {
+			continue
+		}
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality > 0 {
+			languages = append(languages, weightedLanguage{tag, quality})
+		}
+	}
+	if len(languages) == 0 {
+		return ""
+	}
+	sort.SliceStable(languages, func(i, j int) bool {
+		return languages[i].quality > languages[j].quality
+	})
+	return languages[0].tag
+}
+
+// visitorCountry looks up the country of the client address, empty without a GeoIP database
+func visitorCountry(r *http.Request) string {
+	if geoDatabase == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return ""
+	}
+	return geoDatabase.Country(addr)
+}
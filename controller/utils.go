@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/gorilla/mux"
 )
@@ -29,6 +30,11 @@ func SetHeader(w http.ResponseWriter, key, value string) {
 	w.Header().Set(key, value)
 }
 
+// acceptsHtml reports whether the request comes from a browser rather than an API client
+func acceptsHtml(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/html")
+}
+
 func ParsePathParam(r *http.Request, pathParam string) (string, error) {
 	vars := mux.Vars(r)
 	value, ok := vars[pathParam]
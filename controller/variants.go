@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"crypto/rand"
+	"math/big"
+	"net/http"
+	"time"
+
+	"URL_SHORTENER/models"
+)
+
+const (
+	variantCookiePrefix   = "variant_"
+	variantCookieLifetime = 30 * 24 * time.Hour
+)
+
+// pickDestination returns where the visitor goes before utm tags and query forwarding
+// are applied: the first matching rule, else one of the variants, else the original url.
+// variant names the variant served, it is empty otherwise. Sticky variants are remembered
+// in a cookie, which is only set when w isn't nil.
+func pickDestination(w http.ResponseWriter, r *http.Request, url *models.Url) (target string, variant string) {
+	if destination, ok := targetDestination(url, r, clock.Now()); ok {
+		return destination, ""
+	}
+	if len(url.Variants) == 0 {
+		return url.OriginalUrl, ""
+	}
+	cookieName := variantCookiePrefix + url.ShortUrl
+	if url.VariantMode == models.VariantModeSticky {
+		if cookie, err := r.Cookie(cookieName); err == nil {
+			for _, v := range url.Variants {
+				if v.Name == cookie.Value && v.Weight > 0 {
+					return v.Destination, v.Name
+				}
+			}
+		}
+	}
+	// Previews don't pick a variant for the visitor
+	if w == nil {
+		return url.OriginalUrl, ""
+	}
+	chosen := chooseVariant(url.Variants)
+	if url.VariantMode == models.VariantModeSticky {
+		http.SetCookie(w, &http.Cookie{
+			Name:     cookieName,
+			Value:    chosen.Name,
+			Path:     "/",
+			MaxAge:   int(variantCookieLifetime.Seconds()),
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+	}
+	return chosen.Destination, chosen.Name
+}
+
+// chooseVariant picks a variant at random in proportion to the weights
+func chooseVariant(variants []models.Variant) *models.Variant {
+	totalWeight := 0
+	for _, v := range variants {
+		totalWeight += v.Weight
+	}
+	n, _ := rand.Int(rand.Reader, big.NewInt(int64(totalWeight)))
+	pick := int(n.Int64())
+	for i := range variants {
+		if pick < variants[i].Weight {
+			return &variants[i]
+		}
+		pick -= variants[i].Weight
+	}
+	return &variants[len(variants)-1]
+}
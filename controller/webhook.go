@@ -0,0 +1,284 @@
+package controller
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"URL_SHORTENER/models"
+	"URL_SHORTENER/service"
+	"URL_SHORTENER/storage"
+	"URL_SHORTENER/webhook"
+)
+
+const (
+	PathParamWebhookId    = "webhook_id"
+	PathParamDeadLetterId = "dead_letter_id"
+	minWebhookSecret      = 16
+	maxClickThresholds    = 20
+)
+
+// webhookDispatcher delivers the link events, nothing is sent while it is nil
+var webhookDispatcher *webhook.Dispatcher
+
+// SetWebhookDispatcher sets the dispatcher the link events are published to
+func SetWebhookDispatcher(d *webhook.Dispatcher) {
+	webhookDispatcher = d
+}
+
+type CreateWebhookRequestParams struct {
+	Url    string   `json:"url"`
+	Events []string `json:"events"`
+	// Secret is generated when empty
+	Secret          string `json:"secret,omitempty"`
+	ClickThresholds []int  `json:"click_thresholds,omitempty"`
+}
+
+// CreateWebhookResponse is the only response showing the secret of the webhook
+type CreateWebhookResponse struct {
+	models.Webhook
+	Secret string `json:"secret"`
+}
+
+// deletedLink is the link of link.deleted events, the url is gone by the time they are sent
+type deletedLink struct {
+	Domain   string `json:"domain"`
+	ShortUrl string `json:"short_url"`
+}
+
+// publishLinkEvent sends the event of the url to the subscribed webhooks
+func publishLinkEvent(eventType string, url *models.Url) {
+	if webhookDispatcher == nil {
+		return
+	}
+	webhookDispatcher.Publish(webhook.Event{Type: eventType, Link: toShortUrlResponse(url), Clicks: url.ClickCount})
+}
+
+// PublishLinkBroken sends the link.broken event of a url whose destination the health check found broken
+func PublishLinkBroken(url *models.Url) {
+	publishLinkEvent(webhook.EventLinkBroken, url)
+}
+
+// publishLinkUpdated sends the link.updated event of a url that was changed in the store
+func publishLinkUpdated(domain string, shortUrl string) {
+	if webhookDispatcher == nil {
+		return
+	}
+	url, err := store.GetOriginalUrl(domain, shortUrl)
+	if err != nil {
+		return
+	}
+	publishLinkEvent(webhook.EventLinkUpdated, url)
+}
+
+// publishLinkDeleted sends the link.deleted event of a deleted url
+func publishLinkDeleted(domain string, shortUrl string) {
+	if webhookDispatcher == nil {
+		return
+	}
+	webhookDispatcher.Publish(webhook.Event{Type: webhook.EventLinkDeleted, Link: deletedLink{Domain: domain, ShortUrl: shortUrl}})
+}
+
+func CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	params := new(CreateWebhookRequestParams)
+	if err := json.NewDecoder(r.Body).Decode(params); err != nil {
+		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: "Invalid request parameters"})
+		return
+	}
+	if err := validateCreateWebhookParams(params); err != nil {
+		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	secret := params.Secret
+	if secret == "" {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			ServerResponse(w, http.StatusInternalServerError, ErrorResponse{Error: "Error creating webhook."})
+			return
+		}
+		secret = hex.EncodeToString(key)
+	}
+	thresholds := append([]int{}, params.ClickThresholds...)
+	sort.Ints(thresholds)
+	hook := &models.Webhook{
+		Url:             params.Url,
+		Events:          params.Events,
+		Secret:          secret,
+		ClickThresholds: thresholds,
+		CreatedAt:       models.FormatTimestamp(clock.Now()),
+	}
+	if len(hook.ClickThresholds) == 0 {
+		hook.ClickThresholds = nil
+	}
+	if err := store.InsertWebhook(hook); err != nil {
+		ServerResponse(w, http.StatusInternalServerError, ErrorResponse{Error: "Error creating webhook."})
+		return
+	}
+	if webhookDispatcher != nil {
+		webhookDispatcher.SubscriptionsChanged()
+	}
+	ServerResponse(w, http.StatusCreated, CreateWebhookResponse{Webhook: *hook, Secret: secret})
+}
+
+func ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := store.ListWebhooks()
+	if err != nil {
+		ServerResponse(w, http.StatusInternalServerError, ErrorResponse{Error: "Error listing webhooks."})
+		return
+	}
+	ServerResponse(w, http.StatusOK, webhooks)
+}
+
+// DeleteWebhook removes the webhook along with its delivery log and dead letters
+func DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseIdParam(w, r, PathParamWebhookId)
+	if !ok {
+		return
+	}
+	err := store.DeleteWebhook(id)
+	if err != nil {
+		if err.Error() == storage.ErrWebhookDoesNotExist {
+			ServerResponse(w, http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		} else {
+			ServerResponse(w, http.StatusInternalServerError, ErrorResponse{Error: "Error deleting webhook."})
+		}
+		return
+	}
+	if webhookDispatcher != nil {
+		webhookDispatcher.SubscriptionsChanged()
+	}
+	ServerResponse(w, http.StatusOK, "Deletion Successful.")
+}
+
+// ListWebhookDeliveries returns the delivery log of a webhook, newest first
+func ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseIdParam(w, r, PathParamWebhookId)
+	if !ok {
+		return
+	}
+	limit, offset, ok := parsePageParams(w, r)
+	if !ok {
+		return
+	}
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", models.DeliveryPending, models.DeliveryDelivered, models.DeliveryFailed:
+	default:
+		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: "Status must be one of pending, delivered, failed"})
+		return
+	}
+	deliveries, err := store.ListDeliveries(&storage.DeliveryFilter{WebhookId: id, Status: status, Limit: limit, Offset: offset})
+	if err != nil {
+		ServerResponse(w, http.StatusInternalServerError, ErrorResponse{Error: "Error listing deliveries."})
+		return
+	}
+	ServerResponse(w, http.StatusOK, deliveries)
+}
+
+// ListDeadLetters returns the deliveries that failed every attempt, oldest first
+func ListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	limit, offset, ok := parsePageParams(w, r)
+	if !ok {
+		return
+	}
+	deadLetters, err := store.ListDeadLetters(limit, offset)
+	if err != nil {
+		ServerResponse(w, http.StatusInternalServerError, ErrorResponse{Error: "Error listing dead letters."})
+		return
+	}
+	ServerResponse(w, http.StatusOK, deadLetters)
+}
+
+// RetryDeadLetter queues a failed delivery again
+func RetryDeadLetter(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseIdParam(w, r, PathParamDeadLetterId)
+	if !ok {
+		return
+	}
+	err := store.RetryDeadLetter(id, clock.Now())
+	if err != nil {
+		if err.Error() == storage.ErrDeadLetterDoesNotExist {
+			ServerResponse(w, http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		} else {
+			ServerResponse(w, http.StatusInternalServerError, ErrorResponse{Error: "Error retrying dead letter."})
+		}
+		return
+	}
+	ServerResponse(w, http.StatusAccepted, "Delivery queued.")
+}
+
+// parseIdParam parses a numeric path parameter, writing the error response if it is invalid
+func parseIdParam(w http.ResponseWriter, r *http.Request, name string) (int64, bool) {
+	value, err := ParsePathParam(r, name)
+	if err != nil {
+		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return 0, false
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: "Id must be a number"})
+		return 0, false
+	}
+	return id, true
+}
+
+// parsePageParams parses the limit and offset query parameters, writing the error response if they are invalid
+func parsePageParams(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	query := r.URL.Query()
+	limit, err := parseIntParam(query.Get("limit"), service.DefaultListLimit, 1, service.MaxListLimit)
+	if err != nil {
+		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: "limit " + err.Error()})
+		return 0, 0, false
+	}
+	offset, err := parseIntParam(query.Get("offset"), 0, 0, math.MaxInt32)
+	if err != nil {
+		ServerResponse(w, http.StatusBadRequest, ErrorResponse{Error: "offset " + err.Error()})
+		return 0, 0, false
+	}
+	return limit, offset, true
+}
+
+func validateCreateWebhookParams(params *CreateWebhookRequestParams) error {
+	target, err := url.Parse(params.Url)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return errors.New("Webhook url must be an absolute http or https url")
+	}
+	if len(params.Events) == 0 {
+		return errors.New("Events can not be empty")
+	}
+	seen := make(map[string]bool)
+	for _, event := range params.Events {
+		known := false
+		for _, name := range webhook.Events {
+			known = known || event == name
+		}
+		if !known {
+			return errors.New("Events must be link.created, link.updated, link.deleted, link.click_threshold or link.broken")
+		}
+		if seen[event] {
+			return errors.New("Events can not repeat")
+		}
+		seen[event] = true
+	}
+	if seen[webhook.EventLinkClickThreshold] != (len(params.ClickThresholds) > 0) {
+		return errors.New("Click thresholds are required with the link.click_threshold event and only with it")
+	}
+	if len(params.ClickThresholds) > maxClickThresholds {
+		return errors.New("At most 20 click thresholds can be given")
+	}
+	for _, threshold := range params.ClickThresholds {
+		if threshold < 1 {
+			return errors.New("Click thresholds must be positive")
+		}
+	}
+	if params.Secret != "" && len(params.Secret) < minWebhookSecret {
+		return errors.New("Secret must be at least 16 characters")
+	}
+	return nil
+}
@@ -1,9 +1,173 @@
 CREATE TABLE IF NOT EXISTS "urls" (
-	original_url TEXT PRIMARY KEY NOT NULL,
+	-- namespace of the short url, empty for the default one
+	domain TEXT NOT NULL DEFAULT '',
+	original_url TEXT NOT NULL,
 	short_url TEXT NOT NULL,
+	-- UTC in RFC 3339, e.g. 2024-10-16T21:05:18Z
+	created_at TEXT NOT NULL,
+	-- bcrypt hash of the link password, empty when the link is not protected
+	password_hash TEXT NOT NULL DEFAULT '',
+	-- click limit of the link and clicks left, NULL for unlimited links
+	max_clicks INTEGER,
+	remaining_clicks INTEGER,
+	title TEXT NOT NULL DEFAULT '',
+	description TEXT NOT NULL DEFAULT '',
+	-- arbitrary JSON object supplied by the client
+	metadata TEXT NOT NULL DEFAULT '{}',
+	-- fetched from the destination page, page_fetched_at stays empty until it was fetched
+	page_title TEXT NOT NULL DEFAULT '',
+	page_description TEXT NOT NULL DEFAULT '',
+	page_image TEXT NOT NULL DEFAULT '',
+	page_site_name TEXT NOT NULL DEFAULT '',
+	favicon_url TEXT NOT NULL DEFAULT '',
+	page_fetched_at TEXT NOT NULL DEFAULT '',
+	page_fetch_error TEXT NOT NULL DEFAULT '',
+	-- number of times the url was followed
+	click_count INTEGER NOT NULL DEFAULT 0,
+	-- campaign tags added to the original url when it is followed
+	utm_source TEXT NOT NULL DEFAULT '',
+	utm_medium TEXT NOT NULL DEFAULT '',
+	utm_campaign TEXT NOT NULL DEFAULT '',
+	utm_term TEXT NOT NULL DEFAULT '',
+	utm_content TEXT NOT NULL DEFAULT '',
+	-- whether the query string of the short url request is appended to the original url
+	forward_query INTEGER NOT NULL DEFAULT 0,
+	-- ordered targeting rules as a JSON array
+	rules TEXT NOT NULL DEFAULT '[]',
+	-- sticky to keep serving a visitor the same variant, random otherwise
+	variant_mode TEXT NOT NULL DEFAULT '',
+	-- why an admin disabled the url or the blocklist matched a destination
+	status_reason TEXT NOT NULL DEFAULT '',
+	-- active, disabled or blocked, only active urls are followed
+	status TEXT NOT NULL DEFAULT 'active',
+	-- last health check of the original url, health_checked_at stays empty until it was checked
+	health_status TEXT NOT NULL DEFAULT '',
+	health_status_code INTEGER NOT NULL DEFAULT 0,
+	health_latency_ms INTEGER NOT NULL DEFAULT 0,
+	health_error TEXT NOT NULL DEFAULT '',
+	health_checked_at TEXT NOT NULL DEFAULT '',
+	-- counts the changes of the settings of the url, the ETag of its responses
+	version INTEGER NOT NULL DEFAULT 1,
+	PRIMARY KEY (domain, original_url)
+);
+
+-- Create unique index on the short_url of each domain
+CREATE UNIQUE INDEX IF NOT EXISTS idx_urls_domain_short_url ON urls (domain, short_url);
+CREATE INDEX IF NOT EXISTS idx_urls_domain_health_status ON urls (domain, health_status);
+
+-- Tags shared by all urls
+CREATE TABLE IF NOT EXISTS "tags" (
+	id INTEGER PRIMARY KEY,
+	name TEXT NOT NULL UNIQUE
+);
+
+-- Tags of each url, keyed by the url primary key since the short_url can change
+CREATE TABLE IF NOT EXISTS "url_tags" (
+	domain TEXT NOT NULL,
+	original_url TEXT NOT NULL,
+	tag_id INTEGER NOT NULL,
+	PRIMARY KEY (domain, original_url, tag_id)
+);
+CREATE INDEX IF NOT EXISTS idx_url_tags_tag_id ON url_tags (tag_id);
+
+-- Branded short domains and their defaults
+CREATE TABLE IF NOT EXISTS "domains" (
+	name TEXT PRIMARY KEY NOT NULL,
+	code_length INTEGER NOT NULL,
+	redirect_type INTEGER NOT NULL,
+	fallback_url TEXT NOT NULL DEFAULT '',
+	created_at TEXT NOT NULL
+);
+
+-- Weighted destinations of each url and the clicks each of them served
+CREATE TABLE IF NOT EXISTS "url_variants" (
+	domain TEXT NOT NULL,
+	original_url TEXT NOT NULL,
+	name TEXT NOT NULL,
+	destination TEXT NOT NULL,
+	weight INTEGER NOT NULL,
+	position INTEGER NOT NULL,
+	click_count INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (domain, original_url, name)
+);
+
+-- Abuse reports of short urls, reviewed_at stays empty until an admin reviewed the report
+CREATE TABLE IF NOT EXISTS "abuse_reports" (
+	id INTEGER PRIMARY KEY,
+	domain TEXT NOT NULL,
+	short_url TEXT NOT NULL,
+	reason TEXT NOT NULL,
+	details TEXT NOT NULL DEFAULT '',
+	email TEXT NOT NULL DEFAULT '',
+	created_at TEXT NOT NULL,
+	reviewed_at TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_abuse_reports_reviewed_at ON abuse_reports (reviewed_at);
+
+-- Webhook subscriptions, events and click_thresholds are JSON arrays
+CREATE TABLE IF NOT EXISTS "webhooks" (
+	id INTEGER PRIMARY KEY,
+	url TEXT NOT NULL,
+	events TEXT NOT NULL,
+	-- HMAC-SHA256 key signing the deliveries
+	secret TEXT NOT NULL,
+	click_thresholds TEXT NOT NULL DEFAULT '[]',
 	created_at TEXT NOT NULL
 );
 
--- Create index on the short_url
-CREATE index IF NOT EXISTS idx_short ON urls (short_url);
+-- Events queued for each webhook, kept as the delivery log once sent
+CREATE TABLE IF NOT EXISTS "webhook_deliveries" (
+	id INTEGER PRIMARY KEY,
+	webhook_id INTEGER NOT NULL,
+	event TEXT NOT NULL,
+	payload TEXT NOT NULL,
+	-- pending, delivered or failed
+	status TEXT NOT NULL,
+	attempts INTEGER NOT NULL DEFAULT 0,
+	response_status INTEGER NOT NULL DEFAULT 0,
+	last_error TEXT NOT NULL DEFAULT '',
+	created_at TEXT NOT NULL,
+	delivered_at TEXT NOT NULL DEFAULT '',
+	-- unix milliseconds of the next attempt of a pending delivery
+	next_attempt_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id ON webhook_deliveries (webhook_id, id);
 
+-- Deliveries that failed every attempt, until they are retried
+CREATE TABLE IF NOT EXISTS "webhook_dead_letters" (
+	id INTEGER PRIMARY KEY,
+	delivery_id INTEGER NOT NULL UNIQUE,
+	webhook_id INTEGER NOT NULL,
+	event TEXT NOT NULL,
+	payload TEXT NOT NULL,
+	attempts INTEGER NOT NULL,
+	last_error TEXT NOT NULL,
+	failed_at TEXT NOT NULL
+);
+
+-- Responses of create requests sent with an Idempotency-Key, replayed when the request is retried
+CREATE TABLE IF NOT EXISTS "idempotency_keys" (
+	idempotency_key TEXT PRIMARY KEY,
+	-- sha256 of the request the key was first sent with
+	request_hash TEXT NOT NULL,
+	-- status code of the response, 0 while the request is being handled
+	status INTEGER NOT NULL DEFAULT 0,
+	response TEXT NOT NULL DEFAULT '',
+	-- unix milliseconds of the first request, the key expires once the window has passed
+	created_at INTEGER NOT NULL,
+	-- JSON object of the response headers replayed with the response
+	headers TEXT NOT NULL DEFAULT '{}'
+);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys (created_at);
+
+-- Wrong passwords given for a password protected link, shared by every process serving it
+CREATE TABLE IF NOT EXISTS "password_lockouts" (
+	domain TEXT NOT NULL,
+	short_url TEXT NOT NULL,
+	-- wrong passwords in a row, starts over when the link is locked
+	failed_attempts INTEGER NOT NULL DEFAULT 0,
+	-- unix milliseconds until no password is accepted, 0 when it was never locked
+	locked_until INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (domain, short_url)
+);
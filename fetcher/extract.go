@@ -0,0 +1,103 @@
+package fetcher
+
+import (
+	"io"
+	"net/url"
+	"strings"
+
+	"URL_SHORTENER/models"
+
+	"golang.org/x/net/html"
+)
+
+// extractPageMetadata reads the <title>, OpenGraph tags and favicon from the head of an HTML page.
+// Relative urls are resolved against pageUrl.
+func extractPageMetadata(body io.Reader, pageUrl *url.URL) *models.PageMetadata {
+	page := &models.PageMetadata{}
+	var title, description, favicon string
+	tokenizer := html.NewTokenizer(body)
+	inTitle := false
+	for {
+		tokenType := tokenizer.Next()
+		switch tokenType {
+		case html.ErrorToken:
+			return finishPageMetadata(page, pageUrl, title, description, favicon)
+		case html.TextToken:
+			if inTitle && title == "" {
+				title = strings.TrimSpace(string(tokenizer.Text()))
+			}
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			switch string(name) {
+			case "title":
+				inTitle = false
+			case "head":
+				// Everything of interest lives in the head
+				return finishPageMetadata(page, pageUrl, title, description, favicon)
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := tokenizer.TagName()
+			attrs := make(map[string]string)
+			for hasAttr {
+				var key, value []byte
+				key, value, hasAttr = tokenizer.TagAttr()
+				attrs[string(key)] = string(value)
+			}
+			switch string(name) {
+			case "title":
+				inTitle = tokenType == html.StartTagToken
+			case "meta":
+				property := strings.ToLower(attrs["property"])
+				if property == "" {
+					property = strings.ToLower(attrs["name"])
+				}
+				content := strings.TrimSpace(attrs["content"])
+				switch property {
+				case "og:title":
+					page.Title = content
+				case "og:description":
+					page.Description = content
+				case "description":
+					description = content
+				case "og:image":
+					page.Image = content
+				case "og:site_name":
+					page.SiteName = content
+				}
+			case "link":
+				rel := strings.ToLower(attrs["rel"])
+				if favicon == "" && (rel == "icon" || rel == "shortcut icon" || rel == "apple-touch-icon") {
+					favicon = attrs["href"]
+				}
+			}
+		}
+	}
+}
+
+// finishPageMetadata falls back to the plain HTML title and description when there
+// are no OpenGraph tags and makes the image and favicon urls absolute
+func finishPageMetadata(page *models.PageMetadata, pageUrl *url.URL, title, description, favicon string) *models.PageMetadata {
+	if page.Title == "" {
+		page.Title = title
+	}
+	if page.Description == "" {
+		page.Description = description
+	}
+	page.Image = resolveReference(pageUrl, page.Image)
+	if favicon == "" {
+		favicon = "/favicon.ico"
+	}
+	page.FaviconUrl = resolveReference(pageUrl, favicon)
+	return page
+}
+
+func resolveReference(base *url.URL, reference string) string {
+	if reference == "" {
+		return ""
+	}
+	parsed, err := url.Parse(reference)
+	if err != nil {
+		return ""
+	}
+	return base.ResolveReference(parsed).String()
+}
@@ -0,0 +1,197 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"URL_SHORTENER/models"
+)
+
+const userAgent = "URL_SHORTENER-metadata-fetcher/1.0"
+
+type Config struct {
+	// Workers is the number of pages fetched concurrently
+	Workers int
+	// QueueSize is how many pending jobs are buffered before Enqueue drops new ones
+	QueueSize    int
+	Timeout      time.Duration
+	MaxBodyBytes int64
+	MaxRedirects int
+	// MaxAttempts is how often a page is tried before the error is recorded
+	MaxAttempts int
+	// RetryBackoff is the wait before the first retry, it doubles on each further one
+	RetryBackoff time.Duration
+	// AllowPrivateAddresses disables the SSRF protection, only meant for tests and local development
+	AllowPrivateAddresses bool
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Workers:      2,
+		QueueSize:    1000,
+		Timeout:      10 * time.Second,
+		MaxBodyBytes: 1 << 20,
+		MaxRedirects: 5,
+		MaxAttempts:  3,
+		RetryBackoff: 2 * time.Second,
+	}
+}
+
+// PageMetadataStore is where fetched page metadata is saved
+type PageMetadataStore interface {
+	SetPageMetadata(domain string, originalUrl string, page *models.PageMetadata) error
+}
+
+// Job identifies the url whose destination page is fetched
+type Job struct {
+	Domain      string
+	OriginalUrl string
+}
+
+// Fetcher fetches destination pages in the background and stores their metadata
+type Fetcher struct {
+	store    PageMetadataStore
+	config   Config
+	client   *http.Client
+	jobs     chan Job
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+func NewFetcher(store PageMetadataStore, config Config) *Fetcher {
+	return &Fetcher{
+		store:  store,
+		config: config,
+		client: newHTTPClient(config.Timeout, config.MaxRedirects, config.AllowPrivateAddresses),
+		jobs:   make(chan Job, config.QueueSize),
+		stop:   make(chan struct{}),
+	}
+}
+
+// Start launches the workers
+func (f *Fetcher) Start() {
+	for i := 0; i < f.config.Workers; i++ {
+		f.wg.Add(1)
+		go f.worker()
+	}
+}
+
+// Stop makes the workers finish the page they are on and waits for them.
+// Jobs still in the queue are dropped.
+func (f *Fetcher) Stop() {
+	f.stopOnce.Do(func() {
+		close(f.stop)
+	})
+	f.wg.Wait()
+}
+
+// Enqueue schedules the job without blocking. It reports false if the queue is full.
+func (f *Fetcher) Enqueue(job Job) bool {
+	select {
+	case f.jobs <- job:
+		return true
+	default:
+		log.Printf("metadata fetcher queue full, dropping %s", job.OriginalUrl)
+		return false
+	}
+}
+
+func (f *Fetcher) worker() {
+	defer f.wg.Done()
+	for {
+		select {
+		case <-f.stop:
+			return
+		case job := <-f.jobs:
+			f.process(job)
+		}
+	}
+}
+
+// process fetches the page with retries and stores the result, or the last error
+func (f *Fetcher) process(job Job) {
+	var page *models.PageMetadata
+	var err error
+	backoff := f.config.RetryBackoff
+	for attempt := 1; attempt <= f.config.MaxAttempts; attempt++ {
+		page, err = f.Fetch(job.OriginalUrl)
+		var retryable *retryableError
+		if err == nil || !errors.As(err, &retryable) || attempt == f.config.MaxAttempts {
+			break
+		}
+		select {
+		case <-f.stop:
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	if err != nil {
+		page = &models.PageMetadata{FetchError: err.Error()}
+	}
+	page.FetchedAt = models.FormatTimestamp(time.Now())
+	if err = f.store.SetPageMetadata(job.Domain, job.OriginalUrl, page); err != nil {
+		log.Printf("failed to store page metadata of %s: %v", job.OriginalUrl, err)
+	}
+}
+
+// retryableError marks failures that may go away on a later attempt
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryableError) Unwrap() error {
+	return e.err
+}
+
+// Fetch downloads a page once and extracts its metadata
+func (f *Fetcher) Fetch(rawUrl string) (*models.PageMetadata, error) {
+	pageUrl, err := url.Parse(rawUrl)
+	if err != nil || (pageUrl.Scheme != "http" && pageUrl.Scheme != "https") {
+		return nil, fmt.Errorf("unsupported url %q", rawUrl)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), f.config.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageUrl.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	res, err := f.client.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrPrivateAddress) {
+			return nil, err
+		}
+		return nil, &retryableError{err}
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= http.StatusInternalServerError {
+		return nil, &retryableError{fmt.Errorf("destination answered %s", res.Status)}
+	}
+	if res.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("destination answered %s", res.Status)
+	}
+	// Resolve relative links against the page reached after redirects
+	finalUrl := res.Request.URL
+	mediaType, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return finishPageMetadata(&models.PageMetadata{}, finalUrl, "", "", ""), nil
+	}
+	return extractPageMetadata(io.LimitReader(res.Body, f.config.MaxBodyBytes), finalUrl), nil
+}
@@ -0,0 +1,157 @@
+package fetcher
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"URL_SHORTENER/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testPage = `<!DOCTYPE html>
+<html>
+<head>
+	<title> Plain title </title>
+	<meta name="description" content="Plain description">
+	<meta property="og:title" content="OpenGraph title">
+	<meta property="og:image" content="/images/cover.png">
+	<meta property="og:site_name" content="Example">
+	<link rel="icon" href="/static/icon.png">
+</head>
+<body><title>Not the title</title></body>
+</html>`
+
+// fakeStore records the page metadata handed to SetPageMetadata
+type fakeStore struct {
+	pages chan *models.PageMetadata
+}
+
+func (s *fakeStore) SetPageMetadata(domain string, originalUrl string, page *models.PageMetadata) error {
+	s.pages <- page
+	return nil
+}
+
+func testConfig() Config {
+	config := DefaultConfig()
+	config.Timeout = 2 * time.Second
+	config.RetryBackoff = time.Millisecond
+	config.AllowPrivateAddresses = true
+	return config
+}
+
+func TestExtractPageMetadata(t *testing.T) {
+	pageUrl, _ := url.Parse("https://example.com/blog/post")
+
+	t.Run("OpenGraph tags win over plain tags", func(t *testing.T) {
+		page := extractPageMetadata(strings.NewReader(testPage), pageUrl)
+		require.Equal(t, "OpenGraph title", page.Title)
+		require.Equal(t, "Plain description", page.Description)
+		require.Equal(t, "https://example.com/images/cover.png", page.Image)
+		require.Equal(t, "Example", page.SiteName)
+		require.Equal(t, "https://example.com/static/icon.png", page.FaviconUrl)
+	})
+
+	t.Run("Plain title and default favicon", func(t *testing.T) {
+		page := extractPageMetadata(strings.NewReader(`<html><head><title>Hello</title></head></html>`), pageUrl)
+		require.Equal(t, "Hello", page.Title)
+		require.Equal(t, "https://example.com/favicon.ico", page.FaviconUrl)
+	})
+}
+
+func TestFetch(t *testing.T) {
+
+	t.Run("Private addresses are refused", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Error("the request should not have reached the server")
+		}))
+		defer server.Close()
+
+		config := testConfig()
+		config.AllowPrivateAddresses = false
+		_, err := NewFetcher(&fakeStore{}, config).Fetch(server.URL)
+		require.True(t, errors.Is(err, ErrPrivateAddress), err)
+	})
+
+	t.Run("Unsupported scheme", func(t *testing.T) {
+		_, err := NewFetcher(&fakeStore{}, testConfig()).Fetch("file:///etc/passwd")
+		require.Error(t, err)
+	})
+
+	t.Run("Body size limit", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			// The title lies beyond the size limit
+			_, _ = fmt.Fprintf(w, "<html><head><!--%s--><title>Too far</title></head></html>", strings.Repeat("x", 2048))
+		}))
+		defer server.Close()
+
+		config := testConfig()
+		config.MaxBodyBytes = 1024
+		page, err := NewFetcher(&fakeStore{}, config).Fetch(server.URL)
+		require.NoError(t, err)
+		require.Empty(t, page.Title)
+	})
+}
+
+func TestFetcherWorkers(t *testing.T) {
+
+	t.Run("Retries until the page is fetched", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if requests.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = w.Write([]byte(testPage))
+		}))
+		defer server.Close()
+
+		store := &fakeStore{pages: make(chan *models.PageMetadata, 1)}
+		f := NewFetcher(store, testConfig())
+		f.Start()
+		defer f.Stop()
+		require.True(t, f.Enqueue(Job{OriginalUrl: server.URL}))
+
+		select {
+		case page := <-store.pages:
+			require.Equal(t, "OpenGraph title", page.Title)
+			require.Empty(t, page.FetchError)
+			require.NotEmpty(t, page.FetchedAt)
+		case <-time.After(5 * time.Second):
+			t.Fatal("page metadata was not stored")
+		}
+		require.Equal(t, int32(3), requests.Load())
+	})
+
+	t.Run("Client errors are recorded without retrying", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		store := &fakeStore{pages: make(chan *models.PageMetadata, 1)}
+		f := NewFetcher(store, testConfig())
+		f.Start()
+		defer f.Stop()
+		f.Enqueue(Job{OriginalUrl: server.URL})
+
+		select {
+		case page := <-store.pages:
+			require.Contains(t, page.FetchError, "404")
+		case <-time.After(5 * time.Second):
+			t.Fatal("fetch error was not stored")
+		}
+		require.Equal(t, int32(1), requests.Load())
+	})
+}
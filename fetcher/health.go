@@ -0,0 +1,307 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"URL_SHORTENER/models"
+	"URL_SHORTENER/storage"
+)
+
+const healthUserAgent = "URL_SHORTENER-link-checker/1.0"
+
+type HealthConfig struct {
+	// Interval is how long the outcome of a check is kept before the destination is checked again
+	Interval time.Duration
+	// ScanInterval is how often the checker looks for destinations due for a check
+	ScanInterval time.Duration
+	// Workers is the number of destinations checked concurrently
+	Workers int
+	// HostDelay is the least time between two requests to the same host
+	HostDelay    time.Duration
+	Timeout      time.Duration
+	MaxRedirects int
+	// AllowPrivateAddresses disables the SSRF protection, only meant for tests and local development
+	AllowPrivateAddresses bool
+}
+
+func DefaultHealthConfig() HealthConfig {
+	return HealthConfig{
+		Interval:     24 * time.Hour,
+		ScanInterval: time.Hour,
+		Workers:      4,
+		HostDelay:    time.Second,
+		Timeout:      10 * time.Second,
+		MaxRedirects: 5,
+	}
+}
+
+// LinkHealthStore is where the checker finds the urls and saves the outcome of their checks
+type LinkHealthStore interface {
+	ListDomains() ([]models.Domain, error)
+	ExportUrls(filter *storage.UrlFilter, fn func(url *models.Url) error) error
+	SetLinkHealth(domain string, originalUrl string, health *models.LinkHealth) error
+}
+
+// healthJob is a destination to check and the urls leading there
+type healthJob struct {
+	originalUrl string
+	urls        []models.Url
+}
+
+// HealthChecker periodically requests the original urls of the active urls and records whether
+// they still answer. Each destination is checked once per pass, however many urls lead there.
+// The health is stored per original url, so rule and variant destinations are not checked.
+type HealthChecker struct {
+	store  LinkHealthStore
+	config HealthConfig
+	client *http.Client
+	// onBroken is called for urls whose destination broke since the last check, it may be nil
+	onBroken func(url *models.Url)
+	// hostNext is the earliest time the next request to each host may start
+	hostMutex sync.Mutex
+	hostNext  map[string]time.Time
+	ctx       context.Context
+	cancel    context.CancelFunc
+	stopOnce  sync.Once
+	wg        sync.WaitGroup
+}
+
+func NewHealthChecker(store LinkHealthStore, config HealthConfig, onBroken func(url *models.Url)) *HealthChecker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &HealthChecker{
+		store:    store,
+		config:   config,
+		client:   newHTTPClient(config.Timeout, config.MaxRedirects, config.AllowPrivateAddresses),
+		onBroken: onBroken,
+		hostNext: make(map[string]time.Time),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Start checks the due destinations once and then every ScanInterval in the background
+func (c *HealthChecker) Start() {
+	c.wg.Add(1)
+	go c.run()
+}
+
+// Stop cancels the running checks and waits for the checker to exit
+func (c *HealthChecker) Stop() {
+	c.stopOnce.Do(c.cancel)
+	c.wg.Wait()
+}
+
+func (c *HealthChecker) run() {
+	defer c.wg.Done()
+	ticker := time.NewTicker(c.config.ScanInterval)
+	defer ticker.Stop()
+	for {
+		checked, err := c.CheckDue()
+		if err != nil {
+			log.Printf("link health check failed: %v", err)
+		}
+		if checked > 0 {
+			log.Printf("link health check checked %d destinations", checked)
+		}
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckDue checks the destinations of the active urls not checked within the Interval.
+// It returns the number of destinations checked.
+func (c *HealthChecker) CheckDue() (int, error) {
+	jobs, err := c.dueJobs()
+	if err != nil {
+		return 0, err
+	}
+	c.forgetIdleHosts()
+	queue := make(chan healthJob)
+	var checked int
+	var checkedMutex sync.Mutex
+	var workers sync.WaitGroup
+	for i := 0; i < c.config.Workers; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for job := range queue {
+				if c.check(job) {
+					checkedMutex.Lock()
+					checked++
+					checkedMutex.Unlock()
+				}
+			}
+		}()
+	}
+	for _, job := range jobs {
+		select {
+		case queue <- job:
+		case <-c.ctx.Done():
+		}
+	}
+	close(queue)
+	workers.Wait()
+	return checked, nil
+}
+
+// dueJobs groups the urls due for a check by destination, in the order they were found
+func (c *HealthChecker) dueJobs() ([]healthJob, error) {
+	domains, err := c.store.ListDomains()
+	if err != nil {
+		return nil, err
+	}
+	names := []string{""}
+	for _, domain := range domains {
+		names = append(names, domain.Name)
+	}
+	dueBefore := time.Now().Add(-c.config.Interval)
+	var jobs []healthJob
+	index := make(map[string]int)
+	for _, name := range names {
+		err = c.store.ExportUrls(&storage.UrlFilter{Domain: name, Status: models.StatusActive}, func(url *models.Url) error {
+			if url.Health != nil {
+				checkedAt, err := time.Parse(time.RFC3339, url.Health.CheckedAt)
+				if err == nil && checkedAt.After(dueBefore) {
+					return nil
+				}
+			}
+			if i, ok := index[url.OriginalUrl]; ok {
+				jobs[i].urls = append(jobs[i].urls, *url)
+				return nil
+			}
+			index[url.OriginalUrl] = len(jobs)
+			jobs = append(jobs, healthJob{originalUrl: url.OriginalUrl, urls: []models.Url{*url}})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return jobs, nil
+}
+
+// check requests the destination of the job and stores the outcome on its urls.
+// It returns false when the checker was stopped before the check finished.
+func (c *HealthChecker) check(job healthJob) bool {
+	target, err := url.Parse(job.originalUrl)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") {
+		c.record(job, &models.LinkHealth{Status: models.HealthBroken, Error: "the original url is not an http or https url"})
+		return true
+	}
+	if !c.waitForHost(target.Hostname()) {
+		return false
+	}
+	started := time.Now()
+	statusCode, err := c.request(target.String())
+	if c.ctx.Err() != nil {
+		return false
+	}
+	health := &models.LinkHealth{Status: models.HealthOk, StatusCode: statusCode, LatencyMs: time.Since(started).Milliseconds()}
+	if err != nil {
+		health.Status = models.HealthBroken
+		health.Error = err.Error()
+	} else if brokenStatus(statusCode) {
+		health.Status = models.HealthBroken
+		health.Error = fmt.Sprintf("the destination answered %d %s", statusCode, http.StatusText(statusCode))
+	}
+	c.record(job, health)
+	return true
+}
+
+// record stores the outcome on every url of the job and reports those that just broke
+func (c *HealthChecker) record(job healthJob, health *models.LinkHealth) {
+	health.CheckedAt = models.FormatTimestamp(time.Now())
+	for i := range job.urls {
+		url := &job.urls[i]
+		err := c.store.SetLinkHealth(url.Domain, url.OriginalUrl, health)
+		if err != nil {
+			if err.Error() != storage.ErrShortURLDoesNotExist {
+				log.Printf("storing the health of %s failed: %v", url.OriginalUrl, err)
+			}
+			continue
+		}
+		broke := health.Status == models.HealthBroken && (url.Health == nil || url.Health.Status != models.HealthBroken)
+		url.Health = health
+		if broke && c.onBroken != nil {
+			c.onBroken(url)
+		}
+	}
+}
+
+// request sends a HEAD request to the destination, or a GET request to servers not answering HEAD,
+// and returns the status of the response at the end of the redirects
+func (c *HealthChecker) request(target string) (int, error) {
+	statusCode, err := c.send(http.MethodHead, target)
+	if err == nil && (statusCode == http.StatusMethodNotAllowed || statusCode == http.StatusNotImplemented) {
+		statusCode, err = c.send(http.MethodGet, target)
+	}
+	return statusCode, err
+}
+
+func (c *HealthChecker) send(method string, target string) (int, error) {
+	req, err := http.NewRequestWithContext(c.ctx, method, target, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", healthUserAgent)
+	res, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	// Only the status is needed, the body of GET responses is left unread
+	_ = res.Body.Close()
+	return res.StatusCode, nil
+}
+
+// waitForHost waits until a request to the host may start and reserves the slot after it,
+// so the host sees at most one request every HostDelay. It returns false when the checker stops.
+func (c *HealthChecker) waitForHost(host string) bool {
+	c.hostMutex.Lock()
+	now := time.Now()
+	start := c.hostNext[host]
+	if start.Before(now) {
+		start = now
+	}
+	c.hostNext[host] = start.Add(c.config.HostDelay)
+	c.hostMutex.Unlock()
+
+	timer := time.NewTimer(time.Until(start))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-c.ctx.Done():
+		return false
+	}
+}
+
+// forgetIdleHosts drops the hosts that may be requested right away, so only the hosts of a pass are kept
+func (c *HealthChecker) forgetIdleHosts() {
+	c.hostMutex.Lock()
+	defer c.hostMutex.Unlock()
+	now := time.Now()
+	for host, next := range c.hostNext {
+		if next.Before(now) {
+			delete(c.hostNext, host)
+		}
+	}
+}
+
+// brokenStatus tells whether the status means the destination is gone or failing. Answers refusing
+// the checker, such as 401, 403 and 429, show that the page still exists.
+func brokenStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests:
+		return false
+	}
+	return statusCode >= 400
+}
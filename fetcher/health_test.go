@@ -0,0 +1,208 @@
+package fetcher
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"URL_SHORTENER/models"
+	"URL_SHORTENER/storage"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeHealthStore serves the urls of each domain and keeps the health stored for them
+type fakeHealthStore struct {
+	mu   sync.Mutex
+	urls map[string][]models.Url
+}
+
+func (s *fakeHealthStore) ListDomains() ([]models.Domain, error) {
+	return []models.Domain{{Name: "sho.rt"}}, nil
+}
+
+func (s *fakeHealthStore) ExportUrls(filter *storage.UrlFilter, fn func(url *models.Url) error) error {
+	s.mu.Lock()
+	urls := append([]models.Url{}, s.urls[filter.Domain]...)
+	s.mu.Unlock()
+	for i := range urls {
+		if urls[i].Status != filter.Status {
+			continue
+		}
+		if err := fn(&urls[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *fakeHealthStore) SetLinkHealth(domain string, originalUrl string, health *models.LinkHealth) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, url := range s.urls[domain] {
+		if url.OriginalUrl == originalUrl {
+			stored := *health
+			s.urls[domain][i].Health = &stored
+		}
+	}
+	return nil
+}
+
+func (s *fakeHealthStore) health(domain string, originalUrl string) *models.LinkHealth {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, url := range s.urls[domain] {
+		if url.OriginalUrl == originalUrl {
+			return url.Health
+		}
+	}
+	return nil
+}
+
+func testHealthConfig() HealthConfig {
+	config := DefaultHealthConfig()
+	config.Timeout = 2 * time.Second
+	config.HostDelay = 0
+	config.AllowPrivateAddresses = true
+	return config
+}
+
+func TestHealthChecker(t *testing.T) {
+	var mu sync.Mutex
+	requests := make(map[string][]string)
+	var started []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path] = append(requests[r.URL.Path], r.Method)
+		started = append(started, time.Now())
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/gone":
+			w.WriteHeader(http.StatusNotFound)
+		case "/moved":
+			http.Redirect(w, r, "/ok", http.StatusMovedPermanently)
+		case "/get-only":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+		case "/members":
+			w.WriteHeader(http.StatusForbidden)
+		case "/error":
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	active := func(domain string, path string) models.Url {
+		return models.Url{Domain: domain, ShortUrl: path, OriginalUrl: server.URL + path, Status: models.StatusActive}
+	}
+	newStore := func() *fakeHealthStore {
+		return &fakeHealthStore{urls: map[string][]models.Url{
+			"": {active("", "/ok"), active("", "/gone"), active("", "/moved"), active("", "/get-only"),
+				active("", "/members"), active("", "/error"),
+				{ShortUrl: "disabled", OriginalUrl: server.URL + "/disabled", Status: models.StatusDisabled}},
+			"sho.rt": {active("sho.rt", "/gone")},
+		}}
+	}
+	reset := func() {
+		mu.Lock()
+		requests = make(map[string][]string)
+		started = nil
+		mu.Unlock()
+	}
+
+	t.Run("Outcomes are recorded", func(t *testing.T) {
+		reset()
+		store := newStore()
+		var broken []string
+		var brokenMutex sync.Mutex
+		checker := NewHealthChecker(store, testHealthConfig(), func(url *models.Url) {
+			brokenMutex.Lock()
+			broken = append(broken, url.Domain+url.ShortUrl)
+			brokenMutex.Unlock()
+		})
+		checked, err := checker.CheckDue()
+		require.NoError(t, err)
+		require.Equal(t, 6, checked)
+
+		for path, expected := range map[string]int{"/ok": 200, "/moved": 200, "/get-only": 200, "/members": 403} {
+			health := store.health("", server.URL+path)
+			require.Equal(t, models.HealthOk, health.Status, path)
+			require.Equal(t, expected, health.StatusCode, path)
+			require.NotEmpty(t, health.CheckedAt)
+		}
+		for path, expected := range map[string]string{"/gone": "the destination answered 404 Not Found", "/error": "the destination answered 502 Bad Gateway"} {
+			health := store.health("", server.URL+path)
+			require.Equal(t, models.HealthBroken, health.Status, path)
+			require.Equal(t, expected, health.Error, path)
+		}
+		require.Equal(t, models.HealthBroken, store.health("sho.rt", server.URL+"/gone").Status)
+		require.Nil(t, store.health("", server.URL+"/disabled"))
+		require.ElementsMatch(t, []string{"/gone", "sho.rt/gone", "/error"}, broken)
+
+		// A destination shared by several urls is requested once, servers without HEAD get a GET
+		require.Equal(t, []string{http.MethodHead}, requests["/gone"])
+		require.Equal(t, []string{http.MethodHead, http.MethodGet}, requests["/get-only"])
+		require.Empty(t, requests["/disabled"])
+
+		// Recent checks aren't repeated, and broken links are only reported when they break
+		checked, err = checker.CheckDue()
+		require.NoError(t, err)
+		require.Zero(t, checked)
+		config := testHealthConfig()
+		config.Interval = 0
+		checked, err = NewHealthChecker(store, config, func(url *models.Url) {
+			t.Errorf("%s was reported again", url.ShortUrl)
+		}).CheckDue()
+		require.NoError(t, err)
+		require.Equal(t, 6, checked)
+	})
+
+	t.Run("Requests to a host are spaced out", func(t *testing.T) {
+		reset()
+		config := testHealthConfig()
+		config.Workers = 3
+		config.HostDelay = 30 * time.Millisecond
+		_, err := NewHealthChecker(newStore(), config, nil).CheckDue()
+		require.NoError(t, err)
+		mu.Lock()
+		defer mu.Unlock()
+		require.Len(t, started, 8)
+		for i := 1; i < len(started); i++ {
+			// A GET after a HEAD and a followed redirect are part of one check
+			if started[i].Sub(started[i-1]) < 5*time.Millisecond {
+				continue
+			}
+			require.GreaterOrEqual(t, started[i].Sub(started[i-1]), 25*time.Millisecond)
+		}
+		require.GreaterOrEqual(t, started[len(started)-1].Sub(started[0]), 5*config.HostDelay-5*time.Millisecond)
+	})
+
+	t.Run("Stop ends a running check", func(t *testing.T) {
+		config := testHealthConfig()
+		config.HostDelay = time.Hour
+		checker := NewHealthChecker(newStore(), config, nil)
+		checker.Start()
+		time.Sleep(20 * time.Millisecond)
+		stopped := make(chan struct{})
+		go func() {
+			checker.Stop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(2 * time.Second):
+			t.Fatal("the checker didn't stop")
+		}
+	})
+}
+
+func TestBrokenStatus(t *testing.T) {
+	for statusCode, broken := range map[int]bool{200: false, 204: false, 304: false, 401: false, 403: false,
+		404: true, 410: true, 429: false, 500: true, 503: true} {
+		require.Equal(t, broken, brokenStatus(statusCode), statusCode)
+	}
+}
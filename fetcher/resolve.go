@@ -0,0 +1,107 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+var (
+	ErrRedirectLoop     = errors.New("the destination redirects in a loop")
+	ErrTooManyRedirects = errors.New("the destination redirects too many times")
+)
+
+const resolverUserAgent = "URL_SHORTENER-redirect-resolver/1.0"
+
+// Resolver follows the redirect chain of a destination to its final url
+type Resolver struct {
+	client  *http.Client
+	maxHops int
+}
+
+// NewResolver returns a resolver giving up after maxHops redirects. Like the fetcher it
+// refuses private addresses unless allowPrivateAddresses is set.
+func NewResolver(timeout time.Duration, maxHops int, allowPrivateAddresses bool) *Resolver {
+	client := newHTTPClient(timeout, maxHops, allowPrivateAddresses)
+	// Every hop is checked before it is requested, so the redirects are followed by Resolve
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return &Resolver{client: client, maxHops: maxHops}
+}
+
+// Resolve returns the url the redirect chain starting at rawUrl ends at. Each url of the
+// chain is passed to check before it is requested, an error of check ends the resolution.
+func (r *Resolver) Resolve(ctx context.Context, rawUrl string, check func(string) error) (string, error) {
+	current, err := url.Parse(rawUrl)
+	if err != nil {
+		return "", err
+	}
+	seen := make(map[string]bool)
+	for hops := 0; ; hops++ {
+		if err = check(current.String()); err != nil {
+			return "", err
+		}
+		if seen[current.String()] {
+			return "", ErrRedirectLoop
+		}
+		seen[current.String()] = true
+		location, err := r.next(ctx, current)
+		if err != nil {
+			return "", err
+		}
+		if location == nil {
+			return current.String(), nil
+		}
+		if hops == r.maxHops {
+			return "", ErrTooManyRedirects
+		}
+		current = location
+	}
+}
+
+// next requests the url and returns where it redirects to, nil when it doesn't redirect.
+// Servers not answering HEAD requests are asked with GET.
+func (r *Resolver) next(ctx context.Context, current *url.URL) (*url.URL, error) {
+	res, err := r.request(ctx, http.MethodHead, current)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode == http.StatusMethodNotAllowed || res.StatusCode == http.StatusNotImplemented {
+		if res, err = r.request(ctx, http.MethodGet, current); err != nil {
+			return nil, err
+		}
+	}
+	switch res.StatusCode {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+	default:
+		return nil, nil
+	}
+	location, err := res.Location()
+	if err != nil {
+		return nil, fmt.Errorf("the destination redirects without a valid location")
+	}
+	if location.Scheme != "http" && location.Scheme != "https" {
+		return nil, fmt.Errorf("the destination redirects to an unsupported %s url", location.Scheme)
+	}
+	return location, nil
+}
+
+func (r *Resolver) request(ctx context.Context, method string, current *url.URL) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, current.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", resolverUserAgent)
+	res, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	// Only the status and location are needed
+	_ = res.Body.Close()
+	return res, nil
+}
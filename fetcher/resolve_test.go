@@ -0,0 +1,93 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	mux.HandleFunc("/start", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/middle", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/middle", func(w http.ResponseWriter, r *http.Request) {
+		// Some servers only redirect GET requests
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		http.Redirect(w, r, server.URL+"/final?ref=chain", http.StatusFound)
+	})
+	mux.HandleFunc("/final", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusTemporaryRedirect)
+	})
+	mux.HandleFunc("/hop/", func(w http.ResponseWriter, r *http.Request) {
+		var hop int
+		_, _ = fmt.Sscanf(r.URL.Path, "/hop/%d", &hop)
+		http.Redirect(w, r, fmt.Sprintf("/hop/%d", hop+1), http.StatusFound)
+	})
+	mux.HandleFunc("/ftp", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", "ftp://example.com/file")
+		w.WriteHeader(http.StatusFound)
+	})
+	resolver := NewResolver(2*time.Second, 3, true)
+	allowAll := func(string) error { return nil }
+
+	t.Run("Chain is followed to the end", func(t *testing.T) {
+		var hops []string
+		final, err := resolver.Resolve(context.Background(), server.URL+"/start", func(hop string) error {
+			hops = append(hops, strings.TrimPrefix(hop, server.URL))
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, server.URL+"/final?ref=chain", final)
+		require.Equal(t, []string{"/start", "/middle", "/final?ref=chain"}, hops)
+	})
+
+	t.Run("Destination without redirects is kept", func(t *testing.T) {
+		final, err := resolver.Resolve(context.Background(), server.URL+"/final", allowAll)
+		require.NoError(t, err)
+		require.Equal(t, server.URL+"/final", final)
+	})
+
+	t.Run("Loops and long chains are rejected", func(t *testing.T) {
+		_, err := resolver.Resolve(context.Background(), server.URL+"/loop", allowAll)
+		require.ErrorIs(t, err, ErrRedirectLoop)
+		_, err = resolver.Resolve(context.Background(), server.URL+"/hop/1", allowAll)
+		require.ErrorIs(t, err, ErrTooManyRedirects)
+	})
+
+	t.Run("Check stops the chain before the hop is requested", func(t *testing.T) {
+		stop := errors.New("own short url")
+		_, err := resolver.Resolve(context.Background(), server.URL+"/start", func(hop string) error {
+			if strings.HasSuffix(hop, "/middle") {
+				return stop
+			}
+			return nil
+		})
+		require.ErrorIs(t, err, stop)
+	})
+
+	t.Run("Unsupported locations are errors", func(t *testing.T) {
+		_, err := resolver.Resolve(context.Background(), server.URL+"/ftp", allowAll)
+		require.EqualError(t, err, "the destination redirects to an unsupported ftp url")
+	})
+
+	t.Run("Private addresses are refused", func(t *testing.T) {
+		_, err := NewResolver(2*time.Second, 3, false).Resolve(context.Background(), server.URL+"/start", allowAll)
+		require.ErrorIs(t, err, ErrPrivateAddress)
+	})
+}
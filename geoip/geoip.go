@@ -0,0 +1,85 @@
+package geoip
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Database maps addresses to countries. It is loaded from a CSV file of address
+// ranges with the columns start, end and ISO 3166 country code, the layout of the
+// DB-IP and IP2Location lite country databases. IPv4 and IPv6 ranges may be mixed.
+type Database struct {
+	ranges []addrRange
+}
+
+type addrRange struct {
+	start   netip.Addr
+	end     netip.Addr
+	country string
+}
+
+// Open loads the database from a CSV file
+func Open(path string) (*Database, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Load(file)
+}
+
+// Load reads the CSV ranges from r
+func Load(r io.Reader) (*Database, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+	db := &Database{}
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) < 3 {
+			return nil, fmt.Errorf("line %d: expected start, end and country", line)
+		}
+		start, err := netip.ParseAddr(strings.TrimSpace(record[0]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		end, err := netip.ParseAddr(strings.TrimSpace(record[1]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		start, end = start.Unmap(), end.Unmap()
+		if start.BitLen() != end.BitLen() || end.Less(start) {
+			return nil, fmt.Errorf("line %d: invalid range %s - %s", line, start, end)
+		}
+		db.ranges = append(db.ranges, addrRange{start: start, end: end, country: strings.ToUpper(strings.TrimSpace(record[2]))})
+	}
+	sort.Slice(db.ranges, func(i, j int) bool {
+		return db.ranges[i].start.Less(db.ranges[j].start)
+	})
+	return db, nil
+}
+
+// Country returns the country code of the address, empty if it isn't in any range
+func (d *Database) Country(addr netip.Addr) string {
+	addr = addr.Unmap()
+	// The last range starting at or before the address is the only candidate
+	i := sort.Search(len(d.ranges), func(i int) bool {
+		return addr.Less(d.ranges[i].start)
+	}) - 1
+	if i < 0 || d.ranges[i].start.BitLen() != addr.BitLen() || d.ranges[i].end.Less(addr) {
+		return ""
+	}
+	return d.ranges[i].country
+}
@@ -0,0 +1,50 @@
+package geoip
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testRanges = `"2001:db8::","2001:db8::ffff","DE"
+"1.0.0.0","1.0.0.255","AU"
+"8.8.8.0","8.8.8.255","us"
+`
+
+func TestCountry(t *testing.T) {
+	db, err := Load(strings.NewReader(testRanges))
+	require.NoError(t, err)
+
+	tests := map[string]string{
+		"1.0.0.1":         "AU",
+		"8.8.8.8":         "US",
+		"::ffff:8.8.8.8":  "US",
+		"2001:db8::1":     "DE",
+		"1.0.1.0":         "",
+		"0.0.0.1":         "",
+		"2001:db8::1:0:0": "",
+		"255.255.255.255": "",
+	}
+	for address, country := range tests {
+		require.Equal(t, country, db.Country(netip.MustParseAddr(address)), address)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	t.Run("Invalid range", func(t *testing.T) {
+		_, err := Load(strings.NewReader("8.8.8.255,8.8.8.0,US\n"))
+		require.Error(t, err)
+	})
+
+	t.Run("Load from file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "country.csv")
+		require.NoError(t, os.WriteFile(path, []byte(testRanges), 0o600))
+		db, err := Open(path)
+		require.NoError(t, err)
+		require.Equal(t, "AU", db.Country(netip.MustParseAddr("1.0.0.9")))
+	})
+}
@@ -0,0 +1,216 @@
+// Package grpcserver serves the UrlShortener gRPC service. It runs its url operations on the
+// same service.Shortener as the HTTP controller, so both APIs share their business rules.
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"URL_SHORTENER/api/shortenerpb"
+	"URL_SHORTENER/models"
+	"URL_SHORTENER/service"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+type server struct {
+	shortenerpb.UnimplementedUrlShortenerServer
+	shortener *service.Shortener
+}
+
+// NewServer returns a gRPC server with the UrlShortener, health and reflection services
+func NewServer(shortener *service.Shortener) *grpc.Server {
+	grpcServer := grpc.NewServer()
+	shortenerpb.RegisterUrlShortenerServer(grpcServer, &server{shortener: shortener})
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(shortenerpb.UrlShortener_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	reflection.Register(grpcServer)
+	return grpcServer
+}
+
+func (s *server) CreateShortUrl(ctx context.Context, req *shortenerpb.CreateShortUrlRequest) (*shortenerpb.ShortUrl, error) {
+	params := &service.CreateParams{
+		OriginalUrl:  req.GetOriginalUrl(),
+		Password:     req.GetPassword(),
+		Domain:       req.GetDomain(),
+		MaxClicks:    toIntPointer(req.MaxClicks),
+		ForwardQuery: req.GetForwardQuery(),
+	}
+	if utm := req.GetUtm(); utm != nil {
+		params.Utm = &models.UtmParams{
+			Source:   utm.GetSource(),
+			Medium:   utm.GetMedium(),
+			Campaign: utm.GetCampaign(),
+			Term:     utm.GetTerm(),
+			Content:  utm.GetContent(),
+		}
+	}
+	params.Title = req.Title
+	params.Description = req.Description
+	if len(req.GetTags()) > 0 {
+		tags := req.GetTags()
+		params.Tags = &tags
+	}
+	if req.GetMetadata() != nil {
+		metadata := req.GetMetadata().AsMap()
+		params.Metadata = &metadata
+	}
+	url, err := s.shortener.Create(ctx, req.GetDomain(), params)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return toShortUrl(url)
+}
+
+func (s *server) GetShortUrl(ctx context.Context, req *shortenerpb.GetShortUrlRequest) (*shortenerpb.ShortUrl, error) {
+	url, err := s.shortener.Get(req.GetDomain(), req.GetShortUrl())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return toStoredShortUrl(url)
+}
+
+func (s *server) UpdateShortUrl(ctx context.Context, req *shortenerpb.UpdateShortUrlRequest) (*shortenerpb.ShortUrl, error) {
+	params := &service.UpdateParams{Password: req.Password}
+	params.Title = req.Title
+	params.Description = req.Description
+	if req.GetTags() != nil {
+		tags := req.GetTags().GetValues()
+		params.Tags = &tags
+	}
+	if req.GetMetadata() != nil {
+		metadata := req.GetMetadata().AsMap()
+		params.Metadata = &metadata
+	}
+	url, err := s.shortener.Update(req.GetDomain(), req.GetShortUrl(), req.GetVersion(), params)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return toStoredShortUrl(url)
+}
+
+func (s *server) DeleteShortUrl(ctx context.Context, req *shortenerpb.DeleteShortUrlRequest) (*shortenerpb.DeleteShortUrlResponse, error) {
+	err := s.shortener.Delete(req.GetDomain(), req.GetShortUrl(), req.GetVersion())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &shortenerpb.DeleteShortUrlResponse{}, nil
+}
+
+func (s *server) ListShortUrls(ctx context.Context, req *shortenerpb.ListShortUrlsRequest) (*shortenerpb.ListShortUrlsResponse, error) {
+	urls, err := s.shortener.List(req.GetDomain(), &service.ListParams{
+		Tag:    req.GetTag(),
+		Status: req.GetStatus(),
+		Health: req.GetHealth(),
+		Limit:  int(req.GetLimit()),
+		Offset: int(req.GetOffset()),
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	response := &shortenerpb.ListShortUrlsResponse{ShortUrls: make([]*shortenerpb.ShortUrl, 0, len(urls))}
+	for i := range urls {
+		shortUrl, err := toStoredShortUrl(&urls[i])
+		if err != nil {
+			return nil, err
+		}
+		response.ShortUrls = append(response.ShortUrls, shortUrl)
+	}
+	return response, nil
+}
+
+// toStatusError converts an error of the service to a gRPC status
+func toStatusError(err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, service.ErrInvalid):
+		code = codes.InvalidArgument
+	case errors.Is(err, service.ErrNotFound):
+		code = codes.NotFound
+	case errors.Is(err, service.ErrConflict):
+		code = codes.AlreadyExists
+	case errors.Is(err, service.ErrPreconditionFailed):
+		code = codes.Aborted
+	}
+	return status.Error(code, err.Error())
+}
+
+// toStoredShortUrl converts an existing url to the gRPC message. gRPC can't unlock urls or
+// follow inactive ones, so their destinations are left out like for visitors of the HTTP API.
+func toStoredShortUrl(url *models.Url) (*shortenerpb.ShortUrl, error) {
+	if url.PasswordHash != "" || url.Status != models.StatusActive {
+		url.HideDestinations()
+	}
+	return toShortUrl(url)
+}
+
+// toShortUrl converts a DB url to the gRPC message
+func toShortUrl(url *models.Url) (*shortenerpb.ShortUrl, error) {
+	shortUrl := &shortenerpb.ShortUrl{
+		Domain:            url.Domain,
+		OriginalUrl:       url.OriginalUrl,
+		ShortUrl:          url.ShortUrl,
+		CreatedAt:         url.CreatedAt.Format(time.RFC3339),
+		PasswordProtected: url.PasswordHash != "",
+		MaxClicks:         toInt32Pointer(url.MaxClicks),
+		RemainingClicks:   toInt32Pointer(url.RemainingClicks),
+		Title:             url.Title,
+		Description:       url.Description,
+		Tags:              url.Tags,
+		ClickCount:        int64(url.ClickCount),
+		ForwardQuery:      url.ForwardQuery,
+		Status:            url.Status,
+		StatusReason:      url.StatusReason,
+		Version:           url.Version,
+	}
+	if url.Metadata != nil {
+		metadata, err := structpb.NewStruct(url.Metadata)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "Error converting the metadata of the url.")
+		}
+		shortUrl.Metadata = metadata
+	}
+	if url.Utm != nil {
+		shortUrl.Utm = &shortenerpb.UtmParams{
+			Source:   url.Utm.Source,
+			Medium:   url.Utm.Medium,
+			Campaign: url.Utm.Campaign,
+			Term:     url.Utm.Term,
+			Content:  url.Utm.Content,
+		}
+	}
+	if url.Health != nil {
+		shortUrl.Health = &shortenerpb.LinkHealth{
+			Status:     url.Health.Status,
+			StatusCode: int32(url.Health.StatusCode),
+			LatencyMs:  url.Health.LatencyMs,
+			Error:      url.Health.Error,
+			CheckedAt:  url.Health.CheckedAt,
+		}
+	}
+	return shortUrl, nil
+}
+
+func toIntPointer(value *int32) *int {
+	if value == nil {
+		return nil
+	}
+	converted := int(*value)
+	return &converted
+}
+
+func toInt32Pointer(value *int) *int32 {
+	if value == nil {
+		return nil
+	}
+	converted := int32(*value)
+	return &converted
+}
@@ -0,0 +1,214 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"URL_SHORTENER/api/shortenerpb"
+	"URL_SHORTENER/models"
+	"URL_SHORTENER/service"
+	"URL_SHORTENER/storage"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// newTestClient serves the gRPC server on an in-memory listener backed by an in-memory store,
+// the returned shortener runs on the same store
+func newTestClient(t *testing.T) (*grpc.ClientConn, *service.Shortener) {
+	t.Setenv("DB_PATH", ":memory:")
+	store, err := storage.NewURLStore()
+	require.NoError(t, err)
+	t.Cleanup(store.Close)
+
+	listener := bufconn.Listen(1 << 20)
+	shortener := service.NewShortener(store, nil, nil)
+	grpcServer := NewServer(shortener)
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn, shortener
+}
+
+func requireCode(t *testing.T, err error, code codes.Code) {
+	t.Helper()
+	require.Error(t, err)
+	require.Equal(t, code, status.Code(err), err.Error())
+}
+
+func TestUrlShortener(t *testing.T) {
+	conn, _ := newTestClient(t)
+	client := shortenerpb.NewUrlShortenerClient(conn)
+	ctx := context.Background()
+
+	metadata, err := structpb.NewStruct(map[string]interface{}{"team": "growth"})
+	require.NoError(t, err)
+	maxClicks := int32(10)
+	created, err := client.CreateShortUrl(ctx, &shortenerpb.CreateShortUrlRequest{
+		OriginalUrl: "https://example.com/page",
+		MaxClicks:   &maxClicks,
+		Tags:        []string{"Launch"},
+		Metadata:    metadata,
+		Utm:         &shortenerpb.UtmParams{Source: "newsletter"},
+	})
+	require.NoError(t, err)
+	require.Len(t, created.GetShortUrl(), 8)
+	require.Equal(t, "https://example.com/page", created.GetOriginalUrl())
+	require.Equal(t, int32(10), created.GetRemainingClicks())
+	require.Equal(t, []string{"launch"}, created.GetTags())
+	require.Equal(t, "growth", created.GetMetadata().AsMap()["team"])
+	require.Equal(t, "newsletter", created.GetUtm().GetSource())
+	require.Equal(t, "active", created.GetStatus())
+	require.Equal(t, int64(1), created.GetVersion())
+
+	_, err = client.CreateShortUrl(ctx, &shortenerpb.CreateShortUrlRequest{OriginalUrl: "https://example.com/page"})
+	requireCode(t, err, codes.AlreadyExists)
+	_, err = client.CreateShortUrl(ctx, &shortenerpb.CreateShortUrlRequest{})
+	requireCode(t, err, codes.InvalidArgument)
+	_, err = client.CreateShortUrl(ctx, &shortenerpb.CreateShortUrlRequest{OriginalUrl: "https://example.com/other", Domain: "sho.rt"})
+	requireCode(t, err, codes.InvalidArgument)
+
+	got, err := client.GetShortUrl(ctx, &shortenerpb.GetShortUrlRequest{ShortUrl: created.GetShortUrl()})
+	require.NoError(t, err)
+	require.Equal(t, created.GetOriginalUrl(), got.GetOriginalUrl())
+	// Getting the url doesn't follow it
+	require.Equal(t, int64(0), got.GetClickCount())
+	require.Equal(t, int32(10), got.GetRemainingClicks())
+	_, err = client.GetShortUrl(ctx, &shortenerpb.GetShortUrlRequest{ShortUrl: "missing"})
+	requireCode(t, err, codes.NotFound)
+
+	title := "Launch page"
+	password := "secret"
+	updated, err := client.UpdateShortUrl(ctx, &shortenerpb.UpdateShortUrlRequest{
+		ShortUrl: created.GetShortUrl(),
+		Title:    &title,
+		Password: &password,
+		Tags:     &shortenerpb.Tags{},
+	})
+	require.NoError(t, err)
+	require.Equal(t, created.GetShortUrl(), updated.GetShortUrl())
+	require.Equal(t, title, updated.GetTitle())
+	require.True(t, updated.GetPasswordProtected())
+	require.Empty(t, updated.GetTags())
+	require.Equal(t, "growth", updated.GetMetadata().AsMap()["team"])
+	require.Equal(t, int64(2), updated.GetVersion())
+	// Password protected urls don't reveal their destination
+	require.Empty(t, updated.GetOriginalUrl())
+	got, err = client.GetShortUrl(ctx, &shortenerpb.GetShortUrlRequest{ShortUrl: created.GetShortUrl()})
+	require.NoError(t, err)
+	require.True(t, got.GetPasswordProtected())
+	require.Empty(t, got.GetOriginalUrl())
+	_, err = client.UpdateShortUrl(ctx, &shortenerpb.UpdateShortUrlRequest{ShortUrl: "missing", Title: &title})
+	requireCode(t, err, codes.NotFound)
+	// Updates limited to an older version are refused
+	_, err = client.UpdateShortUrl(ctx, &shortenerpb.UpdateShortUrlRequest{ShortUrl: created.GetShortUrl(), Title: &title, Version: 1})
+	requireCode(t, err, codes.Aborted)
+
+	_, err = client.CreateShortUrl(ctx, &shortenerpb.CreateShortUrlRequest{OriginalUrl: "https://example.com/second", Tags: []string{"docs"}})
+	require.NoError(t, err)
+	list, err := client.ListShortUrls(ctx, &shortenerpb.ListShortUrlsRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.GetShortUrls(), 2)
+	for _, listed := range list.GetShortUrls() {
+		require.Equal(t, listed.GetPasswordProtected(), listed.GetOriginalUrl() == "")
+	}
+	list, err = client.ListShortUrls(ctx, &shortenerpb.ListShortUrlsRequest{Tag: "docs"})
+	require.NoError(t, err)
+	require.Len(t, list.GetShortUrls(), 1)
+	require.Equal(t, "https://example.com/second", list.GetShortUrls()[0].GetOriginalUrl())
+	_, err = client.ListShortUrls(ctx, &shortenerpb.ListShortUrlsRequest{Status: "gone"})
+	requireCode(t, err, codes.InvalidArgument)
+	_, err = client.ListShortUrls(ctx, &shortenerpb.ListShortUrlsRequest{Limit: 1000})
+	requireCode(t, err, codes.InvalidArgument)
+
+	_, err = client.DeleteShortUrl(ctx, &shortenerpb.DeleteShortUrlRequest{ShortUrl: created.GetShortUrl(), Version: 1})
+	requireCode(t, err, codes.Aborted)
+	_, err = client.DeleteShortUrl(ctx, &shortenerpb.DeleteShortUrlRequest{ShortUrl: created.GetShortUrl(), Version: 2})
+	require.NoError(t, err)
+	_, err = client.DeleteShortUrl(ctx, &shortenerpb.DeleteShortUrlRequest{ShortUrl: created.GetShortUrl()})
+	requireCode(t, err, codes.NotFound)
+}
+
+// The proto has no rules, variants or variant_mode and no counterpart of PUT, see shortener.proto
+func TestHttpOnlySettings(t *testing.T) {
+	conn, shortener := newTestClient(t)
+	client := shortenerpb.NewUrlShortenerClient(conn)
+	ctx := context.Background()
+
+	created, err := client.CreateShortUrl(ctx, &shortenerpb.CreateShortUrlRequest{OriginalUrl: "https://example.com/page"})
+	require.NoError(t, err)
+	url, err := shortener.Get("", created.GetShortUrl())
+	require.NoError(t, err)
+	require.Empty(t, url.Rules)
+	require.Empty(t, url.Variants)
+	require.Empty(t, url.VariantMode)
+
+	rules := []models.TargetingRule{{Languages: []string{"de"}, Destination: "https://example.com/de"}}
+	variants := []models.Variant{{Destination: "https://example.com/a", Weight: 1}, {Destination: "https://example.com/b", Weight: 1}}
+	variantMode := models.VariantModeSticky
+	_, err = shortener.Update("", created.GetShortUrl(), 0, &service.UpdateParams{Details: service.Details{
+		Rules:       &rules,
+		Variants:    &variants,
+		VariantMode: &variantMode,
+	}})
+	require.NoError(t, err)
+
+	title := "Launch page"
+	updated, err := client.UpdateShortUrl(ctx, &shortenerpb.UpdateShortUrlRequest{ShortUrl: created.GetShortUrl(), Title: &title})
+	require.NoError(t, err)
+	// Updates keep the short url like PATCH, and the settings gRPC can't set
+	require.Equal(t, created.GetShortUrl(), updated.GetShortUrl())
+	require.Equal(t, title, updated.GetTitle())
+	url, err = shortener.Get("", created.GetShortUrl())
+	require.NoError(t, err)
+	require.Len(t, url.Rules, 1)
+	require.Equal(t, "https://example.com/de", url.Rules[0].Destination)
+	require.Len(t, url.Variants, 2)
+	require.Equal(t, models.VariantModeSticky, url.VariantMode)
+}
+
+func TestToStoredShortUrl(t *testing.T) {
+	for _, urlStatus := range []string{models.StatusActive, models.StatusDisabled, models.StatusBlocked} {
+		url := &models.Url{
+			ShortUrl:    "esd87df7",
+			OriginalUrl: "https://example.com",
+			Status:      urlStatus,
+			Health:      &models.LinkHealth{Status: models.HealthOk},
+		}
+		shortUrl, err := toStoredShortUrl(url)
+		require.NoError(t, err)
+		if urlStatus == models.StatusActive {
+			require.Equal(t, "https://example.com", shortUrl.GetOriginalUrl())
+			require.NotNil(t, shortUrl.GetHealth())
+		} else {
+			require.Empty(t, shortUrl.GetOriginalUrl(), urlStatus)
+			require.Nil(t, shortUrl.GetHealth(), urlStatus)
+		}
+	}
+}
+
+func TestHealth(t *testing.T) {
+	conn, _ := newTestClient(t)
+	client := healthpb.NewHealthClient(conn)
+
+	for _, service := range []string{"", shortenerpb.UrlShortener_ServiceDesc.ServiceName} {
+		res, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		require.NoError(t, err)
+		require.Equal(t, healthpb.HealthCheckResponse_SERVING, res.GetStatus())
+	}
+}
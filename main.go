@@ -3,20 +3,44 @@ package main
 import (
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"URL_SHORTENER/blocklist"
 	"URL_SHORTENER/controller"
+	"URL_SHORTENER/fetcher"
+	"URL_SHORTENER/geoip"
+	"URL_SHORTENER/grpcserver"
 	"URL_SHORTENER/storage"
+	"URL_SHORTENER/webhook"
 
 	"github.com/gorilla/mux"
 	_ "github.com/mattn/go-sqlite3"
 )
 
+const (
+	routePrefix      = "/api/short"
+	adminRoutePrefix = "/api/admin"
+	// blocklistReloadInterval is how often the blocklist files are checked for changes
+	blocklistReloadInterval = 30 * time.Second
+	defaultRescanInterval   = time.Hour
+	// defaultRedirectHops is how many redirects of a new destination are followed
+	defaultRedirectHops   = 5
+	defaultResolveTimeout = 5 * time.Second
+	defaultGrpcPort       = "9090"
+)
+
 func main() {
-	routePrefix := "/api/short"
 	port := ":8080"
 	dbPath := "database.sqlite3"
+	// Maintenance subcommands such as backup run instead of the server
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1:], dbPath, os.Stdout, os.Stderr))
+	}
 	_ = os.Setenv("DB_PATH", dbPath)
 	// Get a new URL store
 	store, err := storage.NewURLStore()
@@ -24,20 +48,262 @@ func main() {
 		log.Fatal(err)
 	}
 	controller.Init(store)
+	// Public host short links are served on, used in QR codes
+	controller.SetPublicBaseUrl(os.Getenv("PUBLIC_BASE_URL"))
+	// Key required by the admin endpoints, they are disabled without it
+	controller.SetAdminApiKey(os.Getenv("ADMIN_API_KEY"))
+	// Header of the HTML pages such as the link preview
+	controller.SetBrandName(os.Getenv("BRAND_NAME"))
+	// Answer of disabled links, 451 or 403 and the page shown to browsers
+	if err = disabledResponseConfig(); err != nil {
+		log.Fatal(err)
+	}
+	// Country database for the targeting rules, a CSV file of address ranges
+	if geoDbPath := os.Getenv("GEOIP_DB_PATH"); geoDbPath != "" {
+		geoDatabase, err := geoip.Open(geoDbPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		controller.SetGeoDatabase(geoDatabase)
+	}
+	// Backups taken through the admin API
+	backupDir, backupKeep, err := backupConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+	controller.SetBackupConfig(backupDir, backupKeep)
+	// How long the responses of create requests sent with an Idempotency-Key are replayed
+	if window, err := idempotencyWindowConfig(); err != nil {
+		log.Fatal(err)
+	} else {
+		controller.SetIdempotencyWindow(window)
+	}
+	// Fetch the title, OpenGraph tags and favicon of new urls in the background
+	pageFetcher := fetcher.NewFetcher(store, fetcher.DefaultConfig())
+	pageFetcher.Start()
+	defer pageFetcher.Stop()
+	controller.SetPageFetcher(pageFetcher)
+	// Follow the redirects of new destinations and store where they end, off unless RESOLVE_REDIRECTS is set
+	if resolve, maxHops, err := redirectResolverConfig(); err != nil {
+		log.Fatal(err)
+	} else if resolve {
+		controller.SetRedirectResolver(fetcher.NewResolver(defaultResolveTimeout, maxHops, false))
+	}
+	// Deliver the link events to the webhooks in the background
+	webhookDispatcher := webhook.NewDispatcher(store, webhook.DefaultConfig())
+	webhookDispatcher.Start()
+	defer webhookDispatcher.Stop()
+	controller.SetWebhookDispatcher(webhookDispatcher)
+	// Check the destinations for broken links in the background, off with HEALTH_CHECK_INTERVAL=0
+	if config, err := healthCheckConfig(); err != nil {
+		log.Fatal(err)
+	} else if config.Interval > 0 {
+		healthChecker := fetcher.NewHealthChecker(store, config, controller.PublishLinkBroken)
+		healthChecker.Start()
+		defer healthChecker.Stop()
+	}
+	// Malware and phishing blocklist screening the destinations, off without list files
+	if config, rescanInterval, err := blocklistConfig(); err != nil {
+		log.Fatal(err)
+	} else if len(config.DomainFiles) > 0 || len(config.HashPrefixFiles) > 0 {
+		urlBlocklist, err := blocklist.Load(config)
+		if err != nil {
+			log.Fatal(err)
+		}
+		controller.SetBlocklist(urlBlocklist)
+		scanner := blocklist.NewScanner(urlBlocklist, store, blocklistReloadInterval, rescanInterval)
+		scanner.Start()
+		defer scanner.Stop()
+	}
 
 	defer store.Close()
-	// Initialise Router
+
+	// Serve the gRPC API on its own port
+	grpcListener, err := net.Listen("tcp", grpcAddress())
+	if err != nil {
+		log.Fatal(err)
+	}
+	grpcServer := grpcserver.NewServer(controller.Shortener())
+	defer grpcServer.Stop()
+	go func() {
+		log.Fatal(grpcServer.Serve(grpcListener))
+	}()
+
+	// Listen and Serve the request
+	log.Fatal(http.ListenAndServe(port, newRouter()))
+}
+
+// grpcAddress returns the address of the gRPC API, on the port from GRPC_PORT or 9090
+func grpcAddress() string {
+	port := os.Getenv("GRPC_PORT")
+	if port == "" {
+		port = defaultGrpcPort
+	}
+	return ":" + port
+}
+
+// disabledResponseConfig reads the status code of disabled links from DISABLED_STATUS_CODE,
+// 451 by default, and the HTML template of their page from DISABLED_PAGE_PATH
+func disabledResponseConfig() error {
+	statusCode := http.StatusUnavailableForLegalReasons
+	if value := os.Getenv("DISABLED_STATUS_CODE"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("DISABLED_STATUS_CODE must be 451 or 403")
+		}
+		statusCode = parsed
+	}
+	return controller.SetDisabledResponse(statusCode, os.Getenv("DISABLED_PAGE_PATH"))
+}
+
+// blocklistConfig reads the comma separated list files from BLOCKLIST_DOMAINS and
+// BLOCKLIST_HASH_PREFIXES, and how often the stored urls are scanned from BLOCKLIST_RESCAN_INTERVAL
+func blocklistConfig() (blocklist.Config, time.Duration, error) {
+	config := blocklist.Config{
+		DomainFiles:     splitList(os.Getenv("BLOCKLIST_DOMAINS")),
+		HashPrefixFiles: splitList(os.Getenv("BLOCKLIST_HASH_PREFIXES")),
+	}
+	rescanInterval := defaultRescanInterval
+	if value := os.Getenv("BLOCKLIST_RESCAN_INTERVAL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			return config, 0, fmt.Errorf("BLOCKLIST_RESCAN_INTERVAL must be a duration like 1h")
+		}
+		rescanInterval = parsed
+	}
+	return config, rescanInterval, nil
+}
+
+// idempotencyWindowConfig reads how long idempotency keys are kept from IDEMPOTENCY_KEY_WINDOW, 24 hours by default
+func idempotencyWindowConfig() (time.Duration, error) {
+	value := os.Getenv("IDEMPOTENCY_KEY_WINDOW")
+	if value == "" {
+		return controller.DefaultIdempotencyWindow, nil
+	}
+	window, err := time.ParseDuration(value)
+	if err != nil || window <= 0 {
+		return 0, fmt.Errorf("IDEMPOTENCY_KEY_WINDOW must be a positive duration like 24h")
+	}
+	return window, nil
+}
+
+// healthCheckConfig reads how long a health check is kept from HEALTH_CHECK_INTERVAL, 0 turning
+// the checks off, how many destinations are checked at once from HEALTH_CHECK_WORKERS, and the
+// least time between two requests to a host from HEALTH_CHECK_HOST_DELAY
+func healthCheckConfig() (fetcher.HealthConfig, error) {
+	config := fetcher.DefaultHealthConfig()
+	if value := os.Getenv("HEALTH_CHECK_INTERVAL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			return config, fmt.Errorf("HEALTH_CHECK_INTERVAL must be a duration like 24h")
+		}
+		config.Interval = parsed
+		if parsed > 0 && parsed < config.ScanInterval {
+			config.ScanInterval = parsed
+		}
+	}
+	if value := os.Getenv("HEALTH_CHECK_WORKERS"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			return config, fmt.Errorf("HEALTH_CHECK_WORKERS must be a positive number")
+		}
+		config.Workers = parsed
+	}
+	if value := os.Getenv("HEALTH_CHECK_HOST_DELAY"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			return config, fmt.Errorf("HEALTH_CHECK_HOST_DELAY must be a duration like 1s")
+		}
+		config.HostDelay = parsed
+	}
+	return config, nil
+}
+
+// redirectResolverConfig reads whether the redirects of new destinations are followed from
+// RESOLVE_REDIRECTS, and how many of them at most from REDIRECT_MAX_HOPS
+func redirectResolverConfig() (bool, int, error) {
+	resolve := false
+	if value := os.Getenv("RESOLVE_REDIRECTS"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return false, 0, fmt.Errorf("RESOLVE_REDIRECTS must be true or false")
+		}
+		resolve = parsed
+	}
+	maxHops := defaultRedirectHops
+	if value := os.Getenv("REDIRECT_MAX_HOPS"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			return false, 0, fmt.Errorf("REDIRECT_MAX_HOPS must be a positive number")
+		}
+		maxHops = parsed
+	}
+	return resolve, maxHops, nil
+}
+
+func splitList(value string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// newRouter registers all the endpoints. Every route has to be described in api/openapi.json.
+func newRouter() *mux.Router {
 	r := mux.NewRouter()
-	// Register all the endpoints
 	// Handler to shorten the URL
 	r.HandleFunc(routePrefix, controller.CreateShortUrl).Methods("POST")
+	// Handlers to preview a shorten url, registered first since the plain short url routes would match them too
+	r.HandleFunc(routePrefix+fmt.Sprintf("/{%s}", controller.PathParamShortUrlId)+controller.PreviewSuffix, controller.PreviewUrl).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/{%s}", controller.PathParamShortUrlId)+controller.PreviewSuffix, controller.PreviewUrl).Methods("GET")
+	// Admin handlers to import and export the shorten urls in bulk, registered before the short url routes
+	r.HandleFunc(routePrefix+"/import", controller.AdminOnly(controller.ImportShortUrls)).Methods("POST")
+	r.HandleFunc(routePrefix+"/export", controller.AdminOnly(controller.ExportShortUrls)).Methods("GET")
+	// Handler to list the shorten urls, optionally filtered by tag
+	r.HandleFunc(routePrefix, controller.ListShortUrls).Methods("GET")
 	// Handler to redirect shorten url to the original url
 	r.HandleFunc(routePrefix+fmt.Sprintf("/{%s}", controller.PathParamShortUrlId), controller.RedirectUrl).Methods("GET")
+	// Handler to unlock a password protected shorten url from the unlock form
+	r.HandleFunc(routePrefix+fmt.Sprintf("/{%s}/unlock", controller.PathParamShortUrlId), controller.UnlockShortUrl).Methods("POST")
+	// Handler to report the abuse of a shorten url, the reports are queued for review
+	r.HandleFunc(routePrefix+fmt.Sprintf("/{%s}/report", controller.PathParamShortUrlId), controller.ReportShortUrl).Methods("POST")
+	// Admin handler to disable, block or reactivate a shorten url
+	r.HandleFunc(routePrefix+fmt.Sprintf("/{%s}/status", controller.PathParamShortUrlId), controller.AdminOnly(controller.SetShortUrlStatus)).Methods("PUT")
+	// Handler to describe a shorten url without following it
+	r.HandleFunc(routePrefix+fmt.Sprintf("/{%s}/info", controller.PathParamShortUrlId), controller.DescribeShortUrl).Methods("GET")
+	// Handler to render a QR code pointing at the shorten url
+	r.HandleFunc(routePrefix+fmt.Sprintf("/{%s}/qr", controller.PathParamShortUrlId), controller.GetQrCode).Methods("GET")
 	// Handler to update shorten url
 	r.HandleFunc(routePrefix+fmt.Sprintf("/{%s}", controller.PathParamShortUrlId), controller.UpdateShortUrl).Methods("PUT")
+	// Handler to change the password and details of a shorten url without regenerating it
+	r.HandleFunc(routePrefix+fmt.Sprintf("/{%s}", controller.PathParamShortUrlId), controller.PatchShortUrl).Methods("PATCH")
 	// Handler to delete shorten url
 	r.HandleFunc(routePrefix+fmt.Sprintf("/{%s}", controller.PathParamShortUrlId), controller.DeleteShortUrl).Methods("DELETE")
-
-	// Listen and Serve the request
-	log.Fatal(http.ListenAndServe(port, r))
+	// Handler to serve the OpenAPI document, registered before the short url route without prefix
+	r.HandleFunc(controller.OpenApiPath, controller.OpenApiSpec).Methods("GET")
+	// Handler to redirect shorten url without the route prefix, for branded short domains
+	r.HandleFunc(fmt.Sprintf("/{%s}", controller.PathParamShortUrlId), controller.RedirectUrl).Methods("GET")
+	// Handler to unlock a password protected shorten url from the unlock form shown on branded short domains
+	r.HandleFunc(fmt.Sprintf("/{%s}/unlock", controller.PathParamShortUrlId), controller.UnlockShortUrl).Methods("POST")
+	// Admin handlers to manage the branded short domains
+	r.HandleFunc(adminRoutePrefix+"/domains", controller.AdminOnly(controller.RegisterDomain)).Methods("POST")
+	r.HandleFunc(adminRoutePrefix+"/domains", controller.AdminOnly(controller.ListDomains)).Methods("GET")
+	r.HandleFunc(adminRoutePrefix+fmt.Sprintf("/domains/{%s}", controller.PathParamDomain), controller.AdminOnly(controller.DeleteDomain)).Methods("DELETE")
+	// Admin handlers to review the abuse reports
+	r.HandleFunc(adminRoutePrefix+"/reports", controller.AdminOnly(controller.ListReports)).Methods("GET")
+	r.HandleFunc(adminRoutePrefix+fmt.Sprintf("/reports/{%s}/review", controller.PathParamReportId), controller.AdminOnly(controller.ReviewReport)).Methods("POST")
+	// Admin handlers to manage the webhooks and inspect their deliveries
+	r.HandleFunc(adminRoutePrefix+"/webhooks", controller.AdminOnly(controller.CreateWebhook)).Methods("POST")
+	r.HandleFunc(adminRoutePrefix+"/webhooks", controller.AdminOnly(controller.ListWebhooks)).Methods("GET")
+	r.HandleFunc(adminRoutePrefix+"/webhooks/dead-letters", controller.AdminOnly(controller.ListDeadLetters)).Methods("GET")
+	r.HandleFunc(adminRoutePrefix+fmt.Sprintf("/webhooks/dead-letters/{%s}/retry", controller.PathParamDeadLetterId), controller.AdminOnly(controller.RetryDeadLetter)).Methods("POST")
+	r.HandleFunc(adminRoutePrefix+fmt.Sprintf("/webhooks/{%s}", controller.PathParamWebhookId), controller.AdminOnly(controller.DeleteWebhook)).Methods("DELETE")
+	r.HandleFunc(adminRoutePrefix+fmt.Sprintf("/webhooks/{%s}/deliveries", controller.PathParamWebhookId), controller.AdminOnly(controller.ListWebhookDeliveries)).Methods("GET")
+	// Admin handlers to back up the database while the server runs
+	r.HandleFunc(adminRoutePrefix+"/backups", controller.AdminOnly(controller.CreateBackup)).Methods("POST")
+	r.HandleFunc(adminRoutePrefix+"/backups", controller.AdminOnly(controller.ListBackups)).Methods("GET")
+	return r
 }
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"URL_SHORTENER/api"
+	"URL_SHORTENER/models"
+	"URL_SHORTENER/storage"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOpenApiCoversRoutes checks that every registered route is described in the OpenAPI document
+func TestOpenApiCoversRoutes(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData(api.Spec)
+	require.NoError(t, err)
+
+	routes := 0
+	err = newRouter().Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		require.NoError(t, err)
+		methods, err := route.GetMethods()
+		require.NoError(t, err)
+		pathItem := doc.Paths.Find(path)
+		require.NotNil(t, pathItem, "%s is missing from the OpenAPI document", path)
+		for _, method := range methods {
+			require.NotNil(t, pathItem.GetOperation(method), "%s %s is missing from the OpenAPI document", method, path)
+			routes++
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, routes, countOperations(doc), "the OpenAPI document describes routes that aren't registered")
+}
+
+func countOperations(doc *openapi3.T) int {
+	operations := 0
+	for _, pathItem := range doc.Paths.Map() {
+		operations += len(pathItem.Operations())
+	}
+	return operations
+}
+
+func TestBackupCommands(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "database.sqlite3")
+	backupDir := filepath.Join(dir, "backups")
+	_ = os.Setenv("DB_PATH", dbPath)
+	store, err := storage.NewURLStore()
+	require.NoError(t, err)
+	require.NoError(t, store.InsertUrl(&models.Url{OriginalUrl: "https://example.com", ShortUrl: "code1", CreatedAt: time.Date(2024, 10, 16, 21, 5, 18, 0, time.UTC)}))
+	store.Close()
+
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, 0, runCommand([]string{"backup", "--dir", backupDir, "--keep", "3"}, dbPath, &stdout, &stderr), stderr.String())
+	require.Contains(t, stdout.String(), "schema version")
+	backups, err := storage.ListBackups(backupDir)
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+
+	restoredPath := filepath.Join(dir, "restored.sqlite3")
+	backupPath := filepath.Join(backupDir, backups[0].Name)
+	require.Equal(t, 0, runCommand([]string{"restore", "--db", restoredPath, backupPath}, dbPath, &stdout, &stderr), stderr.String())
+	_ = os.Setenv("DB_PATH", restoredPath)
+	store, err = storage.NewURLStore()
+	require.NoError(t, err)
+	defer store.Close()
+	require.True(t, store.CheckShortUrlExists("", "code1"))
+
+	require.Equal(t, 2, runCommand([]string{"restore"}, dbPath, &stdout, &stderr))
+	require.Equal(t, 1, runCommand([]string{"restore", filepath.Join(dir, "missing.sqlite3")}, dbPath, &stdout, &stderr))
+	require.Equal(t, 2, runCommand([]string{"vacuum"}, dbPath, &stdout, &stderr))
+}
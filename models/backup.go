@@ -0,0 +1,12 @@
+package models
+
+// Backup is a snapshot of the database kept in the backup directory
+type Backup struct {
+	// Name is the file name of the snapshot, its checksum is kept next to it in Name + ".sha256"
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	Sha256 string `json:"sha256"`
+	// SchemaVersion is the number of migrations applied to the snapshot
+	SchemaVersion int    `json:"schema_version"`
+	CreatedAt     string `json:"created_at"`
+}
@@ -0,0 +1,13 @@
+package models
+
+// Domain is a branded short domain with its own short url namespace and defaults
+type Domain struct {
+	Name string `json:"name"`
+	// CodeLength is the length of short urls generated for the domain
+	CodeLength int `json:"code_length"`
+	// RedirectType is the HTTP status code used to redirect visitors
+	RedirectType int `json:"redirect_type"`
+	// FallbackUrl is where unknown short urls redirect to, empty for a 404
+	FallbackUrl string `json:"fallback_url"`
+	CreatedAt   string `json:"created_at"`
+}
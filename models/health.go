@@ -0,0 +1,17 @@
+package models
+
+// Outcomes of a health check of the destination
+const (
+	HealthOk     = "ok"
+	HealthBroken = "broken"
+)
+
+// LinkHealth is the outcome of the last health check of the original url
+type LinkHealth struct {
+	Status string `json:"status"`
+	// StatusCode is the HTTP status the destination answered, 0 when it wasn't reached
+	StatusCode int    `json:"status_code,omitempty"`
+	LatencyMs  int64  `json:"latency_ms"`
+	Error      string `json:"error,omitempty"`
+	CheckedAt  string `json:"checked_at"`
+}
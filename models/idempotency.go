@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// IdempotencyKey is a key sent with a create request and the response of that request,
+// replayed when the request is retried with the same key
+type IdempotencyKey struct {
+	Key string
+	// RequestHash identifies the request the key was first sent with
+	RequestHash string
+	// Status is the status code of the response, 0 while the request is being handled
+	Status int
+	// Headers are the headers of the response, like Content-Type, ETag and Location
+	Headers   map[string][]string
+	Response  string
+	CreatedAt time.Time
+}
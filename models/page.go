@@ -0,0 +1,14 @@
+package models
+
+// PageMetadata is what was extracted from the destination page of a url
+type PageMetadata struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Image is the OpenGraph image of the page
+	Image      string `json:"image,omitempty"`
+	SiteName   string `json:"site_name,omitempty"`
+	FaviconUrl string `json:"favicon_url,omitempty"`
+	FetchedAt  string `json:"fetched_at,omitempty"`
+	// FetchError is set when the page could not be fetched after all retries
+	FetchError string `json:"fetch_error,omitempty"`
+}
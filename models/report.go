@@ -0,0 +1,16 @@
+package models
+
+// Report is an abuse report of a short url, queued until an admin reviewed it
+type Report struct {
+	Id       int64  `json:"id"`
+	Domain   string `json:"domain"`
+	ShortUrl string `json:"short_url"`
+	// Reason is one of spam, phishing, malware, illegal or other
+	Reason  string `json:"reason"`
+	Details string `json:"details,omitempty"`
+	// Email is where the reporter can be contacted, optional
+	Email     string `json:"email,omitempty"`
+	CreatedAt string `json:"created_at"`
+	// ReviewedAt is empty while the report is waiting for review
+	ReviewedAt string `json:"reviewed_at,omitempty"`
+}
@@ -0,0 +1,16 @@
+package models
+
+// TargetingRule sends the visitors matching all of its conditions to Destination.
+// Empty conditions match every visitor, a list matches if any of its entries does.
+type TargetingRule struct {
+	// Platforms are operating systems detected from the User-Agent: ios, android, windows, macos, linux
+	Platforms []string `json:"platforms,omitempty"`
+	// Languages are matched against the preferred Accept-Language, "en" also matches "en-US"
+	Languages []string `json:"languages,omitempty"`
+	// Countries are ISO 3166 codes looked up from the visitor address in the GeoIP database
+	Countries []string `json:"countries,omitempty"`
+	// StartsAt and EndsAt bound the time window of the rule, either may be empty
+	StartsAt    string `json:"starts_at,omitempty"`
+	EndsAt      string `json:"ends_at,omitempty"`
+	Destination string `json:"destination"`
+}
@@ -0,0 +1,8 @@
+package models
+
+import "time"
+
+// FormatTimestamp formats a time the way the API and the database store timestamps, in UTC as RFC 3339
+func FormatTimestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
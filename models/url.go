@@ -1,7 +1,58 @@
 package models
 
+import "time"
+
+// Statuses of a url, only active urls are followed
+const (
+	StatusActive   = "active"
+	StatusDisabled = "disabled"
+	StatusBlocked  = "blocked"
+)
+
 type Url struct {
+	// Domain is the namespace of the short url, empty for the default one
+	Domain      string `json:"domain"`
 	ShortUrl    string `json:"short_url"`
 	OriginalUrl string `json:"original_url"`
-	CreatedAt   string `json:"created_at"`
+	// CreatedAt is in UTC
+	CreatedAt    time.Time `json:"created_at"`
+	PasswordHash string    `json:"-"`
+	// MaxClicks and RemainingClicks are nil for links without a click limit
+	MaxClicks       *int     `json:"max_clicks,omitempty"`
+	RemainingClicks *int     `json:"remaining_clicks,omitempty"`
+	Title           string   `json:"title,omitempty"`
+	Description     string   `json:"description,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
+	// Metadata holds arbitrary JSON supplied by the client
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+	ClickCount int                    `json:"click_count"`
+	// Page is nil until the destination page has been fetched
+	Page *PageMetadata `json:"page,omitempty"`
+	// Utm is nil for urls without campaign tags
+	Utm *UtmParams `json:"utm,omitempty"`
+	// ForwardQuery appends the query string of the short url request to the destination
+	ForwardQuery bool `json:"forward_query"`
+	// Rules are evaluated in order at redirect time, OriginalUrl is used when none matches
+	Rules []TargetingRule `json:"rules,omitempty"`
+	// Variants replace OriginalUrl as destination when no rule matches
+	Variants []Variant `json:"variants,omitempty"`
+	// VariantMode is "sticky" to keep serving a visitor the same variant, random otherwise
+	VariantMode string `json:"variant_mode,omitempty"`
+	// Status is disabled by an admin or blocked once a destination was found on the blocklist
+	Status       string `json:"status"`
+	StatusReason string `json:"status_reason,omitempty"`
+	// Health is nil until the destination has been checked
+	Health *LinkHealth `json:"health,omitempty"`
+	// Version counts the changes of the settings of the url, clicks and checks don't change it
+	Version int64 `json:"-"`
+}
+
+// HideDestinations clears everything that reveals where the url leads, for
+// listing protected urls to callers that can't unlock them
+func (u *Url) HideDestinations() {
+	u.OriginalUrl = ""
+	u.Page = nil
+	u.Rules = nil
+	u.Variants = nil
+	u.Health = nil
 }
@@ -0,0 +1,31 @@
+package models
+
+// UtmParams are the campaign tags added to the destination of a url
+type UtmParams struct {
+	Source   string `json:"source,omitempty"`
+	Medium   string `json:"medium,omitempty"`
+	Campaign string `json:"campaign,omitempty"`
+	Term     string `json:"term,omitempty"`
+	Content  string `json:"content,omitempty"`
+}
+
+// QueryParams maps the tags that are set to their utm_ query parameter names
+func (p *UtmParams) QueryParams() map[string]string {
+	params := make(map[string]string)
+	for name, value := range map[string]string{
+		"utm_source":   p.Source,
+		"utm_medium":   p.Medium,
+		"utm_campaign": p.Campaign,
+		"utm_term":     p.Term,
+		"utm_content":  p.Content,
+	} {
+		if value != "" {
+			params[name] = value
+		}
+	}
+	return params
+}
+
+func (p *UtmParams) IsEmpty() bool {
+	return len(p.QueryParams()) == 0
+}
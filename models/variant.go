@@ -0,0 +1,17 @@
+package models
+
+// Variant modes, sticky variants serve a returning visitor the variant they saw before
+const (
+	VariantModeRandom = "random"
+	VariantModeSticky = "sticky"
+)
+
+// Variant is one of the weighted destinations a url splits its visitors between
+type Variant struct {
+	// Name identifies the variant in the click stats, it is kept when the weights change
+	Name        string `json:"name"`
+	Destination string `json:"destination"`
+	Weight      int    `json:"weight"`
+	// ClickCount is how often the variant was served, it is ignored on input
+	ClickCount int `json:"click_count"`
+}
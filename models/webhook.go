@@ -0,0 +1,57 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Statuses of a webhook delivery
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+// Webhook is a subscription to link lifecycle events
+type Webhook struct {
+	Id     int64    `json:"id"`
+	Url    string   `json:"url"`
+	Events []string `json:"events"`
+	// Secret signs the deliveries, it is only shown when the webhook is created
+	Secret string `json:"-"`
+	// ClickThresholds are the click counts link.click_threshold events are sent at
+	ClickThresholds []int  `json:"click_thresholds,omitempty"`
+	CreatedAt       string `json:"created_at"`
+}
+
+// WebhookDelivery is one event sent to one webhook, kept as the delivery log
+type WebhookDelivery struct {
+	Id        int64           `json:"id"`
+	WebhookId int64           `json:"webhook_id"`
+	Event     string          `json:"event"`
+	Payload   json.RawMessage `json:"payload"`
+	Status    string          `json:"status"`
+	Attempts  int             `json:"attempts"`
+	// ResponseStatus is the HTTP status of the last attempt, 0 when the receiver wasn't reached
+	ResponseStatus int    `json:"response_status,omitempty"`
+	LastError      string `json:"last_error,omitempty"`
+	CreatedAt      string `json:"created_at"`
+	DeliveredAt    string `json:"delivered_at,omitempty"`
+	// NextAttemptAt is when a pending delivery is sent next
+	NextAttemptAt time.Time `json:"-"`
+	// Url and Secret of the webhook, filled in for sending
+	Url    string `json:"-"`
+	Secret string `json:"-"`
+}
+
+// WebhookDeadLetter is a delivery that failed every attempt, kept until it is retried
+type WebhookDeadLetter struct {
+	Id         int64           `json:"id"`
+	DeliveryId int64           `json:"delivery_id"`
+	WebhookId  int64           `json:"webhook_id"`
+	Event      string          `json:"event"`
+	Payload    json.RawMessage `json:"payload"`
+	Attempts   int             `json:"attempts"`
+	LastError  string          `json:"last_error"`
+	FailedAt   string          `json:"failed_at"`
+}
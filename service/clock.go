@@ -0,0 +1,15 @@
+package service
+
+import "time"
+
+// Clock tells the time urls are created at. Tests set a fixed clock to get deterministic timestamps.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the clock of the system
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
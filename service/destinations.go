@@ -0,0 +1,142 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"URL_SHORTENER/models"
+)
+
+const (
+	maxTargetingRules = 20
+	maxVariants       = 10
+	maxVariantWeight  = 1000
+)
+
+var allowedPlatforms = map[string]bool{"ios": true, "android": true, "windows": true, "macos": true, "linux": true}
+
+// normalizeRules validates the rules and normalizes their conditions in place
+func normalizeRules(rules []models.TargetingRule) error {
+	if len(rules) > maxTargetingRules {
+		return fmt.Errorf("A url can have at most %d rules", maxTargetingRules)
+	}
+	for i := range rules {
+		rule := &rules[i]
+		if rule.Destination == "" {
+			return fmt.Errorf("Rule %d: destination can not be empty", i+1)
+		}
+		if err := validateDestination(rule.Destination); err != nil {
+			return fmt.Errorf("Rule %d: %w", i+1, err)
+		}
+		for j, platform := range rule.Platforms {
+			rule.Platforms[j] = strings.ToLower(platform)
+			if !allowedPlatforms[rule.Platforms[j]] {
+				return fmt.Errorf("Rule %d: platform must be one of ios, android, windows, macos, linux", i+1)
+			}
+		}
+		for j, language := range rule.Languages {
+			rule.Languages[j] = strings.ToLower(strings.TrimSpace(language))
+			if rule.Languages[j] == "" {
+				return fmt.Errorf("Rule %d: languages can not be empty", i+1)
+			}
+		}
+		for j, country := range rule.Countries {
+			rule.Countries[j] = strings.ToUpper(strings.TrimSpace(country))
+			if len(rule.Countries[j]) != 2 {
+				return fmt.Errorf("Rule %d: countries must be two letter codes", i+1)
+			}
+		}
+		startsAt, endsAt, err := RuleWindow(rule)
+		if err != nil {
+			return fmt.Errorf("Rule %d: times must be formatted as RFC 3339, like 2024-10-16T21:05:18Z", i+1)
+		}
+		if !startsAt.IsZero() && !endsAt.IsZero() && !startsAt.Before(endsAt) {
+			return fmt.Errorf("Rule %d: starts_at must be before ends_at", i+1)
+		}
+		// Times are stored in UTC whatever offset they were given with
+		if !startsAt.IsZero() {
+			rule.StartsAt = models.FormatTimestamp(startsAt)
+		}
+		if !endsAt.IsZero() {
+			rule.EndsAt = models.FormatTimestamp(endsAt)
+		}
+	}
+	return nil
+}
+
+// validateDestination checks that visitors can be sent to the destination, an absolute http or https url
+func validateDestination(destination string) error {
+	destinationUrl, err := url.Parse(destination)
+	if err != nil || (destinationUrl.Scheme != "http" && destinationUrl.Scheme != "https") || destinationUrl.Host == "" {
+		return errors.New("destination must be an absolute http or https url")
+	}
+	return nil
+}
+
+// RuleWindow parses the RFC 3339 time window of the rule, unset bounds are zero
+func RuleWindow(rule *models.TargetingRule) (startsAt time.Time, endsAt time.Time, err error) {
+	if rule.StartsAt != "" {
+		startsAt, err = time.Parse(time.RFC3339, rule.StartsAt)
+		if err != nil {
+			return
+		}
+	}
+	if rule.EndsAt != "" {
+		endsAt, err = time.Parse(time.RFC3339, rule.EndsAt)
+	}
+	return
+}
+
+// normalizeVariants validates the variants and names the unnamed ones A, B, C... by position
+func normalizeVariants(variants []models.Variant) error {
+	if len(variants) > maxVariants {
+		return fmt.Errorf("A url can have at most %d variants", maxVariants)
+	}
+	seen := make(map[string]bool)
+	totalWeight := 0
+	for i := range variants {
+		variant := &variants[i]
+		variant.ClickCount = 0
+		variant.Name = strings.TrimSpace(variant.Name)
+		if variant.Name == "" {
+			variant.Name = string(rune('A' + i))
+		}
+		if seen[variant.Name] {
+			return fmt.Errorf("Variant names must be unique, %s is used twice", variant.Name)
+		}
+		seen[variant.Name] = true
+		if variant.Destination == "" {
+			return fmt.Errorf("Variant %s: destination can not be empty", variant.Name)
+		}
+		if err := validateDestination(variant.Destination); err != nil {
+			return fmt.Errorf("Variant %s: %w", variant.Name, err)
+		}
+		if variant.Weight < 0 || variant.Weight > maxVariantWeight {
+			return fmt.Errorf("Variant %s: weight must be between 0 and %d", variant.Name, maxVariantWeight)
+		}
+		totalWeight += variant.Weight
+	}
+	if len(variants) > 0 && totalWeight == 0 {
+		return fmt.Errorf("At least one variant needs a weight above zero")
+	}
+	return nil
+}
+
+func validateVariantMode(mode string) error {
+	if mode != "" && mode != models.VariantModeRandom && mode != models.VariantModeSticky {
+		return fmt.Errorf("Variant mode must be one of %s, %s", models.VariantModeRandom, models.VariantModeSticky)
+	}
+	return nil
+}
+
+// ValidateStatus checks that the status is one a url can have
+func ValidateStatus(status string) error {
+	switch status {
+	case models.StatusActive, models.StatusDisabled, models.StatusBlocked:
+		return nil
+	}
+	return errors.New("Status must be one of active, disabled, blocked")
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"URL_SHORTENER/models"
+	"URL_SHORTENER/storage"
+)
+
+const (
+	minCodeLength = 4
+	maxCodeLength = 32
+)
+
+var allowedRedirectTypes = map[int]bool{
+	http.StatusMovedPermanently:  true,
+	http.StatusFound:             true,
+	http.StatusTemporaryRedirect: true,
+	http.StatusPermanentRedirect: true,
+}
+
+type DomainParams struct {
+	Name         string `json:"name"`
+	CodeLength   int    `json:"code_length,omitempty"`
+	RedirectType int    `json:"redirect_type,omitempty"`
+	FallbackUrl  string `json:"fallback_url,omitempty"`
+}
+
+// Validate checks the params of a new domain
+func (p *DomainParams) Validate() error {
+	if p.Name == "" {
+		return errors.New("Domain name can not be empty")
+	}
+	if strings.ContainsAny(p.Name, "/:?#@ ") {
+		return errors.New("Domain name must be a bare host name like sho.rt")
+	}
+	if p.CodeLength != 0 && (p.CodeLength < minCodeLength || p.CodeLength > maxCodeLength) {
+		return errors.New("Code length must be between 4 and 32")
+	}
+	if p.RedirectType != 0 && !allowedRedirectTypes[p.RedirectType] {
+		return errors.New("Redirect type must be one of 301, 302, 307, 308")
+	}
+	if p.FallbackUrl != "" {
+		fallbackUrl, err := url.Parse(p.FallbackUrl)
+		if err != nil || fallbackUrl.Scheme == "" || fallbackUrl.Host == "" {
+			return errors.New("Fallback url must be an absolute url")
+		}
+	}
+	return nil
+}
+
+// RegisterDomain validates the params and registers a branded domain, filling in the defaults
+func (s *Shortener) RegisterDomain(params *DomainParams) (*models.Domain, error) {
+	if err := params.Validate(); err != nil {
+		return nil, invalid(err.Error())
+	}
+	domain := &models.Domain{
+		Name:         NormalizeHost(params.Name),
+		CodeLength:   params.CodeLength,
+		RedirectType: params.RedirectType,
+		FallbackUrl:  params.FallbackUrl,
+		CreatedAt:    models.FormatTimestamp(s.Now()),
+	}
+	if domain.CodeLength == 0 {
+		domain.CodeLength = DefaultCodeLength
+	}
+	if domain.RedirectType == 0 {
+		domain.RedirectType = http.StatusFound
+	}
+	err := s.store.InsertDomain(domain)
+	if err != nil {
+		if err.Error() == storage.ErrDomainAlreadyExists {
+			return nil, conflict(err.Error())
+		}
+		return nil, internal("Error registering domain.")
+	}
+	return domain, nil
+}
+
+// ListDomains lists the registered domains
+func (s *Shortener) ListDomains() ([]models.Domain, error) {
+	domains, err := s.store.ListDomains()
+	if err != nil {
+		return nil, internal("Error listing domains.")
+	}
+	return domains, nil
+}
+
+// DeleteDomain unregisters a domain, its urls are kept
+func (s *Shortener) DeleteDomain(name string) error {
+	err := s.store.DeleteDomain(NormalizeHost(name))
+	if err != nil {
+		if err.Error() == storage.ErrDomainDoesNotExist {
+			return notFound(err.Error())
+		}
+		return internal("Error deleting domain.")
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package service
+
+import "errors"
+
+// Kinds of the errors returned by the service, match them with errors.Is
+var (
+	ErrInvalid  = errors.New("invalid request")
+	ErrNotFound = errors.New("not found")
+	ErrConflict = errors.New("conflict")
+	// ErrPreconditionFailed is returned when the url no longer has the version the caller expects
+	ErrPreconditionFailed = errors.New("precondition failed")
+	ErrInternal           = errors.New("internal error")
+)
+
+// Error is a failed operation. The message can be shown to clients, the kind tells them
+// apart: errors.Is(err, ErrNotFound) holds for the urls that don't exist.
+type Error struct {
+	Kind    error
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+func invalid(message string) error {
+	return &Error{Kind: ErrInvalid, Message: message}
+}
+
+func notFound(message string) error {
+	return &Error{Kind: ErrNotFound, Message: message}
+}
+
+func conflict(message string) error {
+	return &Error{Kind: ErrConflict, Message: message}
+}
+
+func preconditionFailed(message string) error {
+	return &Error{Kind: ErrPreconditionFailed, Message: message}
+}
+
+func internal(message string) error {
+	return &Error{Kind: ErrInternal, Message: message}
+}
@@ -0,0 +1,331 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"URL_SHORTENER/models"
+	"URL_SHORTENER/storage"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	ConflictError     = "error"
+	ConflictSkip      = "skip"
+	ConflictOverwrite = "overwrite"
+	ConflictRename    = "rename"
+
+	ImportStatusImported = "imported"
+	ImportStatusRenamed  = "renamed"
+	ImportStatusSkipped  = "skipped"
+	ImportStatusFailed   = "failed"
+
+	// importedCharSet are the characters of imported short urls, other shorteners use - and _ too
+	importedCharSet  = CharSet + "-_"
+	errShortUrlInUse = "The Short URL is already in use by another url."
+	// legacyTimeLayout is the local time without a zone that older exports have
+	legacyTimeLayout = "2006-01-02 15:04:05"
+)
+
+var allowedConflictModes = map[string]bool{
+	ConflictError:     true,
+	ConflictSkip:      true,
+	ConflictOverwrite: true,
+	ConflictRename:    true,
+}
+
+// reservedShortUrls would be shadowed by the routes next to the short urls
+var reservedShortUrls = map[string]bool{
+	"export": true,
+	"import": true,
+}
+
+// ImportRecord is one url of an import. Exported urls can be imported as they are,
+// the fields only found in responses, like page, are ignored.
+type ImportRecord struct {
+	// ShortUrl is kept when given and generated otherwise
+	ShortUrl string `json:"short_url,omitempty"`
+	// CreatedAt is RFC 3339 or the local time of older exports, the import time is used when empty
+	CreatedAt string `json:"created_at,omitempty"`
+	// PasswordHash is a bcrypt hash as found in exports, it can not be combined with Password
+	PasswordHash    string `json:"password_hash,omitempty"`
+	RemainingClicks *int   `json:"remaining_clicks,omitempty"`
+	ClickCount      int    `json:"click_count,omitempty"`
+	// Status is active when empty, disabled and blocked urls stay that way
+	Status       string `json:"status,omitempty"`
+	StatusReason string `json:"status_reason,omitempty"`
+	CreateParams
+}
+
+// ImportResult tells what became of a record, Error explains the records that weren't imported as given
+type ImportResult struct {
+	ShortUrl string `json:"short_url,omitempty"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Importer stores the records of one import. on_conflict decides what happens to records whose
+// short url or original url is in use: error fails them, skip keeps the existing url, overwrite
+// replaces it and rename gives the record a new short url. A dry run only checks the records.
+type Importer struct {
+	shortener *Shortener
+	// domain is the namespace of the host, used for records without a domain
+	domain *models.Domain
+	// host the import was sent to, destinations on it are checked for short urls of this service
+	host       string
+	onConflict string
+	dryRun     bool
+	// domains caches the registered domains named by the records
+	domains map[string]*models.Domain
+	// seen holds the short urls and original urls checked in a dry run, as they aren't inserted
+	seen map[string]bool
+}
+
+// NewImporter returns an importer storing the records on the namespace of the host, an empty
+// onConflict fails the records in conflict
+func (s *Shortener) NewImporter(host string, onConflict string, dryRun bool) (*Importer, error) {
+	if onConflict == "" {
+		onConflict = ConflictError
+	}
+	if !allowedConflictModes[onConflict] {
+		return nil, invalid(fmt.Sprintf("on_conflict must be one of %s, %s, %s, %s",
+			ConflictError, ConflictSkip, ConflictOverwrite, ConflictRename))
+	}
+	domain, err := s.Domain(host)
+	if err != nil {
+		return nil, err
+	}
+	return &Importer{
+		shortener:  s,
+		domain:     domain,
+		host:       host,
+		onConflict: onConflict,
+		dryRun:     dryRun,
+		domains:    make(map[string]*models.Domain),
+		seen:       make(map[string]bool),
+	}, nil
+}
+
+// Import stores the record, or only checks it in a dry run. A record that fails is reported in
+// the result, it doesn't stop the import.
+func (i *Importer) Import(record *ImportRecord) ImportResult {
+	url, codeLength, err := i.toUrl(record)
+	if err != nil {
+		return ImportResult{ShortUrl: record.ShortUrl, Status: ImportStatusFailed, Error: err.Error()}
+	}
+	generated := record.ShortUrl == ""
+	if i.dryRun {
+		return i.check(url, generated)
+	}
+
+	shortUrl := url.ShortUrl
+	renamed, err := i.insert(url, codeLength, generated)
+	if err == nil {
+		if i.shortener.observer != nil {
+			i.shortener.observer.UrlCreated(url)
+		}
+		if renamed {
+			return ImportResult{ShortUrl: url.ShortUrl, Status: ImportStatusRenamed,
+				Error: fmt.Sprintf("%s is in use, imported as %s", shortUrl, url.ShortUrl)}
+		}
+		return ImportResult{ShortUrl: url.ShortUrl, Status: ImportStatusImported}
+	}
+	switch err.Error() {
+	case storage.ErrShortURLAlreadyExists:
+		return i.conflict(url.ShortUrl, errShortUrlInUse)
+	case storage.ErrURLAlreadyShortened:
+		return i.conflict(url.ShortUrl, err.Error())
+	}
+	log.Printf("importing %s failed: %v", url.OriginalUrl, err)
+	return ImportResult{ShortUrl: url.ShortUrl, Status: ImportStatusFailed, Error: "Error importing url"}
+}
+
+// conflict reports a record whose short url or original url is in use
+func (i *Importer) conflict(shortUrl string, message string) ImportResult {
+	if i.onConflict == ConflictSkip {
+		return ImportResult{ShortUrl: shortUrl, Status: ImportStatusSkipped, Error: message}
+	}
+	return ImportResult{ShortUrl: shortUrl, Status: ImportStatusFailed, Error: message}
+}
+
+// toUrl validates the record and converts it to a DB url, along with the code length of its domain
+func (i *Importer) toUrl(record *ImportRecord) (*models.Url, int, error) {
+	// Validation resets the click counts of the variants, which an import keeps
+	variantClicks := make([]int, 0)
+	if record.Variants != nil {
+		for _, variant := range *record.Variants {
+			variantClicks = append(variantClicks, variant.ClickCount)
+		}
+	}
+	if err := record.CreateParams.Validate(); err != nil {
+		return nil, 0, invalid(err.Error())
+	}
+	for index, clicks := range variantClicks {
+		(*record.Variants)[index].ClickCount = clicks
+	}
+	domain, err := i.recordDomain(record.Domain)
+	if err != nil {
+		return nil, 0, err
+	}
+	if record.ShortUrl != "" {
+		if err = validateImportedShortUrl(record.ShortUrl); err != nil {
+			return nil, 0, invalid(err.Error())
+		}
+	}
+	createdAt, err := parseImportedTime(record.CreatedAt, i.shortener.Now())
+	if err != nil {
+		return nil, 0, invalid(err.Error())
+	}
+	if record.ClickCount < 0 {
+		return nil, 0, invalid("Click count can not be negative")
+	}
+	if record.RemainingClicks != nil &&
+		(record.MaxClicks == nil || *record.RemainingClicks < 0 || *record.RemainingClicks > *record.MaxClicks) {
+		return nil, 0, invalid("Remaining clicks must be between 0 and max clicks")
+	}
+	status := record.Status
+	if status == "" {
+		status = models.StatusActive
+	}
+	if err = ValidateStatus(status); err != nil {
+		return nil, 0, invalid(err.Error())
+	}
+	passwordHash := record.PasswordHash
+	if passwordHash != "" {
+		if record.Password != "" {
+			return nil, 0, invalid("Only one of password and password_hash can be given")
+		}
+		if _, err = bcrypt.Cost([]byte(passwordHash)); err != nil {
+			return nil, 0, invalid("Password hash must be a bcrypt hash")
+		}
+	} else if passwordHash, err = HashPassword(record.Password); err != nil {
+		return nil, 0, internal("Error importing url")
+	}
+
+	url := &models.Url{
+		Domain:          domain.Name,
+		ShortUrl:        record.ShortUrl,
+		OriginalUrl:     record.OriginalUrl,
+		CreatedAt:       createdAt,
+		PasswordHash:    passwordHash,
+		MaxClicks:       record.MaxClicks,
+		RemainingClicks: record.RemainingClicks,
+		ClickCount:      record.ClickCount,
+		ForwardQuery:    record.ForwardQuery,
+		Status:          status,
+		StatusReason:    record.StatusReason,
+	}
+	if url.RemainingClicks == nil {
+		url.RemainingClicks = url.MaxClicks
+	}
+	if record.Utm != nil && !record.Utm.IsEmpty() {
+		url.Utm = record.Utm
+	}
+	record.Details.Apply(url)
+	// Imports don't resolve the redirects of every destination, that would take a request per row
+	if i.shortener.screener != nil {
+		if err = i.shortener.screener.ScreenDestinations(i.host, url); err != nil {
+			return nil, 0, invalid(err.Error())
+		}
+	}
+	if url.ShortUrl == "" {
+		url.ShortUrl = GenerateShortUrl(domain.CodeLength)
+	}
+	return url, domain.CodeLength, nil
+}
+
+// recordDomain returns the registered domain named by a record, or the domain of the host
+func (i *Importer) recordDomain(name string) (*models.Domain, error) {
+	if name == "" {
+		return i.domain, nil
+	}
+	name = NormalizeHost(name)
+	if domain, ok := i.domains[name]; ok {
+		return domain, nil
+	}
+	domain, err := i.shortener.store.GetDomain(name)
+	if err != nil {
+		if err.Error() == storage.ErrDomainDoesNotExist {
+			return nil, invalid(err.Error())
+		}
+		return nil, internal("Error resolving domain.")
+	}
+	i.domains[name] = domain
+	return domain, nil
+}
+
+// insert stores the url. It retries with a new short url when a generated one collided,
+// or when the given one is in use and the conflicts are renamed.
+func (i *Importer) insert(url *models.Url, codeLength int, generated bool) (renamed bool, err error) {
+	store := i.shortener.store
+	overwrite := i.onConflict == ConflictOverwrite
+	for attempt := 0; attempt < MaxShortUrlAttempts; attempt++ {
+		switch {
+		case overwrite && generated && store.CheckShortUrlExists(url.Domain, url.ShortUrl):
+			// A generated short url must never replace the url it collides with
+			err = errors.New(storage.ErrShortURLAlreadyExists)
+		case overwrite:
+			err = store.ReplaceUrl(url)
+		default:
+			err = store.InsertUrl(url)
+		}
+		if err == nil || err.Error() != storage.ErrShortURLAlreadyExists || !(generated || i.onConflict == ConflictRename) {
+			return renamed, err
+		}
+		url.ShortUrl = GenerateShortUrl(codeLength)
+		renamed = !generated
+	}
+	return renamed, err
+}
+
+// check predicts the result of importing the url, records earlier in the import count as stored
+func (i *Importer) check(url *models.Url, generated bool) ImportResult {
+	store := i.shortener.store
+	shortUrlKey := "short_url\x00" + url.Domain + "\x00" + url.ShortUrl
+	originalUrlKey := "original_url\x00" + url.Domain + "\x00" + url.OriginalUrl
+	shortUrlInUse := !generated && (i.seen[shortUrlKey] || store.CheckShortUrlExists(url.Domain, url.ShortUrl))
+	originalUrlInUse := i.seen[originalUrlKey] || store.CheckOriginalUrlExists(url.Domain, url.OriginalUrl)
+	i.seen[shortUrlKey] = true
+	i.seen[originalUrlKey] = true
+
+	switch {
+	case (!shortUrlInUse && !originalUrlInUse) || i.onConflict == ConflictOverwrite:
+		return ImportResult{ShortUrl: url.ShortUrl, Status: ImportStatusImported}
+	case originalUrlInUse:
+		return i.conflict(url.ShortUrl, storage.ErrURLAlreadyShortened)
+	case i.onConflict == ConflictRename:
+		return ImportResult{ShortUrl: url.ShortUrl, Status: ImportStatusRenamed,
+			Error: fmt.Sprintf("%s is in use, it would get a new short url", url.ShortUrl)}
+	}
+	return i.conflict(url.ShortUrl, errShortUrlInUse)
+}
+
+func validateImportedShortUrl(shortUrl string) error {
+	if len(shortUrl) > maxCodeLength || strings.Trim(shortUrl, importedCharSet) != "" {
+		return fmt.Errorf("Short url must be at most %d letters, digits, - or _", maxCodeLength)
+	}
+	if reservedShortUrls[shortUrl] {
+		return fmt.Errorf("Short url %s is reserved", shortUrl)
+	}
+	return nil
+}
+
+// parseImportedTime parses created_at as RFC 3339 or, as in older exports, as local time without a zone.
+// The time is returned in UTC, now is used when the value is empty.
+func parseImportedTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return now, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		parsed, err = time.ParseInLocation(legacyTimeLayout, value, time.Local)
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Created at must be formatted as RFC 3339 or like %s", legacyTimeLayout)
+	}
+	return parsed.UTC().Truncate(time.Second), nil
+}
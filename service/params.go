@@ -0,0 +1,198 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"URL_SHORTENER/models"
+)
+
+const (
+	maxTitleLength       = 200
+	maxDescriptionLength = 2000
+	maxTags              = 20
+	maxTagLength         = 64
+	maxUtmValueLength    = 200
+	// maxPasswordLength is the most bytes bcrypt hashes
+	maxPasswordLength = 72
+)
+
+type CreateParams struct {
+	OriginalUrl string `json:"original_url"`
+	Password    string `json:"password,omitempty"`
+	// Domain creates the url on a registered domain instead of the request host
+	Domain string `json:"domain,omitempty"`
+	// MaxClicks limits how often the link can be followed, unlimited when omitted
+	MaxClicks *int `json:"max_clicks,omitempty"`
+	// Utm tags are added to the original url when the link is followed
+	Utm *models.UtmParams `json:"utm,omitempty"`
+	// ForwardQuery passes the query string of the short url on to the original url
+	ForwardQuery bool `json:"forward_query,omitempty"`
+	Details
+}
+
+type UpdateParams struct {
+	// Password replaces the link password when set, an empty string removes it
+	Password *string `json:"password,omitempty"`
+	Details
+}
+
+// Details describes what a url is for and where it leads visitors.
+// Nil fields are left unchanged on update.
+type Details struct {
+	Title       *string                 `json:"title,omitempty"`
+	Description *string                 `json:"description,omitempty"`
+	Tags        *[]string               `json:"tags,omitempty"`
+	Metadata    *map[string]interface{} `json:"metadata,omitempty"`
+	// Rules replace the targeting rules of the url, an empty list removes them
+	Rules *[]models.TargetingRule `json:"rules,omitempty"`
+	// Variants replace the weighted destinations of the url, an empty list removes them
+	Variants    *[]models.Variant `json:"variants,omitempty"`
+	VariantMode *string           `json:"variant_mode,omitempty"`
+}
+
+// ListParams filters and pages the urls of a namespace, a zero Limit lists the default page size
+type ListParams struct {
+	Tag    string
+	Status string
+	Health string
+	Limit  int
+	Offset int
+}
+
+// Validate checks the params of a new url and normalizes their details in place
+func (p *CreateParams) Validate() error {
+	if p.OriginalUrl == "" {
+		return errors.New("Original Url can not be empty")
+	}
+	if err := validatePassword(p.Password); err != nil {
+		return err
+	}
+	if p.MaxClicks != nil && *p.MaxClicks <= 0 {
+		return errors.New("Max clicks must be greater than zero")
+	}
+	if p.Utm != nil {
+		if err := validateUtmParams(p.Utm); err != nil {
+			return err
+		}
+	}
+	if err := p.Details.Validate(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Validate checks the params of a url update and normalizes their details in place
+func (p *UpdateParams) Validate() error {
+	if p.Password != nil {
+		if err := validatePassword(*p.Password); err != nil {
+			return err
+		}
+	}
+	return p.Details.Validate()
+}
+
+func (p *Details) isEmpty() bool {
+	return p.Title == nil && p.Description == nil && p.Tags == nil && p.Metadata == nil && p.Rules == nil &&
+		p.Variants == nil && p.VariantMode == nil
+}
+
+// Validate checks the details and normalizes the tags in place
+func (p *Details) Validate() error {
+	if p.Title != nil && len(*p.Title) > maxTitleLength {
+		return fmt.Errorf("Title can not be longer than %d characters", maxTitleLength)
+	}
+	if p.Description != nil && len(*p.Description) > maxDescriptionLength {
+		return fmt.Errorf("Description can not be longer than %d characters", maxDescriptionLength)
+	}
+	if p.Tags != nil {
+		tags, err := normalizeTags(*p.Tags)
+		if err != nil {
+			return err
+		}
+		p.Tags = &tags
+	}
+	if p.Rules != nil {
+		if err := normalizeRules(*p.Rules); err != nil {
+			return err
+		}
+	}
+	if p.Variants != nil {
+		if err := normalizeVariants(*p.Variants); err != nil {
+			return err
+		}
+	}
+	if p.VariantMode != nil {
+		if err := validateVariantMode(*p.VariantMode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Apply copies the given details onto the url
+func (p *Details) Apply(url *models.Url) {
+	if p.Title != nil {
+		url.Title = *p.Title
+	}
+	if p.Description != nil {
+		url.Description = *p.Description
+	}
+	if p.Tags != nil {
+		url.Tags = *p.Tags
+	}
+	if p.Metadata != nil {
+		url.Metadata = *p.Metadata
+	}
+	if p.Rules != nil {
+		url.Rules = *p.Rules
+	}
+	if p.Variants != nil {
+		url.Variants = *p.Variants
+	}
+	if p.VariantMode != nil {
+		url.VariantMode = *p.VariantMode
+	}
+}
+
+// normalizeTags lower cases, trims, de-duplicates and sorts the tags
+func normalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool)
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			return nil, errors.New("Tags can not be empty")
+		}
+		if len(tag) > maxTagLength || strings.Contains(tag, ",") {
+			return nil, fmt.Errorf("Tags must be at most %d characters and can not contain commas", maxTagLength)
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	if len(normalized) > maxTags {
+		return nil, fmt.Errorf("A url can have at most %d tags", maxTags)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+func validateUtmParams(utm *models.UtmParams) error {
+	for name, value := range utm.QueryParams() {
+		if len(value) > maxUtmValueLength {
+			return fmt.Errorf("%s can not be longer than %d characters", name, maxUtmValueLength)
+		}
+	}
+	return nil
+}
+
+func validatePassword(password string) error {
+	if len(password) > maxPasswordLength {
+		return fmt.Errorf("Password can not be longer than %d bytes", maxPasswordLength)
+	}
+	return nil
+}
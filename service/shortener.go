@@ -0,0 +1,441 @@
+// Package service holds the business rules of short urls, their domains and imports: validation,
+// short url generation, timestamps and the mapping of storage conflicts. The HTTP controller, the gRPC server and
+// other callers run their url operations through a Shortener.
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"strings"
+	"time"
+
+	"URL_SHORTENER/models"
+	"URL_SHORTENER/storage"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// CharSet are the characters of generated short urls
+	CharSet           = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	DefaultCodeLength = 8
+	// MaxShortUrlAttempts bounds how often a colliding short url is regenerated
+	MaxShortUrlAttempts = 3
+	// MaxUpdateAttempts bounds how often an update without a required version is applied
+	// again to a url that changed concurrently
+	MaxUpdateAttempts = 3
+	DefaultListLimit  = 50
+	MaxListLimit      = 500
+	// errUrlKeptChanging is returned once the update attempts are used up
+	errUrlKeptChanging = "The short url was changed by other requests, try again."
+)
+
+// DefaultDomain is the namespace of hosts that aren't registered. It answers
+// with the url as JSON instead of redirecting.
+var DefaultDomain = models.Domain{
+	CodeLength: DefaultCodeLength,
+}
+
+// Screener vets the destinations of urls before they are stored
+type Screener interface {
+	// ScreenUrl checks a new url. It may replace the original url, e.g. by the end of its redirect chain.
+	ScreenUrl(ctx context.Context, host string, url *models.Url) error
+	// ScreenDestinations checks the destinations of a url without resolving redirects, like the rules and
+	// variants given to an existing url or the urls of an import
+	ScreenDestinations(host string, url *models.Url) error
+}
+
+// Observer is told about the urls created, changed and deleted
+type Observer interface {
+	UrlCreated(url *models.Url)
+	UrlUpdated(domain string, shortUrl string)
+	UrlDeleted(domain string, shortUrl string)
+}
+
+// Shortener runs the url operations. The host passed to them selects the namespace like the
+// Host header of a request: the registered domain of that name or else the default namespace.
+type Shortener struct {
+	store    storage.URLOperations
+	screener Screener
+	observer Observer
+	clock    Clock
+}
+
+// NewShortener returns a shortener on the store. The screener and observer may be nil,
+// destinations are then stored unchecked and nobody is told about the changes.
+func NewShortener(store storage.URLOperations, screener Screener, observer Observer) *Shortener {
+	return &Shortener{store: store, screener: screener, observer: observer, clock: SystemClock{}}
+}
+
+// SetClock sets the clock the creation times of urls are taken from
+func (s *Shortener) SetClock(clock Clock) {
+	s.clock = clock
+}
+
+// Now returns the current time of the clock in UTC, truncated to the seconds stored
+func (s *Shortener) Now() time.Time {
+	return s.clock.Now().UTC().Truncate(time.Second)
+}
+
+// Domain returns the registered domain matching the host, or the default namespace
+func (s *Shortener) Domain(host string) (*models.Domain, error) {
+	domain, err := s.store.GetDomain(NormalizeHost(host))
+	if err != nil {
+		if err.Error() == storage.ErrDomainDoesNotExist {
+			return &DefaultDomain, nil
+		}
+		return nil, internal("Error resolving domain.")
+	}
+	return domain, nil
+}
+
+// Create validates the params and stores a new url with a generated short url
+func (s *Shortener) Create(ctx context.Context, host string, params *CreateParams) (*models.Url, error) {
+	err := params.Validate()
+	if err != nil {
+		return nil, invalid(err.Error())
+	}
+	domain, err := s.createDomain(host, params.Domain)
+	if err != nil {
+		return nil, err
+	}
+	createdAt := s.Now()
+	passwordHash, err := HashPassword(params.Password)
+	if err != nil {
+		return nil, internal("Error creating url")
+	}
+
+	// Convert to DB request
+	url := &models.Url{
+		Domain:          domain.Name,
+		OriginalUrl:     params.OriginalUrl,
+		CreatedAt:       createdAt,
+		PasswordHash:    passwordHash,
+		MaxClicks:       params.MaxClicks,
+		RemainingClicks: params.MaxClicks,
+		ForwardQuery:    params.ForwardQuery,
+		Status:          models.StatusActive,
+		// New urls start at the first version, the default of the column
+		Version: 1,
+	}
+	if params.Utm != nil && !params.Utm.IsEmpty() {
+		url.Utm = params.Utm
+	}
+	params.Details.Apply(url)
+	if s.screener != nil {
+		if err = s.screener.ScreenUrl(ctx, host, url); err != nil {
+			return nil, invalid(err.Error())
+		}
+	}
+
+	for attempt := 0; attempt < MaxShortUrlAttempts; attempt++ {
+		url.ShortUrl = GenerateShortUrl(domain.CodeLength)
+		err = s.store.InsertUrl(url)
+		// Retry only if the generated short url collided with an existing one
+		if err == nil || err.Error() != storage.ErrShortURLAlreadyExists {
+			break
+		}
+	}
+	if err != nil {
+		// If the URL has already been Shortened
+		if err.Error() == storage.ErrURLAlreadyShortened {
+			return nil, conflict(err.Error())
+		}
+		return nil, internal("Error creating url")
+	}
+	if s.observer != nil {
+		s.observer.UrlCreated(url)
+	}
+	return url, nil
+}
+
+// Get returns a url without following it, so no click is counted
+func (s *Shortener) Get(host string, shortUrl string) (*models.Url, error) {
+	domain, err := s.Domain(host)
+	if err != nil {
+		return nil, err
+	}
+	url, err := s.store.GetOriginalUrl(domain.Name, shortUrl)
+	if err != nil {
+		return nil, notFound(storage.ErrShortURLDoesNotExist)
+	}
+	return url, nil
+}
+
+// Regenerate gives a url a new short url and stores the password and details of the params.
+// It returns the new short url. A version other than 0 is the version the url must still have.
+func (s *Shortener) Regenerate(host string, shortUrl string, version int64, params *UpdateParams) (string, error) {
+	err := s.validateUpdate(host, params)
+	if err != nil {
+		return "", err
+	}
+	domain, err := s.Domain(host)
+	if err != nil {
+		return "", err
+	}
+	var passwordHash string
+	if params.Password != nil {
+		passwordHash, err = HashPassword(*params.Password)
+		if err != nil {
+			return "", internal("Error updating short url.")
+		}
+	}
+
+	// The rename and the settings are written together, on the version the url was read with
+	var newShortUrl string
+	createdAt := s.Now()
+	for attempt := 0; attempt < MaxShortUrlAttempts; attempt++ {
+		var url *models.Url
+		url, err = s.versionedUrl(domain.Name, shortUrl, version)
+		if err != nil {
+			return "", err
+		}
+		if params.Password != nil {
+			url.PasswordHash = passwordHash
+		}
+		params.Details.Apply(url)
+		newShortUrl = GenerateShortUrl(domain.CodeLength)
+		err = s.store.UpdateShortUrl(url, newShortUrl, createdAt)
+		// Retry if the generated short url collided with an existing one, or if the url
+		// changed concurrently and the caller didn't require a version
+		if err == nil || !(err.Error() == storage.ErrShortURLAlreadyExists || retryUpdate(err, version)) {
+			break
+		}
+	}
+	if err != nil {
+		return "", updateError(err, version)
+	}
+	if s.observer != nil {
+		s.observer.UrlUpdated(domain.Name, newShortUrl)
+	}
+	return newShortUrl, nil
+}
+
+// Update changes the password and details of a url while keeping its short url.
+// A version other than 0 is the version the url must still have.
+func (s *Shortener) Update(host string, shortUrl string, version int64, params *UpdateParams) (*models.Url, error) {
+	err := s.validateUpdate(host, params)
+	if err != nil {
+		return nil, err
+	}
+	domain, err := s.Domain(host)
+	if err != nil {
+		return nil, err
+	}
+	if !s.store.CheckShortUrlExists(domain.Name, shortUrl) {
+		return nil, notFound(storage.ErrShortURLDoesNotExist)
+	}
+	var passwordHash string
+	if params.Password != nil {
+		passwordHash, err = HashPassword(*params.Password)
+		if err != nil {
+			return nil, internal("Error updating short url.")
+		}
+	}
+	err = s.updateSettings(domain.Name, shortUrl, version, params, passwordHash)
+	if err != nil {
+		return nil, err
+	}
+	url, err := s.store.GetOriginalUrl(domain.Name, shortUrl)
+	if err != nil {
+		return nil, notFound(storage.ErrShortURLDoesNotExist)
+	}
+	if s.observer != nil {
+		s.observer.UrlUpdated(domain.Name, shortUrl)
+	}
+	return url, nil
+}
+
+// Delete deletes a url and its statistics. A version other than 0 is the version the url must still have.
+func (s *Shortener) Delete(host string, shortUrl string, version int64) error {
+	domain, err := s.Domain(host)
+	if err != nil {
+		return err
+	}
+	err = s.store.DeleteShortUrl(domain.Name, shortUrl, version)
+	if err != nil {
+		switch err.Error() {
+		// If the Short url does not exist
+		case storage.ErrShortURLDoesNotExist:
+			return notFound(err.Error())
+		case storage.ErrVersionMismatch:
+			return preconditionFailed(err.Error())
+		}
+		return internal("Error deleting short url.")
+	}
+	if s.observer != nil {
+		s.observer.UrlDeleted(domain.Name, shortUrl)
+	}
+	return nil
+}
+
+// List lists the urls of the namespace newest first, optionally only those with a tag, status or health
+func (s *Shortener) List(host string, params *ListParams) ([]models.Url, error) {
+	limit := params.Limit
+	if limit == 0 {
+		limit = DefaultListLimit
+	}
+	if limit < 1 || limit > MaxListLimit {
+		return nil, invalid(fmt.Sprintf("limit must be an integer between %d and %d", 1, MaxListLimit))
+	}
+	if params.Offset < 0 || params.Offset > math.MaxInt32 {
+		return nil, invalid(fmt.Sprintf("offset must be an integer between %d and %d", 0, math.MaxInt32))
+	}
+	if params.Status != "" {
+		if err := ValidateStatus(params.Status); err != nil {
+			return nil, invalid(err.Error())
+		}
+	}
+	if params.Health != "" && params.Health != models.HealthOk && params.Health != models.HealthBroken {
+		return nil, invalid("Health must be one of ok, broken")
+	}
+	domain, err := s.Domain(host)
+	if err != nil {
+		return nil, err
+	}
+	urls, err := s.store.ListUrls(&storage.UrlFilter{
+		Domain: domain.Name,
+		Tag:    strings.ToLower(strings.TrimSpace(params.Tag)),
+		Status: params.Status,
+		Health: params.Health,
+		Limit:  limit,
+		Offset: params.Offset,
+	})
+	if err != nil {
+		return nil, internal("Error listing urls.")
+	}
+	return urls, nil
+}
+
+// validateUpdate checks the update params and normalizes their details in place
+func (s *Shortener) validateUpdate(host string, params *UpdateParams) error {
+	err := params.Validate()
+	if err == nil && s.screener != nil {
+		url := &models.Url{}
+		if params.Rules != nil {
+			url.Rules = *params.Rules
+		}
+		if params.Variants != nil {
+			url.Variants = *params.Variants
+		}
+		err = s.screener.ScreenDestinations(host, url)
+	}
+	if err != nil {
+		return invalid(err.Error())
+	}
+	return nil
+}
+
+// updateSettings stores the password and details given in the update params in one write. A version other
+// than 0 is the version the url must still have. Without one the write is limited to the version read, and
+// applied again to the url as changed when a concurrent update came first, so that update is never overwritten.
+func (s *Shortener) updateSettings(domain string, shortUrl string, version int64, params *UpdateParams, passwordHash string) error {
+	unchanged := params.Password == nil && params.Details.isEmpty()
+	if unchanged && version == 0 {
+		return nil
+	}
+	var err error
+	for attempt := 0; attempt < MaxUpdateAttempts; attempt++ {
+		var url *models.Url
+		url, err = s.versionedUrl(domain, shortUrl, version)
+		if err != nil || unchanged {
+			return err
+		}
+		if params.Password != nil {
+			url.PasswordHash = passwordHash
+		}
+		params.Details.Apply(url)
+		err = s.store.UpdateUrlDetails(url)
+		if err == nil || !retryUpdate(err, version) {
+			break
+		}
+	}
+	if err != nil {
+		return updateError(err, version)
+	}
+	return nil
+}
+
+// versionedUrl reads a url to update it, a version other than 0 is the version it must have
+func (s *Shortener) versionedUrl(domain string, shortUrl string, version int64) (*models.Url, error) {
+	url, err := s.store.GetOriginalUrl(domain, shortUrl)
+	if err != nil {
+		return nil, notFound(storage.ErrShortURLDoesNotExist)
+	}
+	if version != 0 && url.Version != version {
+		return nil, preconditionFailed(storage.ErrVersionMismatch)
+	}
+	return url, nil
+}
+
+// retryUpdate reports whether an update failed only because the url changed after it was read,
+// which is retried when the caller didn't require a version
+func retryUpdate(err error, version int64) bool {
+	return version == 0 && err.Error() == storage.ErrVersionMismatch
+}
+
+// updateError converts the error of a url update. A url changed concurrently only fails the
+// precondition of callers that required a version.
+func updateError(err error, version int64) error {
+	switch err.Error() {
+	// If the Short url does not exist
+	case storage.ErrShortURLDoesNotExist:
+		return notFound(err.Error())
+	case storage.ErrVersionMismatch:
+		if version == 0 {
+			return conflict(errUrlKeptChanging)
+		}
+		return preconditionFailed(err.Error())
+	}
+	return internal("Error updating short url.")
+}
+
+// createDomain returns the domain a new url is created on, the named one if given
+// or else the one of the host
+func (s *Shortener) createDomain(host string, name string) (*models.Domain, error) {
+	if name == "" {
+		return s.Domain(host)
+	}
+	domain, err := s.store.GetDomain(NormalizeHost(name))
+	if err != nil {
+		if err.Error() == storage.ErrDomainDoesNotExist {
+			return nil, invalid(err.Error())
+		}
+		return nil, internal("Error resolving domain.")
+	}
+	return domain, nil
+}
+
+// GenerateShortUrl generates a random short url of the given length
+func GenerateShortUrl(length int) string {
+	shortUrl := make([]byte, length)
+	for i := 0; i < length; i++ {
+		charIndex, _ := rand.Int(rand.Reader, big.NewInt(int64(len(CharSet))))
+		shortUrl[i] = CharSet[charIndex.Int64()]
+	}
+	return string(shortUrl)
+}
+
+// HashPassword returns the bcrypt hash of the password, or an empty hash for no password
+func HashPassword(password string) (string, error) {
+	if password == "" {
+		return "", nil
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// NormalizeHost strips the port and lower cases the host
+func NormalizeHost(host string) string {
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
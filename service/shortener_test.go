@@ -0,0 +1,592 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"URL_SHORTENER/models"
+	"URL_SHORTENER/storage"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+const testHost = "example.com"
+
+// recorder is an observer keeping the events it was told about
+type recorder struct {
+	events []string
+}
+
+func (r *recorder) UrlCreated(url *models.Url) {
+	r.events = append(r.events, "created "+url.ShortUrl)
+}
+
+func (r *recorder) UrlUpdated(domain string, shortUrl string) {
+	r.events = append(r.events, "updated "+shortUrl)
+}
+
+func (r *recorder) UrlDeleted(domain string, shortUrl string) {
+	r.events = append(r.events, "deleted "+shortUrl)
+}
+
+// rejecter is a screener refusing the destinations containing blocked
+type rejecter struct{}
+
+func (rejecter) ScreenUrl(ctx context.Context, host string, url *models.Url) error {
+	return rejecter{}.ScreenDestinations(host, url)
+}
+
+func (rejecter) ScreenDestinations(host string, url *models.Url) error {
+	destinations := []string{url.OriginalUrl}
+	for _, rule := range url.Rules {
+		destinations = append(destinations, rule.Destination)
+	}
+	for _, destination := range destinations {
+		if strings.Contains(destination, "blocked") {
+			return errors.New("The destination is blocked")
+		}
+	}
+	return nil
+}
+
+// fixedClock always tells the same time
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+// testNow is the time of the test clock, in a zone other than UTC and with a fraction of a second
+var testNow = time.Date(2024, 10, 16, 23, 5, 18, 500, time.FixedZone("CEST", 2*60*60))
+
+// newTestShortener returns a shortener on a mock store where the test host is the default namespace
+func newTestShortener(t *testing.T) (*Shortener, *storage.MockURLOperations, *recorder) {
+	t.Helper()
+	mockDb := storage.NewMockURLOperations(gomock.NewController(t))
+	mockDb.EXPECT().GetDomain(testHost).AnyTimes().Return(nil, errors.New(storage.ErrDomainDoesNotExist))
+	events := &recorder{}
+	shortener := NewShortener(mockDb, rejecter{}, events)
+	shortener.SetClock(fixedClock(testNow))
+	return shortener, mockDb, events
+}
+
+func requireKind(t *testing.T, err error, kind error, message string) {
+	t.Helper()
+	require.ErrorIs(t, err, kind)
+	require.Equal(t, message, err.Error())
+}
+
+func TestCreate(t *testing.T) {
+	t.Run("Invalid params", func(t *testing.T) {
+		shortener, _, _ := newTestShortener(t)
+		_, err := shortener.Create(context.Background(), testHost, &CreateParams{})
+		requireKind(t, err, ErrInvalid, "Original Url can not be empty")
+
+		maxClicks := 0
+		_, err = shortener.Create(context.Background(), testHost, &CreateParams{OriginalUrl: "http://example.com", MaxClicks: &maxClicks})
+		requireKind(t, err, ErrInvalid, "Max clicks must be greater than zero")
+
+		// bcrypt only hashes the first 72 bytes
+		_, err = shortener.Create(context.Background(), testHost, &CreateParams{OriginalUrl: "http://example.com", Password: strings.Repeat("x", 73)})
+		requireKind(t, err, ErrInvalid, "Password can not be longer than 72 bytes")
+	})
+
+	t.Run("Screened destination", func(t *testing.T) {
+		shortener, _, events := newTestShortener(t)
+		_, err := shortener.Create(context.Background(), testHost, &CreateParams{OriginalUrl: "http://blocked.example"})
+		requireKind(t, err, ErrInvalid, "The destination is blocked")
+		require.Empty(t, events.events)
+	})
+
+	t.Run("Unknown domain", func(t *testing.T) {
+		shortener, mockDb, _ := newTestShortener(t)
+		mockDb.EXPECT().GetDomain("sho.rt").Return(nil, errors.New(storage.ErrDomainDoesNotExist))
+		_, err := shortener.Create(context.Background(), testHost, &CreateParams{OriginalUrl: "http://example.com", Domain: "Sho.rt"})
+		requireKind(t, err, ErrInvalid, storage.ErrDomainDoesNotExist)
+	})
+
+	t.Run("Already shortened", func(t *testing.T) {
+		shortener, mockDb, _ := newTestShortener(t)
+		mockDb.EXPECT().InsertUrl(gomock.Any()).Return(errors.New(storage.ErrURLAlreadyShortened))
+		_, err := shortener.Create(context.Background(), testHost, &CreateParams{OriginalUrl: "http://example.com"})
+		requireKind(t, err, ErrConflict, storage.ErrURLAlreadyShortened)
+	})
+
+	t.Run("Store failure", func(t *testing.T) {
+		shortener, mockDb, _ := newTestShortener(t)
+		mockDb.EXPECT().InsertUrl(gomock.Any()).Return(errors.New("disk full"))
+		_, err := shortener.Create(context.Background(), testHost, &CreateParams{OriginalUrl: "http://example.com"})
+		requireKind(t, err, ErrInternal, "Error creating url")
+	})
+
+	t.Run("Colliding short urls are regenerated", func(t *testing.T) {
+		shortener, mockDb, events := newTestShortener(t)
+		var tried []string
+		mockDb.EXPECT().InsertUrl(gomock.Any()).Times(2).DoAndReturn(func(url *models.Url) error {
+			tried = append(tried, url.ShortUrl)
+			if len(tried) == 1 {
+				return errors.New(storage.ErrShortURLAlreadyExists)
+			}
+			return nil
+		})
+		tags := []string{"Docs", "docs "}
+		url, err := shortener.Create(context.Background(), testHost, &CreateParams{
+			OriginalUrl: "http://example.com",
+			Password:    "secret",
+			Details:     Details{Tags: &tags},
+		})
+		require.NoError(t, err)
+		require.Len(t, tried, 2)
+		require.Equal(t, tried[1], url.ShortUrl)
+		require.Len(t, url.ShortUrl, DefaultCodeLength)
+		require.Equal(t, models.StatusActive, url.Status)
+		require.Equal(t, time.Date(2024, 10, 16, 21, 5, 18, 0, time.UTC), url.CreatedAt)
+		require.Equal(t, []string{"docs"}, url.Tags)
+		require.NotEmpty(t, url.PasswordHash)
+		require.NotEqual(t, "secret", url.PasswordHash)
+		require.Equal(t, []string{"created " + url.ShortUrl}, events.events)
+	})
+
+	t.Run("Gives up after the attempts", func(t *testing.T) {
+		shortener, mockDb, _ := newTestShortener(t)
+		mockDb.EXPECT().InsertUrl(gomock.Any()).Times(MaxShortUrlAttempts).Return(errors.New(storage.ErrShortURLAlreadyExists))
+		_, err := shortener.Create(context.Background(), testHost, &CreateParams{OriginalUrl: "http://example.com"})
+		requireKind(t, err, ErrInternal, "Error creating url")
+	})
+
+	t.Run("Registered domain", func(t *testing.T) {
+		shortener, mockDb, _ := newTestShortener(t)
+		mockDb.EXPECT().GetDomain("sho.rt").Return(&models.Domain{Name: "sho.rt", CodeLength: 5}, nil)
+		mockDb.EXPECT().InsertUrl(gomock.Any()).Return(nil)
+		url, err := shortener.Create(context.Background(), "sho.rt:8080", &CreateParams{OriginalUrl: "http://example.com"})
+		require.NoError(t, err)
+		require.Equal(t, "sho.rt", url.Domain)
+		require.Len(t, url.ShortUrl, 5)
+	})
+}
+
+func TestGet(t *testing.T) {
+	shortener, mockDb, _ := newTestShortener(t)
+	mockDb.EXPECT().GetOriginalUrl("", "abc").Return(&models.Url{ShortUrl: "abc", OriginalUrl: "http://example.com"}, nil)
+	mockDb.EXPECT().GetOriginalUrl("", "missing").Return(nil, errors.New("sql: no rows in result set"))
+
+	url, err := shortener.Get(testHost, "abc")
+	require.NoError(t, err)
+	require.Equal(t, "http://example.com", url.OriginalUrl)
+	_, err = shortener.Get(testHost, "missing")
+	requireKind(t, err, ErrNotFound, storage.ErrShortURLDoesNotExist)
+}
+
+func TestRegenerate(t *testing.T) {
+	t.Run("Unknown short url", func(t *testing.T) {
+		shortener, mockDb, events := newTestShortener(t)
+		mockDb.EXPECT().GetOriginalUrl("", "abc").Return(nil, errors.New("sql: no rows in result set"))
+		_, err := shortener.Regenerate(testHost, "abc", 0, &UpdateParams{})
+		requireKind(t, err, ErrNotFound, storage.ErrShortURLDoesNotExist)
+		require.Empty(t, events.events)
+	})
+
+	t.Run("Changed since the version", func(t *testing.T) {
+		shortener, mockDb, events := newTestShortener(t)
+		mockDb.EXPECT().GetOriginalUrl("", "abc").Return(&models.Url{ShortUrl: "abc", Version: 3}, nil)
+		_, err := shortener.Regenerate(testHost, "abc", 2, &UpdateParams{})
+		requireKind(t, err, ErrPreconditionFailed, storage.ErrVersionMismatch)
+		require.Empty(t, events.events)
+	})
+
+	t.Run("Changed while renaming without a version", func(t *testing.T) {
+		shortener, mockDb, events := newTestShortener(t)
+		mockDb.EXPECT().GetOriginalUrl("", "abc").Times(2).Return(&models.Url{ShortUrl: "abc", Version: 2}, nil)
+		gomock.InOrder(
+			mockDb.EXPECT().UpdateShortUrl(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New(storage.ErrVersionMismatch)),
+			mockDb.EXPECT().UpdateShortUrl(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+		)
+		shortUrl, err := shortener.Regenerate(testHost, "abc", 0, &UpdateParams{})
+		require.NoError(t, err)
+		require.Equal(t, []string{"updated " + shortUrl}, events.events)
+	})
+
+	t.Run("Changed while renaming", func(t *testing.T) {
+		shortener, mockDb, events := newTestShortener(t)
+		mockDb.EXPECT().GetOriginalUrl("", "abc").Return(&models.Url{ShortUrl: "abc", Version: 2}, nil)
+		mockDb.EXPECT().UpdateShortUrl(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New(storage.ErrVersionMismatch))
+		_, err := shortener.Regenerate(testHost, "abc", 2, &UpdateParams{})
+		requireKind(t, err, ErrPreconditionFailed, storage.ErrVersionMismatch)
+		require.Empty(t, events.events)
+	})
+
+	t.Run("New short url and details", func(t *testing.T) {
+		shortener, mockDb, events := newTestShortener(t)
+		title := "Docs"
+		var newShortUrl string
+		mockDb.EXPECT().GetOriginalUrl("", "abc").Return(&models.Url{ShortUrl: "abc", OriginalUrl: "http://example.com", Version: 2}, nil)
+		// The details are renamed with the url in one write, on the version it was read with
+		mockDb.EXPECT().UpdateShortUrl(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+			func(url *models.Url, shortUrl string, createdAt time.Time) error {
+				require.Equal(t, "abc", url.ShortUrl)
+				require.Equal(t, title, url.Title)
+				require.Equal(t, int64(2), url.Version)
+				require.Equal(t, time.Date(2024, 10, 16, 21, 5, 18, 0, time.UTC), createdAt)
+				newShortUrl = shortUrl
+				return nil
+			})
+		shortUrl, err := shortener.Regenerate(testHost, "abc", 2, &UpdateParams{Details: Details{Title: &title}})
+		require.NoError(t, err)
+		require.Equal(t, newShortUrl, shortUrl)
+		require.Equal(t, []string{"updated " + shortUrl}, events.events)
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	t.Run("Invalid details", func(t *testing.T) {
+		shortener, _, _ := newTestShortener(t)
+		title := strings.Repeat("x", maxTitleLength+1)
+		_, err := shortener.Update(testHost, "abc", 0, &UpdateParams{Details: Details{Title: &title}})
+		requireKind(t, err, ErrInvalid, "Title can not be longer than 200 characters")
+
+		password := strings.Repeat("x", 73)
+		_, err = shortener.Update(testHost, "abc", 0, &UpdateParams{Password: &password})
+		requireKind(t, err, ErrInvalid, "Password can not be longer than 72 bytes")
+	})
+
+	t.Run("Screened rule destination", func(t *testing.T) {
+		shortener, _, _ := newTestShortener(t)
+		rules := []models.TargetingRule{{Destination: "http://blocked.example"}}
+		_, err := shortener.Update(testHost, "abc", 0, &UpdateParams{Details: Details{Rules: &rules}})
+		requireKind(t, err, ErrInvalid, "The destination is blocked")
+	})
+
+	t.Run("Unknown short url", func(t *testing.T) {
+		shortener, mockDb, _ := newTestShortener(t)
+		mockDb.EXPECT().CheckShortUrlExists("", "abc").Return(false)
+		_, err := shortener.Update(testHost, "abc", 0, &UpdateParams{})
+		requireKind(t, err, ErrNotFound, storage.ErrShortURLDoesNotExist)
+	})
+
+	t.Run("Changed since the version", func(t *testing.T) {
+		shortener, mockDb, events := newTestShortener(t)
+		title := "Docs"
+		mockDb.EXPECT().CheckShortUrlExists("", "abc").Return(true)
+		mockDb.EXPECT().GetOriginalUrl("", "abc").Return(&models.Url{ShortUrl: "abc", Version: 3}, nil)
+		_, err := shortener.Update(testHost, "abc", 2, &UpdateParams{Details: Details{Title: &title}})
+		requireKind(t, err, ErrPreconditionFailed, storage.ErrVersionMismatch)
+		require.Empty(t, events.events)
+	})
+
+	t.Run("Changed concurrently since the version", func(t *testing.T) {
+		shortener, mockDb, _ := newTestShortener(t)
+		title := "Docs"
+		mockDb.EXPECT().CheckShortUrlExists("", "abc").Return(true)
+		mockDb.EXPECT().GetOriginalUrl("", "abc").Return(&models.Url{ShortUrl: "abc", Version: 3}, nil)
+		mockDb.EXPECT().UpdateUrlDetails(gomock.Any()).Return(errors.New(storage.ErrVersionMismatch))
+		_, err := shortener.Update(testHost, "abc", 3, &UpdateParams{Details: Details{Title: &title}})
+		requireKind(t, err, ErrPreconditionFailed, storage.ErrVersionMismatch)
+	})
+
+	t.Run("Changed concurrently without a version", func(t *testing.T) {
+		shortener, mockDb, _ := newTestShortener(t)
+		title := "Docs"
+		mockDb.EXPECT().CheckShortUrlExists("", "abc").Return(true)
+		gomock.InOrder(
+			mockDb.EXPECT().GetOriginalUrl("", "abc").Return(&models.Url{ShortUrl: "abc", Version: 3}, nil),
+			mockDb.EXPECT().GetOriginalUrl("", "abc").Return(&models.Url{ShortUrl: "abc", Description: "About", Version: 4}, nil),
+			mockDb.EXPECT().GetOriginalUrl("", "abc").Return(&models.Url{ShortUrl: "abc", Title: title, Description: "About", Version: 5}, nil),
+		)
+		// The update is applied again to the url as the concurrent update left it
+		gomock.InOrder(
+			mockDb.EXPECT().UpdateUrlDetails(gomock.Any()).Return(errors.New(storage.ErrVersionMismatch)),
+			mockDb.EXPECT().UpdateUrlDetails(gomock.Any()).DoAndReturn(func(url *models.Url) error {
+				require.Equal(t, int64(4), url.Version)
+				require.Equal(t, "About", url.Description)
+				require.Equal(t, title, url.Title)
+				return nil
+			}),
+		)
+		url, err := shortener.Update(testHost, "abc", 0, &UpdateParams{Details: Details{Title: &title}})
+		require.NoError(t, err)
+		require.Equal(t, int64(5), url.Version)
+	})
+
+	t.Run("Kept changing without a version", func(t *testing.T) {
+		shortener, mockDb, _ := newTestShortener(t)
+		title := "Docs"
+		mockDb.EXPECT().CheckShortUrlExists("", "abc").Return(true)
+		mockDb.EXPECT().GetOriginalUrl("", "abc").Times(MaxUpdateAttempts).Return(&models.Url{ShortUrl: "abc", Version: 3}, nil)
+		mockDb.EXPECT().UpdateUrlDetails(gomock.Any()).Times(MaxUpdateAttempts).Return(errors.New(storage.ErrVersionMismatch))
+		_, err := shortener.Update(testHost, "abc", 0, &UpdateParams{Details: Details{Title: &title}})
+		requireKind(t, err, ErrConflict, errUrlKeptChanging)
+	})
+
+	t.Run("Password and details", func(t *testing.T) {
+		shortener, mockDb, events := newTestShortener(t)
+		password := ""
+		description := "About"
+		mockDb.EXPECT().CheckShortUrlExists("", "abc").Return(true)
+		mockDb.EXPECT().GetOriginalUrl("", "abc").Times(2).Return(&models.Url{ShortUrl: "abc", PasswordHash: "hash", Description: description, Version: 2}, nil)
+		// The password and details are stored in one write limited to the version read
+		mockDb.EXPECT().UpdateUrlDetails(gomock.Any()).DoAndReturn(func(url *models.Url) error {
+			require.Empty(t, url.PasswordHash)
+			require.Equal(t, int64(2), url.Version)
+			return nil
+		})
+		url, err := shortener.Update(testHost, "abc", 2, &UpdateParams{Password: &password, Details: Details{Description: &description}})
+		require.NoError(t, err)
+		require.Equal(t, description, url.Description)
+		require.Equal(t, []string{"updated abc"}, events.events)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	shortener, mockDb, events := newTestShortener(t)
+	mockDb.EXPECT().DeleteShortUrl("", "abc", int64(0)).Return(nil)
+	mockDb.EXPECT().DeleteShortUrl("", "missing", int64(0)).Return(errors.New(storage.ErrShortURLDoesNotExist))
+	mockDb.EXPECT().DeleteShortUrl("", "changed", int64(2)).Return(errors.New(storage.ErrVersionMismatch))
+	mockDb.EXPECT().DeleteShortUrl("", "broken", int64(0)).Return(errors.New("disk failure"))
+
+	require.NoError(t, shortener.Delete(testHost, "abc", 0))
+	requireKind(t, shortener.Delete(testHost, "missing", 0), ErrNotFound, storage.ErrShortURLDoesNotExist)
+	requireKind(t, shortener.Delete(testHost, "changed", 2), ErrPreconditionFailed, storage.ErrVersionMismatch)
+	requireKind(t, shortener.Delete(testHost, "broken", 0), ErrInternal, "Error deleting short url.")
+	require.Equal(t, []string{"deleted abc"}, events.events)
+}
+
+func TestList(t *testing.T) {
+	t.Run("Invalid params", func(t *testing.T) {
+		shortener, _, _ := newTestShortener(t)
+		_, err := shortener.List(testHost, &ListParams{Limit: MaxListLimit + 1})
+		requireKind(t, err, ErrInvalid, "limit must be an integer between 1 and 500")
+		_, err = shortener.List(testHost, &ListParams{Offset: -1})
+		require.ErrorIs(t, err, ErrInvalid)
+		_, err = shortener.List(testHost, &ListParams{Status: "gone"})
+		requireKind(t, err, ErrInvalid, "Status must be one of active, disabled, blocked")
+		_, err = shortener.List(testHost, &ListParams{Health: "unknown"})
+		requireKind(t, err, ErrInvalid, "Health must be one of ok, broken")
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		shortener, mockDb, _ := newTestShortener(t)
+		mockDb.EXPECT().ListUrls(&storage.UrlFilter{Tag: "docs", Status: models.StatusActive, Limit: DefaultListLimit, Offset: 10}).
+			Return([]models.Url{{ShortUrl: "abc"}}, nil)
+		urls, err := shortener.List(testHost, &ListParams{Tag: " Docs", Status: models.StatusActive, Offset: 10})
+		require.NoError(t, err)
+		require.Len(t, urls, 1)
+	})
+}
+
+func TestRegisterDomain(t *testing.T) {
+	t.Run("Invalid params", func(t *testing.T) {
+		shortener, _, _ := newTestShortener(t)
+		_, err := shortener.RegisterDomain(&DomainParams{Name: "https://sho.rt"})
+		requireKind(t, err, ErrInvalid, "Domain name must be a bare host name like sho.rt")
+		_, err = shortener.RegisterDomain(&DomainParams{Name: "sho.rt", RedirectType: 200})
+		requireKind(t, err, ErrInvalid, "Redirect type must be one of 301, 302, 307, 308")
+	})
+
+	t.Run("Defaults", func(t *testing.T) {
+		shortener, mockDb, _ := newTestShortener(t)
+		mockDb.EXPECT().InsertDomain(gomock.Any()).Return(nil)
+		domain, err := shortener.RegisterDomain(&DomainParams{Name: "Sho.RT"})
+		require.NoError(t, err)
+		require.Equal(t, &models.Domain{Name: "sho.rt", CodeLength: DefaultCodeLength, RedirectType: 302,
+			CreatedAt: "2024-10-16T21:05:18Z"}, domain)
+	})
+
+	t.Run("Already registered", func(t *testing.T) {
+		shortener, mockDb, _ := newTestShortener(t)
+		mockDb.EXPECT().InsertDomain(gomock.Any()).Return(errors.New(storage.ErrDomainAlreadyExists))
+		_, err := shortener.RegisterDomain(&DomainParams{Name: "sho.rt"})
+		requireKind(t, err, ErrConflict, storage.ErrDomainAlreadyExists)
+	})
+
+	t.Run("Delete unknown domain", func(t *testing.T) {
+		shortener, mockDb, _ := newTestShortener(t)
+		mockDb.EXPECT().DeleteDomain("sho.rt").Return(errors.New(storage.ErrDomainDoesNotExist))
+		requireKind(t, shortener.DeleteDomain("Sho.rt"), ErrNotFound, storage.ErrDomainDoesNotExist)
+	})
+}
+
+func TestSetStatus(t *testing.T) {
+	t.Run("Invalid status", func(t *testing.T) {
+		shortener, _, _ := newTestShortener(t)
+		_, err := shortener.SetStatus(testHost, "esd87df7", &StatusParams{Status: "paused"})
+		requireKind(t, err, ErrInvalid, "Status must be one of active, disabled, blocked")
+		_, err = shortener.SetStatus(testHost, "esd87df7", &StatusParams{Status: models.StatusDisabled, Reason: strings.Repeat("x", 501)})
+		requireKind(t, err, ErrInvalid, "Reason can be at most 500 characters")
+	})
+
+	t.Run("Reactivating drops the reason", func(t *testing.T) {
+		shortener, mockDb, events := newTestShortener(t)
+		mockDb.EXPECT().SetUrlStatus("", "esd87df7", models.StatusActive, "").Return(nil)
+		mockDb.EXPECT().GetOriginalUrl("", "esd87df7").Return(&models.Url{ShortUrl: "esd87df7", Status: models.StatusActive}, nil)
+		url, err := shortener.SetStatus(testHost, "esd87df7", &StatusParams{Status: models.StatusActive, Reason: "Looked fine"})
+		require.NoError(t, err)
+		require.Equal(t, models.StatusActive, url.Status)
+		require.Equal(t, []string{"updated esd87df7"}, events.events)
+	})
+
+	t.Run("Unknown url", func(t *testing.T) {
+		shortener, mockDb, events := newTestShortener(t)
+		mockDb.EXPECT().SetUrlStatus("", "missing", models.StatusDisabled, "").Return(errors.New(storage.ErrShortURLDoesNotExist))
+		_, err := shortener.SetStatus(testHost, "missing", &StatusParams{Status: models.StatusDisabled})
+		requireKind(t, err, ErrNotFound, storage.ErrShortURLDoesNotExist)
+		require.Empty(t, events.events)
+	})
+}
+
+func TestImport(t *testing.T) {
+	t.Run("Invalid conflict mode", func(t *testing.T) {
+		shortener, _, _ := newTestShortener(t)
+		_, err := shortener.NewImporter(testHost, "merge", false)
+		requireKind(t, err, ErrInvalid, "on_conflict must be one of error, skip, overwrite, rename")
+	})
+
+	t.Run("Imported urls are created like new ones", func(t *testing.T) {
+		shortener, mockDb, events := newTestShortener(t)
+		mockDb.EXPECT().InsertUrl(gomock.Any()).DoAndReturn(func(url *models.Url) error {
+			require.Equal(t, "My_link-1", url.ShortUrl)
+			require.Equal(t, time.Date(2024, 10, 16, 21, 5, 18, 0, time.UTC), url.CreatedAt)
+			require.Equal(t, 7, url.ClickCount)
+			return nil
+		})
+		importer, err := shortener.NewImporter(testHost, "", false)
+		require.NoError(t, err)
+		result := importer.Import(&ImportRecord{ShortUrl: "My_link-1", CreatedAt: "2024-10-16T23:05:18+02:00", ClickCount: 7,
+			CreateParams: CreateParams{OriginalUrl: "http://example.com"}})
+		require.Equal(t, ImportResult{ShortUrl: "My_link-1", Status: ImportStatusImported}, result)
+		require.Equal(t, []string{"created My_link-1"}, events.events)
+	})
+
+	t.Run("Invalid records", func(t *testing.T) {
+		shortener, _, _ := newTestShortener(t)
+		importer, err := shortener.NewImporter(testHost, ConflictSkip, false)
+		require.NoError(t, err)
+		for record, message := range map[*ImportRecord]string{
+			{ShortUrl: "my link", CreateParams: CreateParams{OriginalUrl: "http://example.com"}}:     "Short url must be at most 32 letters, digits, - or _",
+			{ShortUrl: "export", CreateParams: CreateParams{OriginalUrl: "http://example.com"}}:      "Short url export is reserved",
+			{CreatedAt: "16.10.2024", CreateParams: CreateParams{OriginalUrl: "http://example.com"}}: "Created at must be formatted as RFC 3339 or like 2006-01-02 15:04:05",
+			{PasswordHash: "secret", CreateParams: CreateParams{OriginalUrl: "http://example.com"}}:  "Password hash must be a bcrypt hash",
+			{CreateParams: CreateParams{OriginalUrl: "http://blocked.example"}}:                      "The destination is blocked",
+		} {
+			require.Equal(t, ImportResult{ShortUrl: record.ShortUrl, Status: ImportStatusFailed, Error: message}, importer.Import(record))
+		}
+	})
+
+	t.Run("Short urls in use are renamed", func(t *testing.T) {
+		shortener, mockDb, _ := newTestShortener(t)
+		gomock.InOrder(
+			mockDb.EXPECT().InsertUrl(gomock.Any()).Return(errors.New(storage.ErrShortURLAlreadyExists)),
+			mockDb.EXPECT().InsertUrl(gomock.Any()).Return(nil),
+		)
+		importer, err := shortener.NewImporter(testHost, ConflictRename, false)
+		require.NoError(t, err)
+		result := importer.Import(&ImportRecord{ShortUrl: "docs", CreateParams: CreateParams{OriginalUrl: "http://example.com"}})
+		require.Equal(t, ImportStatusRenamed, result.Status)
+		require.Len(t, result.ShortUrl, DefaultCodeLength)
+	})
+
+	t.Run("Dry run", func(t *testing.T) {
+		shortener, mockDb, events := newTestShortener(t)
+		mockDb.EXPECT().CheckShortUrlExists("", "docs").Return(false)
+		mockDb.EXPECT().CheckOriginalUrlExists("", "http://example.com").Return(false)
+		importer, err := shortener.NewImporter(testHost, ConflictSkip, true)
+		require.NoError(t, err)
+		record := func() *ImportRecord {
+			return &ImportRecord{ShortUrl: "docs", CreateParams: CreateParams{OriginalUrl: "http://example.com"}}
+		}
+		require.Equal(t, ImportStatusImported, importer.Import(record()).Status)
+		// The second row conflicts with the first one, which wasn't stored
+		require.Equal(t, ImportStatusSkipped, importer.Import(record()).Status)
+		require.Empty(t, events.events)
+	})
+}
+
+func TestParseImportedTime(t *testing.T) {
+	now := time.Date(2024, 10, 17, 8, 0, 0, 0, time.UTC)
+	createdAt, err := parseImportedTime("2024-10-16T23:05:18+02:00", now)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 10, 16, 21, 5, 18, 0, time.UTC), createdAt)
+	// Older exports have the local time without a zone
+	createdAt, err = parseImportedTime("2024-10-16 23:05:18", now)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 10, 16, 23, 5, 18, 0, time.Local).UTC(), createdAt)
+	createdAt, err = parseImportedTime("", now)
+	require.NoError(t, err)
+	require.Equal(t, now, createdAt)
+	_, err = parseImportedTime("16.10.2024", now)
+	require.Error(t, err)
+
+	require.NoError(t, validateImportedShortUrl("My_link-1"))
+	require.Error(t, validateImportedShortUrl("my link"))
+	require.Error(t, validateImportedShortUrl("export"))
+	require.Error(t, validateImportedShortUrl(strings.Repeat("a", maxCodeLength+1)))
+}
+
+func TestValidateDetails(t *testing.T) {
+	tags := []string{"b", "A", "a"}
+	mode := "sometimes"
+	rules := []models.TargetingRule{{Destination: "http://example.com", Platforms: []string{"IOS"}, Countries: []string{" de"},
+		StartsAt: "2024-11-01T00:00:00+01:00"}}
+	details := &Details{Tags: &tags, Rules: &rules}
+	require.NoError(t, details.Validate())
+	require.Equal(t, []string{"a", "b"}, *details.Tags)
+	require.Equal(t, "ios", rules[0].Platforms[0])
+	require.Equal(t, "DE", rules[0].Countries[0])
+	require.Equal(t, "2024-10-31T23:00:00Z", rules[0].StartsAt)
+
+	// Times without a zone are refused
+	rules[0].StartsAt = "2024-11-01 00:00:00"
+	require.EqualError(t, details.Validate(), "Rule 1: times must be formatted as RFC 3339, like 2024-10-16T21:05:18Z")
+
+	// Visitors can only be sent on to absolute web urls
+	for _, destination := range []string{"foo", "javascript:alert(1)", "/path", "//example.com", "ftp://example.com", "https://", "http://exa mple.com"} {
+		rules = []models.TargetingRule{{Destination: destination}}
+		details = &Details{Rules: &rules}
+		require.EqualError(t, details.Validate(), "Rule 1: destination must be an absolute http or https url", destination)
+	}
+	rules = []models.TargetingRule{{Destination: "HTTPS://example.com/de?lang=de"}}
+	details = &Details{Rules: &rules}
+	require.NoError(t, details.Validate())
+
+	details = &Details{VariantMode: &mode}
+	require.EqualError(t, details.Validate(), "Variant mode must be one of random, sticky")
+
+	variants := []models.Variant{{Destination: "http://a.example", Weight: 1}, {Destination: "http://b.example", ClickCount: 7}}
+	details = &Details{Variants: &variants}
+	require.NoError(t, details.Validate())
+	require.Equal(t, "A", variants[0].Name)
+	require.Equal(t, "B", variants[1].Name)
+	require.Zero(t, variants[1].ClickCount)
+
+	for _, destination := range []string{"foo", "javascript:alert(1)", "/path", "//example.com", "ftp://example.com", "https://", "http://exa mple.com"} {
+		variants = []models.Variant{{Destination: "http://a.example", Weight: 1}, {Destination: destination, Weight: 1}}
+		details = &Details{Variants: &variants}
+		require.EqualError(t, details.Validate(), "Variant B: destination must be an absolute http or https url", destination)
+	}
+}
+
+func TestGenerateShortUrl(t *testing.T) {
+	var length = 8
+	shortUrl1 := GenerateShortUrl(length)
+	shortUrl2 := GenerateShortUrl(length)
+
+	// Check if the length of the generated short URL is correct
+	if len(shortUrl1) != length {
+		t.Errorf("Expected URL length of %d, but got %d", length, len(shortUrl1))
+	}
+
+	// Check if the generated URLs are unique
+	if shortUrl1 == shortUrl2 {
+		t.Errorf("Expected unique URLs, but got identical URLs: %s and %s", shortUrl1, shortUrl2)
+	}
+
+	// Check if the URL contains only allowed characters
+	for _, char := range shortUrl1 {
+		if !strings.ContainsRune(CharSet, char) {
+			t.Errorf("Generated URL contains invalid character: %c", char)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"fmt"
+
+	"URL_SHORTENER/models"
+	"URL_SHORTENER/storage"
+)
+
+const maxStatusReason = 500
+
+type StatusParams struct {
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Validate checks the status and drops the reason of an active url, which would only be stale
+func (p *StatusParams) Validate() error {
+	if err := ValidateStatus(p.Status); err != nil {
+		return err
+	}
+	if len(p.Reason) > maxStatusReason {
+		return fmt.Errorf("Reason can be at most %d characters", maxStatusReason)
+	}
+	if p.Status == models.StatusActive {
+		p.Reason = ""
+	}
+	return nil
+}
+
+// SetStatus disables, blocks or reactivates a url and returns it with its new status
+func (s *Shortener) SetStatus(host string, shortUrl string, params *StatusParams) (*models.Url, error) {
+	if err := params.Validate(); err != nil {
+		return nil, invalid(err.Error())
+	}
+	domain, err := s.Domain(host)
+	if err != nil {
+		return nil, err
+	}
+	err = s.store.SetUrlStatus(domain.Name, shortUrl, params.Status, params.Reason)
+	if err != nil {
+		if err.Error() == storage.ErrShortURLDoesNotExist {
+			return nil, notFound(err.Error())
+		}
+		return nil, internal("Error changing the status.")
+	}
+	url, err := s.store.GetOriginalUrl(domain.Name, shortUrl)
+	if err != nil {
+		return nil, internal("Error retrieving url.")
+	}
+	if s.observer != nil {
+		s.observer.UrlUpdated(domain.Name, shortUrl)
+	}
+	return url, nil
+}
@@ -0,0 +1,285 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"URL_SHORTENER/models"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+const (
+	backupPrefix = "backup-"
+	backupSuffix = ".sqlite3"
+	// backupTimeLayout names the backups after the UTC time they were made at
+	backupTimeLayout = "20060102-150405.000Z"
+	// localBackupTimeLayout named older backups after the local time, which repeats itself when the clocks go back
+	localBackupTimeLayout = "20060102-150405.000"
+	checksumSuffix        = ".sha256"
+)
+
+var ErrBackupChecksumMissing = "The checksum file of the backup is missing."
+var ErrBackupChecksumMismatch = "The backup does not match its checksum."
+var ErrBackupCorrupt = "The backup failed the integrity check."
+var ErrBackupSchemaTooNew = "The backup has a newer schema than this version supports."
+
+// Backup writes a consistent snapshot of the database into dir with VACUUM INTO, which
+// doesn't block other connections. The SHA-256 checksum is written next to it in the
+// format of sha256sum. Only the newest keep backups are kept, all of them when keep is 0.
+func (s *URLStore) Backup(dir string, keep int) (*models.Backup, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	name := backupPrefix + now.Format(backupTimeLayout) + backupSuffix
+	path := filepath.Join(dir, name)
+	// The snapshot only gets its final name once it is complete
+	tmpPath := path + ".tmp"
+	if _, err := s.db.Exec(`VACUUM INTO ?`, tmpPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return nil, err
+	}
+	checksum, size, err := fileChecksum(tmpPath)
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return nil, err
+	}
+	if err = os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return nil, err
+	}
+	if err = os.WriteFile(path+checksumSuffix, []byte(checksum+"  "+name+"\n"), 0o644); err != nil {
+		return nil, err
+	}
+	var version int
+	if err = s.db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		return nil, err
+	}
+	if err = rotateBackups(dir, keep); err != nil {
+		return nil, err
+	}
+	return &models.Backup{
+		Name:          name,
+		Size:          size,
+		Sha256:        checksum,
+		SchemaVersion: version,
+		CreatedAt:     models.FormatTimestamp(now),
+	}, nil
+}
+
+// ListBackups returns the backups in dir, newest first
+func ListBackups(dir string) ([]models.Backup, error) {
+	names, err := backupNames(dir)
+	if err != nil {
+		return nil, err
+	}
+	backups := make([]models.Backup, 0, len(names))
+	for i := len(names) - 1; i >= 0; i-- {
+		backup, err := readBackup(filepath.Join(dir, names[i]))
+		if err != nil {
+			return nil, err
+		}
+		backups = append(backups, *backup)
+	}
+	return backups, nil
+}
+
+// RestoreBackup replaces the database at dbPath with the backup, after checking it against
+// its checksum, its integrity and the schema version. The pages are copied with the SQLite
+// online backup API, so the write-ahead log of the database is taken care of. Backups of
+// an older schema are migrated. The server should be stopped while restoring.
+func RestoreBackup(backupPath string, dbPath string) (*models.Backup, error) {
+	backup, err := VerifyBackup(backupPath)
+	if err != nil {
+		return nil, err
+	}
+	source, err := sql.Open(SQLITE, "file:"+backupPath+"?mode=ro")
+	if err != nil {
+		return nil, err
+	}
+	defer source.Close()
+	destination, err := sql.Open(SQLITE, dataSourceName(dbPath))
+	if err != nil {
+		return nil, err
+	}
+	defer destination.Close()
+
+	ctx := context.Background()
+	sourceConn, err := source.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer sourceConn.Close()
+	destinationConn, err := destination.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer destinationConn.Close()
+	err = destinationConn.Raw(func(destinationDriverConn interface{}) error {
+		return sourceConn.Raw(func(sourceDriverConn interface{}) error {
+			copier, err := destinationDriverConn.(*sqlite3.SQLiteConn).Backup("main", sourceDriverConn.(*sqlite3.SQLiteConn), "main")
+			if err != nil {
+				return err
+			}
+			if _, err = copier.Step(-1); err != nil {
+				_ = copier.Finish()
+				return err
+			}
+			return copier.Finish()
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = migrate(destination); err != nil {
+		return nil, err
+	}
+	return backup, nil
+}
+
+// VerifyBackup checks the backup against its checksum file, runs the SQLite integrity
+// check on it and makes sure its schema isn't newer than the migrations
+func VerifyBackup(path string) (*models.Backup, error) {
+	expected, err := os.ReadFile(path + checksumSuffix)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, errors.New(ErrBackupChecksumMissing)
+		}
+		return nil, err
+	}
+	backup, err := readBackup(path)
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.Fields(string(expected))
+	if len(fields) == 0 || fields[0] != backup.Sha256 {
+		return nil, errors.New(ErrBackupChecksumMismatch)
+	}
+
+	db, err := sql.Open(SQLITE, "file:"+path+"?mode=ro")
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	var result string
+	if err = db.QueryRow(`PRAGMA integrity_check`).Scan(&result); err != nil || result != "ok" {
+		return nil, errors.New(ErrBackupCorrupt)
+	}
+	if backup.SchemaVersion > SchemaVersion() {
+		return nil, fmt.Errorf("%s Backup schema version %d, supported %d", ErrBackupSchemaTooNew, backup.SchemaVersion, SchemaVersion())
+	}
+	return backup, nil
+}
+
+// readBackup describes the backup file. The checksum is computed from the file, not read from the checksum file.
+func readBackup(path string) (*models.Backup, error) {
+	checksum, size, err := fileChecksum(path)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open(SQLITE, "file:"+path+"?mode=ro")
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	var version int
+	if err = db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		return nil, errors.New(ErrBackupCorrupt)
+	}
+	backup := &models.Backup{
+		Name:          filepath.Base(path),
+		Size:          size,
+		Sha256:        checksum,
+		SchemaVersion: version,
+	}
+	if createdAt := backupTime(backup.Name); !createdAt.IsZero() {
+		backup.CreatedAt = models.FormatTimestamp(createdAt)
+	}
+	return backup, nil
+}
+
+// backupTime returns the time a backup was made at from its name, the zero time if the name holds none
+func backupTime(name string) time.Time {
+	timestamp := strings.TrimSuffix(strings.TrimPrefix(name, backupPrefix), backupSuffix)
+	if createdAt, err := time.Parse(backupTimeLayout, timestamp); err == nil {
+		return createdAt
+	}
+	if createdAt, err := time.ParseInLocation(localBackupTimeLayout, timestamp, time.Local); err == nil {
+		return createdAt
+	}
+	return time.Time{}
+}
+
+func fileChecksum(path string) (string, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+// backupNames returns the names of the backups in dir, oldest first
+func backupNames(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasPrefix(name, backupPrefix) && strings.HasSuffix(name, backupSuffix) {
+			names = append(names, name)
+		}
+	}
+	// Sorted by the time in the name, as older backups named after the local time are mixed in
+	sort.Slice(names, func(i, j int) bool {
+		first, second := backupTime(names[i]), backupTime(names[j])
+		if first.Equal(second) {
+			return names[i] < names[j]
+		}
+		return first.Before(second)
+	})
+	return names, nil
+}
+
+// rotateBackups deletes all but the newest keep backups and their checksums
+func rotateBackups(dir string, keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+	names, err := backupNames(dir)
+	if err != nil {
+		return err
+	}
+	for len(names) > keep {
+		path := filepath.Join(dir, names[0])
+		if err = os.Remove(path); err != nil {
+			return err
+		}
+		if err = os.Remove(path + checksumSuffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		names = names[1:]
+	}
+	return nil
+}
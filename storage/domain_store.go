@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+
+	"URL_SHORTENER/models"
+)
+
+var ErrDomainAlreadyExists = "The specified domain has already been registered."
+var ErrDomainDoesNotExist = "The specified domain is not registered."
+
+type DomainOperations interface {
+	InsertDomain(domain *models.Domain) error
+	GetDomain(name string) (*models.Domain, error)
+	ListDomains() ([]models.Domain, error)
+	DeleteDomain(name string) error
+}
+
+func (s *URLStore) InsertDomain(domain *models.Domain) error {
+	insertDomainQuery := `INSERT INTO domains (name, code_length, redirect_type, fallback_url, created_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (name) DO NOTHING`
+	result, err := s.db.Exec(insertDomainQuery, domain.Name, domain.CodeLength, domain.RedirectType, domain.FallbackUrl, domain.CreatedAt)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New(ErrDomainAlreadyExists)
+	}
+	return nil
+}
+
+func (s *URLStore) GetDomain(name string) (*models.Domain, error) {
+	getDomainQuery := `SELECT name, code_length, redirect_type, fallback_url, created_at FROM domains WHERE name = ?`
+	var domain models.Domain
+	err := s.db.QueryRow(getDomainQuery, name).Scan(&domain.Name, &domain.CodeLength, &domain.RedirectType, &domain.FallbackUrl, &domain.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New(ErrDomainDoesNotExist)
+		}
+		return nil, err
+	}
+	return &domain, nil
+}
+
+func (s *URLStore) ListDomains() ([]models.Domain, error) {
+	listDomainsQuery := `SELECT name, code_length, redirect_type, fallback_url, created_at FROM domains ORDER BY name`
+	rows, err := s.db.Query(listDomainsQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	domains := make([]models.Domain, 0)
+	for rows.Next() {
+		var domain models.Domain
+		err = rows.Scan(&domain.Name, &domain.CodeLength, &domain.RedirectType, &domain.FallbackUrl, &domain.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		domains = append(domains, domain)
+	}
+	return domains, rows.Err()
+}
+
+// DeleteDomain unregisters the domain, its urls are kept
+func (s *URLStore) DeleteDomain(name string) error {
+	deleteDomainQuery := `DELETE FROM domains WHERE name = ?`
+	result, err := s.db.Exec(deleteDomainQuery, name)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New(ErrDomainDoesNotExist)
+	}
+	return nil
+}
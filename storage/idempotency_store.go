@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"URL_SHORTENER/models"
+)
+
+var ErrIdempotencyKeyDoesNotExist = "The specified idempotency key does not exist."
+
+type IdempotencyOperations interface {
+	ReserveIdempotencyKey(key *models.IdempotencyKey, expiredBefore time.Time, abandonedBefore time.Time) (*models.IdempotencyKey, error)
+	SaveIdempotentResponse(key *models.IdempotencyKey) error
+	ReleaseIdempotencyKey(key *models.IdempotencyKey) error
+}
+
+// ReserveIdempotencyKey stores the key for a request about to be handled and returns nil.
+// When the key is already taken the stored key is returned instead, and the request must not be handled again.
+// Keys created before expiredBefore are removed first, so they can be used again, and so are reservations
+// made before abandonedBefore that never got a response, like those of a crashed process.
+func (s *URLStore) ReserveIdempotencyKey(key *models.IdempotencyKey, expiredBefore time.Time, abandonedBefore time.Time) (*models.IdempotencyKey, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	deleteExpiredQuery := `DELETE FROM idempotency_keys WHERE created_at < ? OR (status = 0 AND created_at < ?)`
+	if _, err = tx.Exec(deleteExpiredQuery, expiredBefore.UnixMilli(), abandonedBefore.UnixMilli()); err != nil {
+		return nil, err
+	}
+	reserveKeyQuery := `INSERT INTO idempotency_keys (idempotency_key, request_hash, created_at) VALUES (?, ?, ?)
+		ON CONFLICT (idempotency_key) DO NOTHING`
+	result, err := tx.Exec(reserveKeyQuery, key.Key, key.RequestHash, key.CreatedAt.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	if rowsAffected, err := result.RowsAffected(); err != nil {
+		return nil, err
+	} else if rowsAffected == 1 {
+		return nil, tx.Commit()
+	}
+
+	existing := models.IdempotencyKey{Key: key.Key}
+	var createdAt int64
+	var headers string
+	getKeyQuery := `SELECT request_hash, status, headers, response, created_at FROM idempotency_keys WHERE idempotency_key = ?`
+	err = tx.QueryRow(getKeyQuery, key.Key).Scan(&existing.RequestHash, &existing.Status, &headers, &existing.Response, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal([]byte(headers), &existing.Headers); err != nil {
+		return nil, err
+	}
+	existing.CreatedAt = time.UnixMilli(createdAt).UTC()
+	return &existing, tx.Commit()
+}
+
+// SaveIdempotentResponse stores the status, headers and response of the request the key was reserved for.
+// The reservation is matched by its creation time, a reservation that was given up and taken again isn't changed.
+func (s *URLStore) SaveIdempotentResponse(key *models.IdempotencyKey) error {
+	headers, err := json.Marshal(key.Headers)
+	if err != nil {
+		return err
+	}
+	saveResponseQuery := `UPDATE idempotency_keys SET status = ?, headers = ?, response = ?
+		WHERE idempotency_key = ? AND created_at = ? AND status = 0`
+	result, err := s.db.Exec(saveResponseQuery, key.Status, string(headers), key.Response, key.Key, key.CreatedAt.UnixMilli())
+	if err != nil {
+		return err
+	}
+	return checkIdempotencyKeyAffected(result)
+}
+
+// ReleaseIdempotencyKey removes a reservation, so the request can be retried with its key
+func (s *URLStore) ReleaseIdempotencyKey(key *models.IdempotencyKey) error {
+	releaseKeyQuery := `DELETE FROM idempotency_keys WHERE idempotency_key = ? AND created_at = ? AND status = 0`
+	result, err := s.db.Exec(releaseKeyQuery, key.Key, key.CreatedAt.UnixMilli())
+	if err != nil {
+		return err
+	}
+	return checkIdempotencyKeyAffected(result)
+}
+
+func checkIdempotencyKeyAffected(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New(ErrIdempotencyKeyDoesNotExist)
+	}
+	return nil
+}
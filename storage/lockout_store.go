@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// LockoutOperations count the passwords given for a link. They are kept in the database,
+// so every process serving the links shares them.
+type LockoutOperations interface {
+	CountPasswordAttempt(domain string, shortUrl string, maxAttempts int, now time.Time, lockout time.Duration) (time.Time, error)
+	ResetPasswordLockout(domain string, shortUrl string) error
+}
+
+// CountPasswordAttempt counts a password given for the link before it is compared, and returns until when
+// the link is locked if the attempt must be refused, the zero time otherwise. The maxAttempts-th attempt in
+// a row locks the link for the lockout duration, unless ResetPasswordLockout is called once it turned out right.
+// The count and the check are one statement, so concurrent guesses can't all get in before the link is locked.
+func (s *URLStore) CountPasswordAttempt(domain string, shortUrl string, maxAttempts int, now time.Time, lockout time.Duration) (time.Time, error) {
+	lockedUntil := now.Add(lockout).UnixMilli()
+	firstLock := int64(0)
+	if maxAttempts <= 1 {
+		firstLock = lockedUntil
+	}
+	// Once a lock expired the count starts over
+	countAttemptQuery := `INSERT INTO password_lockouts (domain, short_url, failed_attempts, locked_until) VALUES (?, ?, 1, ?)
+		ON CONFLICT (domain, short_url) DO UPDATE SET
+			failed_attempts = CASE WHEN locked_until BETWEEN 1 AND ? THEN 1 ELSE failed_attempts + 1 END,
+			locked_until = CASE WHEN locked_until BETWEEN 1 AND ? THEN ?
+				WHEN locked_until = 0 AND failed_attempts + 1 >= ? THEN ?
+				ELSE locked_until END
+		RETURNING failed_attempts, locked_until`
+	var attempts int
+	var lockedUntilMs int64
+	err := s.db.QueryRow(countAttemptQuery, domain, shortUrl, firstLock, now.UnixMilli(), now.UnixMilli(), firstLock,
+		maxAttempts, lockedUntil).Scan(&attempts, &lockedUntilMs)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if attempts <= maxAttempts {
+		return time.Time{}, nil
+	}
+	return time.UnixMilli(lockedUntilMs).UTC(), nil
+}
+
+// ResetPasswordLockout forgets the passwords given for the link
+func (s *URLStore) ResetPasswordLockout(domain string, shortUrl string) error {
+	_, err := s.db.Exec(`DELETE FROM password_lockouts WHERE domain = ? AND short_url = ?`, domain, shortUrl)
+	return err
+}
+
+// deleteLockout forgets the passwords given for a short url, so a link reusing it starts over
+func deleteLockout(tx *sql.Tx, domain string, shortUrl string) error {
+	_, err := tx.Exec(`DELETE FROM password_lockouts WHERE domain = ? AND short_url = ?`, domain, shortUrl)
+	return err
+}
+
+// moveLockout hands the passwords given for a link over to its new short url
+func moveLockout(tx *sql.Tx, domain string, shortUrl string, updatedShortUrl string) error {
+	if updatedShortUrl == shortUrl {
+		return nil
+	}
+	if err := deleteLockout(tx, domain, updatedShortUrl); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`UPDATE password_lockouts SET short_url = ? WHERE domain = ? AND short_url = ?`, updatedShortUrl, domain, shortUrl)
+	return err
+}
@@ -0,0 +1,337 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// migrations holds the schema changes in the order they are applied.
+// The index of the last applied migration plus one is kept in PRAGMA user_version,
+// so new migrations must only ever be appended.
+var migrations = []string{
+	// 1: Create the urls table
+	`CREATE TABLE IF NOT EXISTS "urls" (
+		original_url TEXT PRIMARY KEY NOT NULL,
+		short_url TEXT NOT NULL,
+		created_at TEXT NOT NULL
+	);`,
+	// 2: Replace the old non-unique index with a unique one on the short_url
+	`DROP INDEX IF EXISTS idx_short;
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_urls_short_url ON urls (short_url);`,
+	// 3: Optional bcrypt hash of the password protecting the link
+	`ALTER TABLE urls ADD COLUMN password_hash TEXT NOT NULL DEFAULT '';`,
+	// 4: Optional click limit, NULL means unlimited
+	`ALTER TABLE urls ADD COLUMN max_clicks INTEGER;
+	ALTER TABLE urls ADD COLUMN remaining_clicks INTEGER;`,
+	// 5: Namespace urls by domain. SQLite can't change a primary key in place, so the table is rebuilt.
+	`CREATE TABLE urls_new (
+		domain TEXT NOT NULL DEFAULT '',
+		original_url TEXT NOT NULL,
+		short_url TEXT NOT NULL,
+		created_at TEXT NOT NULL,
+		password_hash TEXT NOT NULL DEFAULT '',
+		max_clicks INTEGER,
+		remaining_clicks INTEGER,
+		PRIMARY KEY (domain, original_url)
+	);
+	INSERT INTO urls_new (original_url, short_url, created_at, password_hash, max_clicks, remaining_clicks)
+		SELECT original_url, short_url, created_at, password_hash, max_clicks, remaining_clicks FROM urls;
+	DROP TABLE urls;
+	ALTER TABLE urls_new RENAME TO urls;
+	CREATE UNIQUE INDEX idx_urls_domain_short_url ON urls (domain, short_url);
+	CREATE TABLE domains (
+		name TEXT PRIMARY KEY NOT NULL,
+		code_length INTEGER NOT NULL,
+		redirect_type INTEGER NOT NULL,
+		fallback_url TEXT NOT NULL DEFAULT '',
+		created_at TEXT NOT NULL
+	);`,
+	// 6: Descriptive details and tags of a url
+	`ALTER TABLE urls ADD COLUMN title TEXT NOT NULL DEFAULT '';
+	ALTER TABLE urls ADD COLUMN description TEXT NOT NULL DEFAULT '';
+	ALTER TABLE urls ADD COLUMN metadata TEXT NOT NULL DEFAULT '{}';
+	CREATE TABLE tags (
+		id INTEGER PRIMARY KEY,
+		name TEXT NOT NULL UNIQUE
+	);
+	CREATE TABLE url_tags (
+		domain TEXT NOT NULL,
+		original_url TEXT NOT NULL,
+		tag_id INTEGER NOT NULL,
+		PRIMARY KEY (domain, original_url, tag_id)
+	);
+	CREATE INDEX idx_url_tags_tag_id ON url_tags (tag_id);`,
+	// 7: Metadata fetched from the destination page, page_fetched_at stays empty until it was fetched
+	`ALTER TABLE urls ADD COLUMN page_title TEXT NOT NULL DEFAULT '';
+	ALTER TABLE urls ADD COLUMN page_description TEXT NOT NULL DEFAULT '';
+	ALTER TABLE urls ADD COLUMN page_image TEXT NOT NULL DEFAULT '';
+	ALTER TABLE urls ADD COLUMN page_site_name TEXT NOT NULL DEFAULT '';
+	ALTER TABLE urls ADD COLUMN favicon_url TEXT NOT NULL DEFAULT '';
+	ALTER TABLE urls ADD COLUMN page_fetched_at TEXT NOT NULL DEFAULT '';
+	ALTER TABLE urls ADD COLUMN page_fetch_error TEXT NOT NULL DEFAULT '';`,
+	// 8: Number of times the url was followed
+	`ALTER TABLE urls ADD COLUMN click_count INTEGER NOT NULL DEFAULT 0;`,
+	// 9: Campaign tags merged into the destination and the query string forwarding mode
+	`ALTER TABLE urls ADD COLUMN utm_source TEXT NOT NULL DEFAULT '';
+	ALTER TABLE urls ADD COLUMN utm_medium TEXT NOT NULL DEFAULT '';
+	ALTER TABLE urls ADD COLUMN utm_campaign TEXT NOT NULL DEFAULT '';
+	ALTER TABLE urls ADD COLUMN utm_term TEXT NOT NULL DEFAULT '';
+	ALTER TABLE urls ADD COLUMN utm_content TEXT NOT NULL DEFAULT '';
+	ALTER TABLE urls ADD COLUMN forward_query INTEGER NOT NULL DEFAULT 0;`,
+	// 10: Ordered targeting rules, a JSON array
+	`ALTER TABLE urls ADD COLUMN rules TEXT NOT NULL DEFAULT '[]';`,
+	// 11: Weighted destinations and the clicks each of them served
+	`ALTER TABLE urls ADD COLUMN variant_mode TEXT NOT NULL DEFAULT '';
+	CREATE TABLE url_variants (
+		domain TEXT NOT NULL,
+		original_url TEXT NOT NULL,
+		name TEXT NOT NULL,
+		destination TEXT NOT NULL,
+		weight INTEGER NOT NULL,
+		position INTEGER NOT NULL,
+		click_count INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (domain, original_url, name)
+	);`,
+	// 12: Why a url was blocked, empty while it isn't
+	`ALTER TABLE urls ADD COLUMN blocked_reason TEXT NOT NULL DEFAULT '';`,
+	// 13: Status of a url, admins disable urls and the blocklist blocks them. Blocked urls keep their reason.
+	`ALTER TABLE urls RENAME COLUMN blocked_reason TO status_reason;
+	ALTER TABLE urls ADD COLUMN status TEXT NOT NULL DEFAULT 'active';
+	UPDATE urls SET status = 'blocked' WHERE status_reason != '';`,
+	// 14: Abuse reports queued for review
+	`CREATE TABLE abuse_reports (
+		id INTEGER PRIMARY KEY,
+		domain TEXT NOT NULL,
+		short_url TEXT NOT NULL,
+		reason TEXT NOT NULL,
+		details TEXT NOT NULL DEFAULT '',
+		email TEXT NOT NULL DEFAULT '',
+		created_at TEXT NOT NULL,
+		reviewed_at TEXT NOT NULL DEFAULT ''
+	);
+	CREATE INDEX idx_abuse_reports_reviewed_at ON abuse_reports (reviewed_at);`,
+	// 15: Webhook subscriptions, their deliveries and the deliveries that failed every attempt
+	`CREATE TABLE webhooks (
+		id INTEGER PRIMARY KEY,
+		url TEXT NOT NULL,
+		events TEXT NOT NULL,
+		secret TEXT NOT NULL,
+		click_thresholds TEXT NOT NULL DEFAULT '[]',
+		created_at TEXT NOT NULL
+	);
+	CREATE TABLE webhook_deliveries (
+		id INTEGER PRIMARY KEY,
+		webhook_id INTEGER NOT NULL,
+		event TEXT NOT NULL,
+		payload TEXT NOT NULL,
+		status TEXT NOT NULL,
+		attempts INTEGER NOT NULL DEFAULT 0,
+		response_status INTEGER NOT NULL DEFAULT 0,
+		last_error TEXT NOT NULL DEFAULT '',
+		created_at TEXT NOT NULL,
+		delivered_at TEXT NOT NULL DEFAULT '',
+		next_attempt_at INTEGER NOT NULL
+	);
+	CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries (status, next_attempt_at);
+	CREATE INDEX idx_webhook_deliveries_webhook_id ON webhook_deliveries (webhook_id, id);
+	CREATE TABLE webhook_dead_letters (
+		id INTEGER PRIMARY KEY,
+		delivery_id INTEGER NOT NULL UNIQUE,
+		webhook_id INTEGER NOT NULL,
+		event TEXT NOT NULL,
+		payload TEXT NOT NULL,
+		attempts INTEGER NOT NULL,
+		last_error TEXT NOT NULL,
+		failed_at TEXT NOT NULL
+	);`,
+	// 16: Outcome of the last health check of the destination, health_checked_at stays empty until it was checked
+	`ALTER TABLE urls ADD COLUMN health_status TEXT NOT NULL DEFAULT '';
+	ALTER TABLE urls ADD COLUMN health_status_code INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE urls ADD COLUMN health_latency_ms INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE urls ADD COLUMN health_error TEXT NOT NULL DEFAULT '';
+	ALTER TABLE urls ADD COLUMN health_checked_at TEXT NOT NULL DEFAULT '';
+	CREATE INDEX idx_urls_domain_health_status ON urls (domain, health_status);`,
+	// 17: created_at was the local time without a zone, convert it to UTC in RFC 3339 so it sorts across
+	// timezones and DST changes. The 'utc' modifier reads the old value in the local time of the process.
+	`UPDATE urls SET created_at = strftime('%Y-%m-%dT%H:%M:%SZ', created_at, 'utc') WHERE created_at NOT LIKE '%Z';`,
+	// 18: Counts the changes of the settings of a url, the ETag of its responses
+	`ALTER TABLE urls ADD COLUMN version INTEGER NOT NULL DEFAULT 1;`,
+	// 19: Responses of create requests sent with an Idempotency-Key, replayed when the request is retried
+	`CREATE TABLE idempotency_keys (
+		idempotency_key TEXT PRIMARY KEY,
+		request_hash TEXT NOT NULL,
+		status INTEGER NOT NULL DEFAULT 0,
+		response TEXT NOT NULL DEFAULT '',
+		created_at INTEGER NOT NULL
+	);
+	CREATE INDEX idx_idempotency_keys_created_at ON idempotency_keys (created_at);`,
+	// 20: Wrong passwords given for a link and until when it is locked, shared by every process
+	`CREATE TABLE password_lockouts (
+		domain TEXT NOT NULL,
+		short_url TEXT NOT NULL,
+		failed_attempts INTEGER NOT NULL DEFAULT 0,
+		locked_until INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (domain, short_url)
+	);`,
+	// 21: Headers of the idempotent responses, replayed with the body
+	`ALTER TABLE idempotency_keys ADD COLUMN headers TEXT NOT NULL DEFAULT '{}';`,
+	// 22: The other times were local without a zone too, convert them to UTC in RFC 3339 like migration 17.
+	// The times of targeting rules are converted inside their JSON.
+	`UPDATE urls SET page_fetched_at = strftime('%Y-%m-%dT%H:%M:%SZ', page_fetched_at, 'utc') WHERE page_fetched_at != '' AND page_fetched_at NOT LIKE '%Z';
+	UPDATE urls SET health_checked_at = strftime('%Y-%m-%dT%H:%M:%SZ', health_checked_at, 'utc') WHERE health_checked_at != '' AND health_checked_at NOT LIKE '%Z';
+	UPDATE domains SET created_at = strftime('%Y-%m-%dT%H:%M:%SZ', created_at, 'utc') WHERE created_at NOT LIKE '%Z';
+	UPDATE abuse_reports SET created_at = strftime('%Y-%m-%dT%H:%M:%SZ', created_at, 'utc') WHERE created_at NOT LIKE '%Z';
+	UPDATE abuse_reports SET reviewed_at = strftime('%Y-%m-%dT%H:%M:%SZ', reviewed_at, 'utc') WHERE reviewed_at != '' AND reviewed_at NOT LIKE '%Z';
+	UPDATE webhooks SET created_at = strftime('%Y-%m-%dT%H:%M:%SZ', created_at, 'utc') WHERE created_at NOT LIKE '%Z';
+	UPDATE webhook_deliveries SET created_at = strftime('%Y-%m-%dT%H:%M:%SZ', created_at, 'utc') WHERE created_at NOT LIKE '%Z';
+	UPDATE webhook_deliveries SET delivered_at = strftime('%Y-%m-%dT%H:%M:%SZ', delivered_at, 'utc') WHERE delivered_at != '' AND delivered_at NOT LIKE '%Z';
+	UPDATE webhook_dead_letters SET failed_at = strftime('%Y-%m-%dT%H:%M:%SZ', failed_at, 'utc') WHERE failed_at NOT LIKE '%Z';
+	UPDATE urls SET rules = (SELECT json_group_array(json(CASE WHEN json_extract(value, '$.starts_at') IS NULL OR json_extract(value, '$.starts_at') = '' OR json_extract(value, '$.starts_at') LIKE '%Z' THEN value
+		ELSE json_set(value, '$.starts_at', strftime('%Y-%m-%dT%H:%M:%SZ', json_extract(value, '$.starts_at'), 'utc')) END)) FROM json_each(urls.rules))
+	WHERE rules LIKE '%"starts_at"%';
+	UPDATE urls SET rules = (SELECT json_group_array(json(CASE WHEN json_extract(value, '$.ends_at') IS NULL OR json_extract(value, '$.ends_at') = '' OR json_extract(value, '$.ends_at') LIKE '%Z' THEN value
+		ELSE json_set(value, '$.ends_at', strftime('%Y-%m-%dT%H:%M:%SZ', json_extract(value, '$.ends_at'), 'utc')) END)) FROM json_each(urls.rules))
+	WHERE rules LIKE '%"ends_at"%';`,
+	// 23: Lockouts outlived the links that were deleted or renamed, so a link reusing the short url inherited them
+	`DELETE FROM password_lockouts WHERE NOT EXISTS (SELECT 1 FROM urls
+		WHERE urls.domain = password_lockouts.domain AND urls.short_url = password_lockouts.short_url);`,
+}
+
+// migrationChecks run before the migration of the same number. They stop the upgrade with an error
+// when the existing rows can't be migrated without losing data, so an admin can fix them first.
+var migrationChecks = map[int]func(tx *sql.Tx) error{
+	2:  checkDuplicateShortUrls,
+	17: func(tx *sql.Tx) error { return checkLocalTimes(tx, urlCreationTimes) },
+	22: func(tx *sql.Tx) error { return checkLocalTimes(tx, localTimes) },
+}
+
+// maxReportedConflicts bounds how many conflicting rows a failed migration names
+const maxReportedConflicts = 10
+
+// checkDuplicateShortUrls fails if several urls share a short url, the unique index of migration 2 can't be built then
+func checkDuplicateShortUrls(tx *sql.Tx) error {
+	duplicatesQuery := `SELECT short_url, group_concat(original_url, ', ') FROM urls
+		GROUP BY short_url HAVING count(*) > 1 ORDER BY short_url LIMIT ?`
+	rows, err := tx.Query(duplicatesQuery, maxReportedConflicts)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var conflicts []string
+	for rows.Next() {
+		var shortUrl, originalUrls string
+		if err = rows.Scan(&shortUrl, &originalUrls); err != nil {
+			return err
+		}
+		conflicts = append(conflicts, fmt.Sprintf("%s is the short url of %s", shortUrl, originalUrls))
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("short urls must be unique, change or delete the duplicates first: %s", strings.Join(conflicts, "; "))
+	}
+	return nil
+}
+
+// timeColumn is a column holding local times without a zone. Optional columns are empty until they are set.
+type timeColumn struct {
+	table    string
+	column   string
+	row      string
+	optional bool
+}
+
+// urlCreationTimes are converted by migration 17
+var urlCreationTimes = []timeColumn{
+	{table: "urls", column: "created_at", row: "domain || '/' || short_url"},
+}
+
+// localTimes are converted by migration 22
+var localTimes = []timeColumn{
+	{table: "urls", column: "page_fetched_at", row: "domain || '/' || short_url", optional: true},
+	{table: "urls", column: "health_checked_at", row: "domain || '/' || short_url", optional: true},
+	{table: "urls, json_each(urls.rules)", column: "json_extract(value, '$.starts_at')", row: "domain || '/' || short_url", optional: true},
+	{table: "urls, json_each(urls.rules)", column: "json_extract(value, '$.ends_at')", row: "domain || '/' || short_url", optional: true},
+	{table: "domains", column: "created_at", row: "name"},
+	{table: "abuse_reports", column: "created_at", row: "'report ' || id"},
+	{table: "abuse_reports", column: "reviewed_at", row: "'report ' || id", optional: true},
+	{table: "webhooks", column: "created_at", row: "'webhook ' || id"},
+	{table: "webhook_deliveries", column: "created_at", row: "'delivery ' || id"},
+	{table: "webhook_deliveries", column: "delivered_at", row: "'delivery ' || id", optional: true},
+	{table: "webhook_dead_letters", column: "failed_at", row: "'dead letter ' || id"},
+}
+
+// checkLocalTimes fails if a time of the columns can't be read by strftime. The migration would write
+// NULL for it otherwise, which the NOT NULL columns refuse with an error that doesn't name the row.
+func checkLocalTimes(tx *sql.Tx, columns []timeColumn) error {
+	var invalid []string
+	for _, column := range columns {
+		condition := column.column + ` NOT LIKE '%Z' AND strftime('%s', ` + column.column + `) IS NULL`
+		if column.optional {
+			condition = column.column + ` != '' AND ` + condition
+		}
+		invalidQuery := `SELECT ` + column.row + `, ` + column.column + ` FROM ` + column.table + ` WHERE ` + condition + ` LIMIT ?`
+		rows, err := tx.Query(invalidQuery, maxReportedConflicts-len(invalid))
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var row, value string
+			if err = rows.Scan(&row, &value); err != nil {
+				rows.Close()
+				return err
+			}
+			invalid = append(invalid, fmt.Sprintf("%s of %s is %q", column.column, row, value))
+		}
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return err
+		}
+		if len(invalid) >= maxReportedConflicts {
+			break
+		}
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("times must be formatted like 2006-01-02 15:04:05, fix or clear them first: %s", strings.Join(invalid, "; "))
+	}
+	return nil
+}
+
+// SchemaVersion is the schema version of a fully migrated database
+func SchemaVersion() int {
+	return len(migrations)
+}
+
+// migrate applies every migration newer than the database's schema version
+func migrate(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var version int
+	if err = tx.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		return err
+	}
+	for ; version < len(migrations); version++ {
+		if check, ok := migrationChecks[version+1]; ok {
+			if err = check(tx); err != nil {
+				return fmt.Errorf("Failed to apply migration %d: %s", version+1, err.Error())
+			}
+		}
+		if _, err = tx.Exec(migrations[version]); err != nil {
+			return fmt.Errorf("Failed to apply migration %d: %s", version+1, err.Error())
+		}
+	}
+	// PRAGMA does not accept bound parameters
+	if _, err = tx.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, version)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
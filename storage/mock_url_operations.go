@@ -7,6 +7,7 @@ package storage
 import (
 	models "URL_SHORTENER/models"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -34,61 +35,234 @@ func (m *MockURLOperations) EXPECT() *MockURLOperationsMockRecorder {
 	return m.recorder
 }
 
+// Backup mocks base method.
+func (m *MockURLOperations) Backup(arg0 string, arg1 int) (*models.Backup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Backup", arg0, arg1)
+	ret0, _ := ret[0].(*models.Backup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Backup indicates an expected call of Backup.
+func (mr *MockURLOperationsMockRecorder) Backup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Backup", reflect.TypeOf((*MockURLOperations)(nil).Backup), arg0, arg1)
+}
+
 // CheckOriginalUrlExists mocks base method.
-func (m *MockURLOperations) CheckOriginalUrlExists(arg0 string) bool {
+func (m *MockURLOperations) CheckOriginalUrlExists(arg0, arg1 string) bool {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CheckOriginalUrlExists", arg0)
+	ret := m.ctrl.Call(m, "CheckOriginalUrlExists", arg0, arg1)
 	ret0, _ := ret[0].(bool)
 	return ret0
 }
 
 // CheckOriginalUrlExists indicates an expected call of CheckOriginalUrlExists.
-func (mr *MockURLOperationsMockRecorder) CheckOriginalUrlExists(arg0 interface{}) *gomock.Call {
+func (mr *MockURLOperationsMockRecorder) CheckOriginalUrlExists(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckOriginalUrlExists", reflect.TypeOf((*MockURLOperations)(nil).CheckOriginalUrlExists), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckOriginalUrlExists", reflect.TypeOf((*MockURLOperations)(nil).CheckOriginalUrlExists), arg0, arg1)
 }
 
 // CheckShortUrlExists mocks base method.
-func (m *MockURLOperations) CheckShortUrlExists(arg0 string) bool {
+func (m *MockURLOperations) CheckShortUrlExists(arg0, arg1 string) bool {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CheckShortUrlExists", arg0)
+	ret := m.ctrl.Call(m, "CheckShortUrlExists", arg0, arg1)
 	ret0, _ := ret[0].(bool)
 	return ret0
 }
 
 // CheckShortUrlExists indicates an expected call of CheckShortUrlExists.
-func (mr *MockURLOperationsMockRecorder) CheckShortUrlExists(arg0 interface{}) *gomock.Call {
+func (mr *MockURLOperationsMockRecorder) CheckShortUrlExists(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckShortUrlExists", reflect.TypeOf((*MockURLOperations)(nil).CheckShortUrlExists), arg0, arg1)
+}
+
+// ConsumeClick mocks base method.
+func (m *MockURLOperations) ConsumeClick(arg0, arg1 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeClick", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeClick indicates an expected call of ConsumeClick.
+func (mr *MockURLOperationsMockRecorder) ConsumeClick(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeClick", reflect.TypeOf((*MockURLOperations)(nil).ConsumeClick), arg0, arg1)
+}
+
+// CountPasswordAttempt mocks base method.
+func (m *MockURLOperations) CountPasswordAttempt(arg0, arg1 string, arg2 int, arg3 time.Time, arg4 time.Duration) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountPasswordAttempt", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountPasswordAttempt indicates an expected call of CountPasswordAttempt.
+func (mr *MockURLOperationsMockRecorder) CountPasswordAttempt(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountPasswordAttempt", reflect.TypeOf((*MockURLOperations)(nil).CountPasswordAttempt), arg0, arg1, arg2, arg3, arg4)
+}
+
+// DeleteDomain mocks base method.
+func (m *MockURLOperations) DeleteDomain(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDomain", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDomain indicates an expected call of DeleteDomain.
+func (mr *MockURLOperationsMockRecorder) DeleteDomain(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckShortUrlExists", reflect.TypeOf((*MockURLOperations)(nil).CheckShortUrlExists), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDomain", reflect.TypeOf((*MockURLOperations)(nil).DeleteDomain), arg0)
 }
 
 // DeleteShortUrl mocks base method.
-func (m *MockURLOperations) DeleteShortUrl(arg0 string) error {
+func (m *MockURLOperations) DeleteShortUrl(arg0, arg1 string, arg2 int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteShortUrl", arg0)
+	ret := m.ctrl.Call(m, "DeleteShortUrl", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteShortUrl indicates an expected call of DeleteShortUrl.
-func (mr *MockURLOperationsMockRecorder) DeleteShortUrl(arg0 interface{}) *gomock.Call {
+func (mr *MockURLOperationsMockRecorder) DeleteShortUrl(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteShortUrl", reflect.TypeOf((*MockURLOperations)(nil).DeleteShortUrl), arg0, arg1, arg2)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockURLOperations) DeleteWebhook(arg0 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockURLOperationsMockRecorder) DeleteWebhook(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockURLOperations)(nil).DeleteWebhook), arg0)
+}
+
+// DueDeliveries mocks base method.
+func (m *MockURLOperations) DueDeliveries(arg0 time.Time, arg1 int) ([]models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DueDeliveries", arg0, arg1)
+	ret0, _ := ret[0].([]models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DueDeliveries indicates an expected call of DueDeliveries.
+func (mr *MockURLOperationsMockRecorder) DueDeliveries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DueDeliveries", reflect.TypeOf((*MockURLOperations)(nil).DueDeliveries), arg0, arg1)
+}
+
+// ExportUrls mocks base method.
+func (m *MockURLOperations) ExportUrls(arg0 *UrlFilter, arg1 func(*models.Url) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportUrls", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportUrls indicates an expected call of ExportUrls.
+func (mr *MockURLOperationsMockRecorder) ExportUrls(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportUrls", reflect.TypeOf((*MockURLOperations)(nil).ExportUrls), arg0, arg1)
+}
+
+// FinishDelivery mocks base method.
+func (m *MockURLOperations) FinishDelivery(arg0 *models.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishDelivery", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishDelivery indicates an expected call of FinishDelivery.
+func (mr *MockURLOperationsMockRecorder) FinishDelivery(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishDelivery", reflect.TypeOf((*MockURLOperations)(nil).FinishDelivery), arg0)
+}
+
+// GetDomain mocks base method.
+func (m *MockURLOperations) GetDomain(arg0 string) (*models.Domain, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDomain", arg0)
+	ret0, _ := ret[0].(*models.Domain)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDomain indicates an expected call of GetDomain.
+func (mr *MockURLOperationsMockRecorder) GetDomain(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteShortUrl", reflect.TypeOf((*MockURLOperations)(nil).DeleteShortUrl), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDomain", reflect.TypeOf((*MockURLOperations)(nil).GetDomain), arg0)
 }
 
 // GetOriginalUrl mocks base method.
-func (m *MockURLOperations) GetOriginalUrl(arg0 string) (*models.Url, error) {
+func (m *MockURLOperations) GetOriginalUrl(arg0, arg1 string) (*models.Url, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOriginalUrl", arg0)
+	ret := m.ctrl.Call(m, "GetOriginalUrl", arg0, arg1)
 	ret0, _ := ret[0].(*models.Url)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetOriginalUrl indicates an expected call of GetOriginalUrl.
-func (mr *MockURLOperationsMockRecorder) GetOriginalUrl(arg0 interface{}) *gomock.Call {
+func (mr *MockURLOperationsMockRecorder) GetOriginalUrl(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOriginalUrl", reflect.TypeOf((*MockURLOperations)(nil).GetOriginalUrl), arg0, arg1)
+}
+
+// InsertDeliveries mocks base method.
+func (m *MockURLOperations) InsertDeliveries(arg0 []models.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertDeliveries", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertDeliveries indicates an expected call of InsertDeliveries.
+func (mr *MockURLOperationsMockRecorder) InsertDeliveries(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertDeliveries", reflect.TypeOf((*MockURLOperations)(nil).InsertDeliveries), arg0)
+}
+
+// InsertDomain mocks base method.
+func (m *MockURLOperations) InsertDomain(arg0 *models.Domain) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertDomain", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertDomain indicates an expected call of InsertDomain.
+func (mr *MockURLOperationsMockRecorder) InsertDomain(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertDomain", reflect.TypeOf((*MockURLOperations)(nil).InsertDomain), arg0)
+}
+
+// InsertReport mocks base method.
+func (m *MockURLOperations) InsertReport(arg0 *models.Report) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertReport", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertReport indicates an expected call of InsertReport.
+func (mr *MockURLOperationsMockRecorder) InsertReport(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOriginalUrl", reflect.TypeOf((*MockURLOperations)(nil).GetOriginalUrl), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertReport", reflect.TypeOf((*MockURLOperations)(nil).InsertReport), arg0)
 }
 
 // InsertUrl mocks base method.
@@ -105,8 +279,268 @@ func (mr *MockURLOperationsMockRecorder) InsertUrl(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUrl", reflect.TypeOf((*MockURLOperations)(nil).InsertUrl), arg0)
 }
 
+// InsertWebhook mocks base method.
+func (m *MockURLOperations) InsertWebhook(arg0 *models.Webhook) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebhook", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertWebhook indicates an expected call of InsertWebhook.
+func (mr *MockURLOperationsMockRecorder) InsertWebhook(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebhook", reflect.TypeOf((*MockURLOperations)(nil).InsertWebhook), arg0)
+}
+
+// ListDeadLetters mocks base method.
+func (m *MockURLOperations) ListDeadLetters(arg0, arg1 int) ([]models.WebhookDeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeadLetters", arg0, arg1)
+	ret0, _ := ret[0].([]models.WebhookDeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeadLetters indicates an expected call of ListDeadLetters.
+func (mr *MockURLOperationsMockRecorder) ListDeadLetters(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeadLetters", reflect.TypeOf((*MockURLOperations)(nil).ListDeadLetters), arg0, arg1)
+}
+
+// ListDeliveries mocks base method.
+func (m *MockURLOperations) ListDeliveries(arg0 *DeliveryFilter) ([]models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeliveries", arg0)
+	ret0, _ := ret[0].([]models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeliveries indicates an expected call of ListDeliveries.
+func (mr *MockURLOperationsMockRecorder) ListDeliveries(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeliveries", reflect.TypeOf((*MockURLOperations)(nil).ListDeliveries), arg0)
+}
+
+// ListDomains mocks base method.
+func (m *MockURLOperations) ListDomains() ([]models.Domain, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDomains")
+	ret0, _ := ret[0].([]models.Domain)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDomains indicates an expected call of ListDomains.
+func (mr *MockURLOperationsMockRecorder) ListDomains() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDomains", reflect.TypeOf((*MockURLOperations)(nil).ListDomains))
+}
+
+// ListReports mocks base method.
+func (m *MockURLOperations) ListReports(arg0 *ReportFilter) ([]models.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReports", arg0)
+	ret0, _ := ret[0].([]models.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReports indicates an expected call of ListReports.
+func (mr *MockURLOperationsMockRecorder) ListReports(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReports", reflect.TypeOf((*MockURLOperations)(nil).ListReports), arg0)
+}
+
+// ListUrls mocks base method.
+func (m *MockURLOperations) ListUrls(arg0 *UrlFilter) ([]models.Url, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUrls", arg0)
+	ret0, _ := ret[0].([]models.Url)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUrls indicates an expected call of ListUrls.
+func (mr *MockURLOperationsMockRecorder) ListUrls(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUrls", reflect.TypeOf((*MockURLOperations)(nil).ListUrls), arg0)
+}
+
+// ListWebhooks mocks base method.
+func (m *MockURLOperations) ListWebhooks() ([]models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooks")
+	ret0, _ := ret[0].([]models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooks indicates an expected call of ListWebhooks.
+func (mr *MockURLOperationsMockRecorder) ListWebhooks() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockURLOperations)(nil).ListWebhooks))
+}
+
+// RecordClick mocks base method.
+func (m *MockURLOperations) RecordClick(arg0, arg1, arg2 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordClick", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordClick indicates an expected call of RecordClick.
+func (mr *MockURLOperationsMockRecorder) RecordClick(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordClick", reflect.TypeOf((*MockURLOperations)(nil).RecordClick), arg0, arg1, arg2)
+}
+
+// ReleaseIdempotencyKey mocks base method.
+func (m *MockURLOperations) ReleaseIdempotencyKey(arg0 *models.IdempotencyKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseIdempotencyKey", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseIdempotencyKey indicates an expected call of ReleaseIdempotencyKey.
+func (mr *MockURLOperationsMockRecorder) ReleaseIdempotencyKey(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseIdempotencyKey", reflect.TypeOf((*MockURLOperations)(nil).ReleaseIdempotencyKey), arg0)
+}
+
+// ReplaceUrl mocks base method.
+func (m *MockURLOperations) ReplaceUrl(arg0 *models.Url) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceUrl", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceUrl indicates an expected call of ReplaceUrl.
+func (mr *MockURLOperationsMockRecorder) ReplaceUrl(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceUrl", reflect.TypeOf((*MockURLOperations)(nil).ReplaceUrl), arg0)
+}
+
+// ReserveIdempotencyKey mocks base method.
+func (m *MockURLOperations) ReserveIdempotencyKey(arg0 *models.IdempotencyKey, arg1, arg2 time.Time) (*models.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveIdempotencyKey", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveIdempotencyKey indicates an expected call of ReserveIdempotencyKey.
+func (mr *MockURLOperationsMockRecorder) ReserveIdempotencyKey(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveIdempotencyKey", reflect.TypeOf((*MockURLOperations)(nil).ReserveIdempotencyKey), arg0, arg1, arg2)
+}
+
+// ResetPasswordLockout mocks base method.
+func (m *MockURLOperations) ResetPasswordLockout(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPasswordLockout", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPasswordLockout indicates an expected call of ResetPasswordLockout.
+func (mr *MockURLOperationsMockRecorder) ResetPasswordLockout(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPasswordLockout", reflect.TypeOf((*MockURLOperations)(nil).ResetPasswordLockout), arg0, arg1)
+}
+
+// RetryDeadLetter mocks base method.
+func (m *MockURLOperations) RetryDeadLetter(arg0 int64, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryDeadLetter", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetryDeadLetter indicates an expected call of RetryDeadLetter.
+func (mr *MockURLOperationsMockRecorder) RetryDeadLetter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryDeadLetter", reflect.TypeOf((*MockURLOperations)(nil).RetryDeadLetter), arg0, arg1)
+}
+
+// ReviewReport mocks base method.
+func (m *MockURLOperations) ReviewReport(arg0 int64, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReviewReport", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReviewReport indicates an expected call of ReviewReport.
+func (mr *MockURLOperationsMockRecorder) ReviewReport(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReviewReport", reflect.TypeOf((*MockURLOperations)(nil).ReviewReport), arg0, arg1)
+}
+
+// SaveIdempotentResponse mocks base method.
+func (m *MockURLOperations) SaveIdempotentResponse(arg0 *models.IdempotencyKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveIdempotentResponse", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveIdempotentResponse indicates an expected call of SaveIdempotentResponse.
+func (mr *MockURLOperationsMockRecorder) SaveIdempotentResponse(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveIdempotentResponse", reflect.TypeOf((*MockURLOperations)(nil).SaveIdempotentResponse), arg0)
+}
+
+// SetLinkHealth mocks base method.
+func (m *MockURLOperations) SetLinkHealth(arg0, arg1 string, arg2 *models.LinkHealth) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLinkHealth", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLinkHealth indicates an expected call of SetLinkHealth.
+func (mr *MockURLOperationsMockRecorder) SetLinkHealth(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLinkHealth", reflect.TypeOf((*MockURLOperations)(nil).SetLinkHealth), arg0, arg1, arg2)
+}
+
+// SetPageMetadata mocks base method.
+func (m *MockURLOperations) SetPageMetadata(arg0, arg1 string, arg2 *models.PageMetadata) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPageMetadata", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPageMetadata indicates an expected call of SetPageMetadata.
+func (mr *MockURLOperationsMockRecorder) SetPageMetadata(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPageMetadata", reflect.TypeOf((*MockURLOperations)(nil).SetPageMetadata), arg0, arg1, arg2)
+}
+
+// SetUrlStatus mocks base method.
+func (m *MockURLOperations) SetUrlStatus(arg0, arg1, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUrlStatus", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUrlStatus indicates an expected call of SetUrlStatus.
+func (mr *MockURLOperationsMockRecorder) SetUrlStatus(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUrlStatus", reflect.TypeOf((*MockURLOperations)(nil).SetUrlStatus), arg0, arg1, arg2, arg3)
+}
+
 // UpdateShortUrl mocks base method.
-func (m *MockURLOperations) UpdateShortUrl(arg0, arg1, arg2 string) error {
+func (m *MockURLOperations) UpdateShortUrl(arg0 *models.Url, arg1 string, arg2 time.Time) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UpdateShortUrl", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
@@ -118,3 +552,17 @@ func (mr *MockURLOperationsMockRecorder) UpdateShortUrl(arg0, arg1, arg2 interfa
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateShortUrl", reflect.TypeOf((*MockURLOperations)(nil).UpdateShortUrl), arg0, arg1, arg2)
 }
+
+// UpdateUrlDetails mocks base method.
+func (m *MockURLOperations) UpdateUrlDetails(arg0 *models.Url) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUrlDetails", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUrlDetails indicates an expected call of UpdateUrlDetails.
+func (mr *MockURLOperationsMockRecorder) UpdateUrlDetails(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUrlDetails", reflect.TypeOf((*MockURLOperations)(nil).UpdateUrlDetails), arg0)
+}
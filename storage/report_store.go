@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"errors"
+
+	"URL_SHORTENER/models"
+)
+
+var ErrReportDoesNotExist = "The specified report does not exist."
+
+type ReportOperations interface {
+	InsertReport(report *models.Report) error
+	ListReports(filter *ReportFilter) ([]models.Report, error)
+	ReviewReport(id int64, reviewedAt string) error
+}
+
+// ReportFilter narrows down the reports returned by ListReports
+type ReportFilter struct {
+	// Reviewed lists the reviewed reports when true and the pending ones when false, all of them when nil
+	Reviewed *bool
+	Limit    int
+	Offset   int
+}
+
+// InsertReport queues the report and sets its id
+func (s *URLStore) InsertReport(report *models.Report) error {
+	insertReportQuery := `INSERT INTO abuse_reports (domain, short_url, reason, details, email, created_at) VALUES (?, ?, ?, ?, ?, ?)`
+	result, err := s.db.Exec(insertReportQuery, report.Domain, report.ShortUrl, report.Reason, report.Details, report.Email, report.CreatedAt)
+	if err != nil {
+		return err
+	}
+	report.Id, err = result.LastInsertId()
+	return err
+}
+
+// ListReports returns the reports oldest first, in the order they are reviewed
+func (s *URLStore) ListReports(filter *ReportFilter) ([]models.Report, error) {
+	listReportsQuery := `SELECT id, domain, short_url, reason, details, email, created_at, reviewed_at FROM abuse_reports`
+	if filter.Reviewed != nil {
+		if *filter.Reviewed {
+			listReportsQuery += ` WHERE reviewed_at != ''`
+		} else {
+			listReportsQuery += ` WHERE reviewed_at = ''`
+		}
+	}
+	listReportsQuery += ` ORDER BY id LIMIT ? OFFSET ?`
+	rows, err := s.db.Query(listReportsQuery, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := make([]models.Report, 0)
+	for rows.Next() {
+		var report models.Report
+		err = rows.Scan(&report.Id, &report.Domain, &report.ShortUrl, &report.Reason, &report.Details, &report.Email,
+			&report.CreatedAt, &report.ReviewedAt)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, rows.Err()
+}
+
+// ReviewReport marks the report as reviewed, it leaves the queue
+func (s *URLStore) ReviewReport(id int64, reviewedAt string) error {
+	reviewReportQuery := `UPDATE abuse_reports SET reviewed_at = ? WHERE id = ?`
+	result, err := s.db.Exec(reviewReportQuery, reviewedAt, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New(ErrReportDoesNotExist)
+	}
+	return nil
+}
@@ -1,8 +1,18 @@
 package storage
 
 import (
+	"database/sql"
+	"fmt"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"URL_SHORTENER/models"
 
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/require"
@@ -28,16 +38,1092 @@ func TestNewURLStore(t *testing.T) {
 	})
 
 	t.Run("Custom DB_PATH", func(t *testing.T) {
-		dbPath := "database.sqlite3"
+		// WAL mode leaves -wal and -shm files next to the database, keep them out of the tree
+		dbPath := filepath.Join(t.TempDir(), "database.sqlite3")
 		_ = os.Setenv("DB_PATH", dbPath)
 		urlStore, err := NewURLStore()
 		require.Nil(t, err)
 		require.NotNil(t, urlStore)
 		require.NotNil(t, urlStore.db)
+		urlStore.Close()
+	})
+
+}
+
+func newTestStore(t *testing.T, dbPath string) *URLStore {
+	t.Helper()
+	_ = os.Setenv("DB_PATH", dbPath)
+	urlStore, err := NewURLStore()
+	require.NoError(t, err)
+	t.Cleanup(urlStore.Close)
+	return urlStore
+}
+
+// testTime parses a time formatted like 2024-10-16 23:05:18 as UTC
+func testTime(value string) time.Time {
+	parsed, err := time.Parse("2006-01-02 15:04:05", value)
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+func TestInsertUrl(t *testing.T) {
+
+	t.Run("Original URL already shortened", func(t *testing.T) {
+		urlStore := newTestStore(t, ":memory:")
+		err := urlStore.InsertUrl(&models.Url{OriginalUrl: "http://example.com", ShortUrl: "esd87df7", CreatedAt: testTime("2024-10-16 23:05:18")})
+		require.NoError(t, err)
+
+		err = urlStore.InsertUrl(&models.Url{OriginalUrl: "http://example.com", ShortUrl: "abcd1234", CreatedAt: testTime("2024-10-16 23:05:18")})
+		require.EqualError(t, err, ErrURLAlreadyShortened)
+	})
+
+	t.Run("Short URL already in use", func(t *testing.T) {
+		urlStore := newTestStore(t, ":memory:")
+		err := urlStore.InsertUrl(&models.Url{OriginalUrl: "http://example.com", ShortUrl: "esd87df7", CreatedAt: testTime("2024-10-16 23:05:18")})
+		require.NoError(t, err)
 
-		defer func() {
-			_ = os.Remove(dbPath)
+		err = urlStore.InsertUrl(&models.Url{OriginalUrl: "http://other.com", ShortUrl: "esd87df7", CreatedAt: testTime("2024-10-16 23:05:18")})
+		require.EqualError(t, err, ErrShortURLAlreadyExists)
+	})
+}
+
+func TestUpdateShortUrl(t *testing.T) {
+
+	t.Run("Short URL does not exist", func(t *testing.T) {
+		urlStore := newTestStore(t, ":memory:")
+		err := urlStore.UpdateShortUrl(&models.Url{ShortUrl: "esd87df7"}, "abcd1234", testTime("2024-10-16 23:05:18"))
+		require.EqualError(t, err, ErrShortURLDoesNotExist)
+	})
+
+	t.Run("Updated short URL already in use", func(t *testing.T) {
+		urlStore := newTestStore(t, ":memory:")
+		require.NoError(t, urlStore.InsertUrl(&models.Url{OriginalUrl: "http://example.com", ShortUrl: "esd87df7", CreatedAt: testTime("2024-10-16 23:05:18")}))
+		require.NoError(t, urlStore.InsertUrl(&models.Url{OriginalUrl: "http://other.com", ShortUrl: "abcd1234", CreatedAt: testTime("2024-10-16 23:05:18")}))
+
+		err := urlStore.UpdateShortUrl(&models.Url{ShortUrl: "esd87df7"}, "abcd1234", testTime("2024-10-16 23:05:18"))
+		require.EqualError(t, err, ErrShortURLAlreadyExists)
+	})
+
+	t.Run("Successful update", func(t *testing.T) {
+		urlStore := newTestStore(t, ":memory:")
+		require.NoError(t, urlStore.InsertUrl(&models.Url{OriginalUrl: "http://example.com", ShortUrl: "esd87df7", CreatedAt: testTime("2024-10-16 23:05:18")}))
+
+		url, err := urlStore.GetOriginalUrl("", "esd87df7")
+		require.NoError(t, err)
+		url.Title = "Renamed"
+		require.NoError(t, urlStore.UpdateShortUrl(url, "abcd1234", testTime("2024-10-17 10:00:00")))
+		require.False(t, urlStore.CheckShortUrlExists("", "esd87df7"))
+		// The details are written with the rename and the version counts up once
+		url, err = urlStore.GetOriginalUrl("", "abcd1234")
+		require.NoError(t, err)
+		require.Equal(t, "Renamed", url.Title)
+		require.Equal(t, "http://example.com", url.OriginalUrl)
+		require.Equal(t, testTime("2024-10-17 10:00:00"), url.CreatedAt)
+		require.Equal(t, int64(2), url.Version)
+	})
+}
+
+func TestDeleteShortUrl(t *testing.T) {
+	urlStore := newTestStore(t, ":memory:")
+	require.NoError(t, urlStore.InsertUrl(&models.Url{OriginalUrl: "http://example.com", ShortUrl: "esd87df7", CreatedAt: testTime("2024-10-16 23:05:18")}))
+
+	require.NoError(t, urlStore.DeleteShortUrl("", "esd87df7", 0))
+	require.EqualError(t, urlStore.DeleteShortUrl("", "esd87df7", 0), ErrShortURLDoesNotExist)
+}
+
+const (
+	stressHelperEnv      = "URL_STORE_STRESS_HELPER"
+	stressProcessCount   = 4
+	stressGoroutineCount = 4
+	stressUrlCount       = 25
+)
+
+// TestStressHelperProcess is not a real test. It is run as a child process by
+// TestMultiProcessStress and reports how many inserts and updates it won.
+func TestStressHelperProcess(t *testing.T) {
+	if os.Getenv(stressHelperEnv) != "1" {
+		t.Skip("only runs as a child of TestMultiProcessStress")
+	}
+	urlStore, err := NewURLStore()
+	require.NoError(t, err)
+	defer urlStore.Close()
+
+	var inserted, updated atomic.Int64
+	var wg sync.WaitGroup
+	for g := 0; g < stressGoroutineCount; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < stressUrlCount; i++ {
+				shortUrl := fmt.Sprintf("short%d", i)
+				err := urlStore.InsertUrl(&models.Url{
+					OriginalUrl: fmt.Sprintf("http://example.com/%d", i),
+					ShortUrl:    shortUrl,
+					CreatedAt:   testTime("2024-10-16 23:05:18"),
+				})
+				switch {
+				case err == nil:
+					inserted.Add(1)
+				case err.Error() != ErrURLAlreadyShortened && err.Error() != ErrShortURLAlreadyExists:
+					t.Errorf("unexpected insert error: %v", err)
+				}
+
+				err = urlStore.UpdateShortUrl(&models.Url{ShortUrl: shortUrl}, shortUrl+"-updated", testTime("2024-10-17 10:00:00"))
+				switch {
+				case err == nil:
+					updated.Add(1)
+				case err.Error() != ErrShortURLDoesNotExist && err.Error() != ErrShortURLAlreadyExists:
+					t.Errorf("unexpected update error: %v", err)
+				}
+			}
 		}()
+	}
+	wg.Wait()
+	fmt.Printf("inserted=%d updated=%d\n", inserted.Load(), updated.Load())
+}
+
+func TestMultiProcessStress(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping multi-process stress test in short mode")
+	}
+	dbPath := filepath.Join(t.TempDir(), "stress.sqlite3")
+	// Create the schema up front so the children don't race on it
+	newTestStore(t, dbPath)
+
+	outputs := make([][]byte, stressProcessCount)
+	errs := make([]error, stressProcessCount)
+	var wg sync.WaitGroup
+	for p := 0; p < stressProcessCount; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			cmd := exec.Command(os.Args[0], "-test.run=^TestStressHelperProcess$", "-test.v")
+			cmd.Env = append(os.Environ(), stressHelperEnv+"=1", "DB_PATH="+dbPath)
+			outputs[p], errs[p] = cmd.CombinedOutput()
+		}(p)
+	}
+	wg.Wait()
+
+	var totalInserted, totalUpdated int
+	for p := 0; p < stressProcessCount; p++ {
+		require.NoError(t, errs[p], string(outputs[p]))
+		var inserted, updated int
+		for _, line := range strings.Split(string(outputs[p]), "\n") {
+			if _, err := fmt.Sscanf(line, "inserted=%d updated=%d", &inserted, &updated); err == nil {
+				break
+			}
+		}
+		totalInserted += inserted
+		totalUpdated += updated
+	}
+
+	// Every original URL is inserted exactly once and renamed exactly once across all processes
+	require.Equal(t, stressUrlCount, totalInserted)
+	require.Equal(t, stressUrlCount, totalUpdated)
+	urlStore := newTestStore(t, dbPath)
+	for i := 0; i < stressUrlCount; i++ {
+		require.True(t, urlStore.CheckShortUrlExists("", fmt.Sprintf("short%d-updated", i)))
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "migrate.sqlite3")
+	urlStore := newTestStore(t, dbPath)
+
+	var version int
+	require.NoError(t, urlStore.db.QueryRow(`PRAGMA user_version`).Scan(&version))
+	require.Equal(t, SchemaVersion(), version)
+
+	// Opening an up to date database again applies nothing
+	urlStore = newTestStore(t, dbPath)
+	require.NoError(t, urlStore.db.QueryRow(`PRAGMA user_version`).Scan(&version))
+	require.Equal(t, SchemaVersion(), version)
+}
+
+func TestUrlVersion(t *testing.T) {
+	urlStore := newTestStore(t, ":memory:")
+	require.NoError(t, urlStore.InsertUrl(&models.Url{OriginalUrl: "http://example.com", ShortUrl: "esd87df7", CreatedAt: testTime("2024-10-16 23:05:18")}))
+	url, err := urlStore.GetOriginalUrl("", "esd87df7")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), url.Version)
+
+	// The password hash is stored with the details and every change counts up the version
+	url.PasswordHash = "hash"
+	require.NoError(t, urlStore.UpdateUrlDetails(url))
+	url, err = urlStore.GetOriginalUrl("", "esd87df7")
+	require.NoError(t, err)
+	require.Equal(t, "hash", url.PasswordHash)
+	require.Equal(t, int64(2), url.Version)
+
+	// Writes limited to an older version fail and leave the url as it is
+	stale := *url
+	stale.Version = 1
+	stale.Title = "Stale"
+	require.EqualError(t, urlStore.UpdateUrlDetails(&stale), ErrVersionMismatch)
+	require.EqualError(t, urlStore.UpdateShortUrl(&models.Url{ShortUrl: "esd87df7", Version: 1}, "abcd1234", testTime("2024-10-17 10:00:00")), ErrVersionMismatch)
+	require.EqualError(t, urlStore.DeleteShortUrl("", "esd87df7", 1), ErrVersionMismatch)
+	url, err = urlStore.GetOriginalUrl("", "esd87df7")
+	require.NoError(t, err)
+	require.Empty(t, url.Title)
+	require.Equal(t, int64(2), url.Version)
+
+	require.NoError(t, urlStore.SetUrlStatus("", "esd87df7", models.StatusDisabled, ""))
+	require.NoError(t, urlStore.UpdateShortUrl(&models.Url{ShortUrl: "esd87df7", Version: 3}, "abcd1234", testTime("2024-10-17 10:00:00")))
+	// Clicks don't change the version
+	_, err = urlStore.RecordClick("", "abcd1234", "")
+	require.NoError(t, err)
+	url, err = urlStore.GetOriginalUrl("", "abcd1234")
+	require.NoError(t, err)
+	require.Equal(t, int64(4), url.Version)
+
+	require.EqualError(t, urlStore.UpdateShortUrl(&models.Url{ShortUrl: "esd87df7", Version: 4}, "other", testTime("2024-10-17 10:00:00")), ErrShortURLDoesNotExist)
+	require.NoError(t, urlStore.DeleteShortUrl("", "abcd1234", 4))
+	require.EqualError(t, urlStore.DeleteShortUrl("", "abcd1234", 4), ErrShortURLDoesNotExist)
+}
+
+func TestConsumeClick(t *testing.T) {
+	urlStore := newTestStore(t, ":memory:")
+	maxClicks := 2
+	require.NoError(t, urlStore.InsertUrl(&models.Url{OriginalUrl: "http://example.com", ShortUrl: "esd87df7", CreatedAt: testTime("2024-10-16 23:05:18"), MaxClicks: &maxClicks}))
+
+	remainingClicks, err := urlStore.ConsumeClick("", "esd87df7")
+	require.NoError(t, err)
+	require.Equal(t, 1, remainingClicks)
+	remainingClicks, err = urlStore.ConsumeClick("", "esd87df7")
+	require.NoError(t, err)
+	require.Equal(t, 0, remainingClicks)
+
+	_, err = urlStore.ConsumeClick("", "esd87df7")
+	require.EqualError(t, err, ErrShortURLExhausted)
+	_, err = urlStore.ConsumeClick("", "abcd1234")
+	require.EqualError(t, err, ErrShortURLDoesNotExist)
+}
+
+func TestDomains(t *testing.T) {
+	urlStore := newTestStore(t, ":memory:")
+	domain := &models.Domain{Name: "sho.rt", CodeLength: 6, RedirectType: 301, FallbackUrl: "https://example.com", CreatedAt: "2024-10-16 23:05:18"}
+
+	_, err := urlStore.GetDomain("sho.rt")
+	require.EqualError(t, err, ErrDomainDoesNotExist)
+
+	require.NoError(t, urlStore.InsertDomain(domain))
+	require.EqualError(t, urlStore.InsertDomain(domain), ErrDomainAlreadyExists)
+
+	storedDomain, err := urlStore.GetDomain("sho.rt")
+	require.NoError(t, err)
+	require.Equal(t, domain, storedDomain)
+
+	domains, err := urlStore.ListDomains()
+	require.NoError(t, err)
+	require.Equal(t, []models.Domain{*domain}, domains)
+
+	require.NoError(t, urlStore.DeleteDomain("sho.rt"))
+	require.EqualError(t, urlStore.DeleteDomain("sho.rt"), ErrDomainDoesNotExist)
+}
+
+func TestUrlsNamespacedByDomain(t *testing.T) {
+	urlStore := newTestStore(t, ":memory:")
+	// The same short url and original url can exist once per domain
+	require.NoError(t, urlStore.InsertUrl(&models.Url{OriginalUrl: "http://example.com", ShortUrl: "esd87df7", CreatedAt: testTime("2024-10-16 23:05:18")}))
+	require.NoError(t, urlStore.InsertUrl(&models.Url{Domain: "sho.rt", OriginalUrl: "http://example.com", ShortUrl: "esd87df7", CreatedAt: testTime("2024-10-16 23:05:18")}))
+
+	require.NoError(t, urlStore.DeleteShortUrl("sho.rt", "esd87df7", 0))
+	require.False(t, urlStore.CheckShortUrlExists("sho.rt", "esd87df7"))
+	require.True(t, urlStore.CheckShortUrlExists("", "esd87df7"))
+}
+
+func TestMigrateKeepsExistingUrls(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "legacy.sqlite3")
+	// Create a database with the original schema
+	db, err := sql.Open(SQLITE, dbPath)
+	require.NoError(t, err)
+	_, err = db.Exec(migrations[0] + `INSERT INTO urls (original_url, short_url, created_at) VALUES ('http://example.com', 'esd87df7', '2024-10-16 23:05:18');`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	urlStore := newTestStore(t, dbPath)
+	url, err := urlStore.GetOriginalUrl("", "esd87df7")
+	require.NoError(t, err)
+	require.Equal(t, "http://example.com", url.OriginalUrl)
+	require.Empty(t, url.PasswordHash)
+	require.Nil(t, url.MaxClicks)
+}
+
+func TestMigrateFailsOnDuplicateShortUrls(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "duplicates.sqlite3")
+	// The original schema only had a non-unique index on the short url
+	db, err := sql.Open(SQLITE, dbPath)
+	require.NoError(t, err)
+	_, err = db.Exec(migrations[0] + `INSERT INTO urls (original_url, short_url, created_at) VALUES
+		('http://example.com/a', 'esd87df7', '2024-10-16 23:05:18'),
+		('http://example.com/b', 'esd87df7', '2024-10-16 23:05:19'),
+		('http://example.com/c', 'k3j4h5g6', '2024-10-16 23:05:20');`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	_ = os.Setenv("DB_PATH", dbPath)
+	urlStore, err := NewURLStore()
+	require.Nil(t, urlStore)
+	require.ErrorContains(t, err, "Failed to apply migration 2")
+	require.ErrorContains(t, err, "esd87df7 is the short url of http://example.com/a, http://example.com/b")
+	require.NotContains(t, err.Error(), "k3j4h5g6")
+
+	// Nothing was applied, the database opens once the duplicate is gone
+	db, err = sql.Open(SQLITE, dbPath)
+	require.NoError(t, err)
+	_, err = db.Exec(`DELETE FROM urls WHERE original_url = 'http://example.com/b'`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+	urlStore = newTestStore(t, dbPath)
+	url, err := urlStore.GetOriginalUrl("", "esd87df7")
+	require.NoError(t, err)
+	require.Equal(t, "http://example.com/a", url.OriginalUrl)
+}
+
+// openAtSchemaVersion creates a database at dbPath with the first version migrations applied
+func openAtSchemaVersion(t *testing.T, dbPath string, version int) *sql.DB {
+	db, err := sql.Open(SQLITE, dbPath)
+	require.NoError(t, err)
+	for _, migration := range migrations[:version] {
+		_, err = db.Exec(migration)
+		require.NoError(t, err)
+	}
+	_, err = db.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, version))
+	require.NoError(t, err)
+	return db
+}
+
+func TestMigrateLocalTimes(t *testing.T) {
+	localToUtc := func(value string) string {
+		parsed, err := time.ParseInLocation("2006-01-02 15:04:05", value, time.Local)
+		require.NoError(t, err)
+		return models.FormatTimestamp(parsed)
+	}
+
+	t.Run("Converted to UTC", func(t *testing.T) {
+		dbPath := filepath.Join(t.TempDir(), "times.sqlite3")
+		db := openAtSchemaVersion(t, dbPath, 21)
+		_, err := db.Exec(`INSERT INTO urls (domain, original_url, short_url, created_at, health_status, health_checked_at, rules)
+			VALUES ('', 'http://example.com', 'esd87df7', '2024-10-16T21:05:18Z', 'ok', '2024-10-16 23:05:20',
+			'[{"destination": "http://example.com/sale", "starts_at": "2024-11-01 00:00:00"}, {"destination": "http://example.com/fr", "countries": ["FR"]}]');
+			INSERT INTO domains (name, code_length, redirect_type, created_at) VALUES ('sho.rt', 6, 301, '2024-10-16 23:05:18');`)
+		require.NoError(t, err)
+		require.NoError(t, db.Close())
+
+		urlStore := newTestStore(t, dbPath)
+		url, err := urlStore.GetOriginalUrl("", "esd87df7")
+		require.NoError(t, err)
+		require.Equal(t, localToUtc("2024-10-16 23:05:20"), url.Health.CheckedAt)
+		require.Len(t, url.Rules, 2)
+		require.Equal(t, localToUtc("2024-11-01 00:00:00"), url.Rules[0].StartsAt)
+		require.Empty(t, url.Rules[0].EndsAt)
+		require.Equal(t, []string{"FR"}, url.Rules[1].Countries)
+		require.Empty(t, url.Rules[1].StartsAt)
+		domain, err := urlStore.GetDomain("sho.rt")
+		require.NoError(t, err)
+		require.Equal(t, localToUtc("2024-10-16 23:05:18"), domain.CreatedAt)
+	})
+
+	t.Run("Unreadable times stop the migration", func(t *testing.T) {
+		dbPath := filepath.Join(t.TempDir(), "times.sqlite3")
+		db := openAtSchemaVersion(t, dbPath, 21)
+		_, err := db.Exec(`INSERT INTO webhooks (url, events, secret, created_at) VALUES ('https://hooks.example.com', '[]', 'secret', 'yesterday')`)
+		require.NoError(t, err)
+		require.NoError(t, db.Close())
+
+		_ = os.Setenv("DB_PATH", dbPath)
+		urlStore, err := NewURLStore()
+		require.Nil(t, urlStore)
+		require.ErrorContains(t, err, "Failed to apply migration 22")
+		require.ErrorContains(t, err, `created_at of webhook 1 is "yesterday"`)
+	})
+
+	t.Run("Unreadable creation times of urls", func(t *testing.T) {
+		dbPath := filepath.Join(t.TempDir(), "times.sqlite3")
+		db := openAtSchemaVersion(t, dbPath, 16)
+		_, err := db.Exec(`INSERT INTO urls (domain, original_url, short_url, created_at) VALUES
+			('', 'http://example.com/a', 'esd87df7', '2024-10-16 23:05:18'),
+			('sho.rt', 'http://example.com/b', 'k3j4h5g6', '16/10/2024')`)
+		require.NoError(t, err)
+		require.NoError(t, db.Close())
+
+		_ = os.Setenv("DB_PATH", dbPath)
+		urlStore, err := NewURLStore()
+		require.Nil(t, urlStore)
+		require.ErrorContains(t, err, "Failed to apply migration 17")
+		require.ErrorContains(t, err, `created_at of sho.rt/k3j4h5g6 is "16/10/2024"`)
+		require.NotContains(t, err.Error(), "esd87df7")
+	})
+}
+
+func TestUrlDetailsAndTags(t *testing.T) {
+	urlStore := newTestStore(t, ":memory:")
+	require.NoError(t, urlStore.InsertUrl(&models.Url{
+		OriginalUrl: "http://example.com/docs",
+		ShortUrl:    "esd87df7",
+		CreatedAt:   testTime("2024-10-16 23:05:18"),
+		Title:       "Docs",
+		Description: "Internal documentation",
+		Tags:        []string{"internal", "docs"},
+		Metadata:    map[string]interface{}{"owner": "platform"},
+	}))
+	require.NoError(t, urlStore.InsertUrl(&models.Url{
+		OriginalUrl: "http://example.com/blog",
+		ShortUrl:    "abcd1234",
+		CreatedAt:   testTime("2024-10-17 10:00:00"),
+		Tags:        []string{"blog"},
+	}))
+
+	url, err := urlStore.GetOriginalUrl("", "esd87df7")
+	require.NoError(t, err)
+	require.Equal(t, "Docs", url.Title)
+	require.Equal(t, "Internal documentation", url.Description)
+	require.Equal(t, []string{"docs", "internal"}, url.Tags)
+	require.Equal(t, map[string]interface{}{"owner": "platform"}, url.Metadata)
+
+	// Filter by tag
+	urls, err := urlStore.ListUrls(&UrlFilter{Tag: "docs", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, urls, 1)
+	require.Equal(t, "esd87df7", urls[0].ShortUrl)
+
+	// Newest first without a filter
+	urls, err = urlStore.ListUrls(&UrlFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, urls, 2)
+	require.Equal(t, "abcd1234", urls[0].ShortUrl)
+
+	// Replace the details
+	url.Title = "Documentation"
+	url.Tags = []string{"blog"}
+	url.Metadata = nil
+	require.NoError(t, urlStore.UpdateUrlDetails(url))
+	urls, err = urlStore.ListUrls(&UrlFilter{Tag: "blog", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, urls, 2)
+	url, err = urlStore.GetOriginalUrl("", "esd87df7")
+	require.NoError(t, err)
+	require.Equal(t, "Documentation", url.Title)
+	require.Empty(t, url.Metadata)
+
+	// Deleting a url removes its tags
+	require.NoError(t, urlStore.DeleteShortUrl("", "esd87df7", 0))
+	var urlTagCount int
+	require.NoError(t, urlStore.db.QueryRow(`SELECT count(*) FROM url_tags WHERE original_url = 'http://example.com/docs'`).Scan(&urlTagCount))
+	require.Zero(t, urlTagCount)
+
+	require.EqualError(t, urlStore.UpdateUrlDetails(url), ErrShortURLDoesNotExist)
+}
+
+func TestSetPageMetadata(t *testing.T) {
+	urlStore := newTestStore(t, ":memory:")
+	page := &models.PageMetadata{Title: "Example", FaviconUrl: "http://example.com/favicon.ico", FetchedAt: "2024-10-16 23:05:20"}
+	require.EqualError(t, urlStore.SetPageMetadata("", "http://example.com", page), ErrShortURLDoesNotExist)
+
+	require.NoError(t, urlStore.InsertUrl(&models.Url{OriginalUrl: "http://example.com", ShortUrl: "esd87df7", CreatedAt: testTime("2024-10-16 23:05:18")}))
+	url, err := urlStore.GetOriginalUrl("", "esd87df7")
+	require.NoError(t, err)
+	require.Nil(t, url.Page)
+
+	require.NoError(t, urlStore.SetPageMetadata("", "http://example.com", page))
+	url, err = urlStore.GetOriginalUrl("", "esd87df7")
+	require.NoError(t, err)
+	require.Equal(t, page, url.Page)
+}
+
+func TestUtmParamsStored(t *testing.T) {
+	urlStore := newTestStore(t, ":memory:")
+	utm := &models.UtmParams{Source: "newsletter", Medium: "email", Campaign: "fall"}
+	require.NoError(t, urlStore.InsertUrl(&models.Url{OriginalUrl: "http://example.com", ShortUrl: "esd87df7", CreatedAt: testTime("2024-10-16 23:05:18"), Utm: utm, ForwardQuery: true}))
+	require.NoError(t, urlStore.InsertUrl(&models.Url{OriginalUrl: "http://example.org", ShortUrl: "esd87df8", CreatedAt: testTime("2024-10-16 23:05:18")}))
+
+	url, err := urlStore.GetOriginalUrl("", "esd87df7")
+	require.NoError(t, err)
+	require.Equal(t, utm, url.Utm)
+	require.True(t, url.ForwardQuery)
+
+	url, err = urlStore.GetOriginalUrl("", "esd87df8")
+	require.NoError(t, err)
+	require.Nil(t, url.Utm)
+	require.False(t, url.ForwardQuery)
+}
+
+func TestTargetingRulesStored(t *testing.T) {
+	urlStore := newTestStore(t, ":memory:")
+	require.NoError(t, urlStore.InsertUrl(&models.Url{OriginalUrl: "http://example.com", ShortUrl: "esd87df7", CreatedAt: testTime("2024-10-16 23:05:18")}))
+	url, err := urlStore.GetOriginalUrl("", "esd87df7")
+	require.NoError(t, err)
+	require.Nil(t, url.Rules)
+
+	url.Rules = []models.TargetingRule{
+		{Platforms: []string{"ios"}, Destination: "https://apps.apple.com/app/id1"},
+		{Countries: []string{"DE"}, Languages: []string{"de"}, Destination: "https://example.de"},
+	}
+	require.NoError(t, urlStore.UpdateUrlDetails(url))
+	updated, err := urlStore.GetOriginalUrl("", "esd87df7")
+	require.NoError(t, err)
+	require.Equal(t, url.Rules, updated.Rules)
+}
+
+func TestUrlVariants(t *testing.T) {
+	urlStore := newTestStore(t, ":memory:")
+	require.NoError(t, urlStore.InsertUrl(&models.Url{
+		OriginalUrl: "http://example.com", ShortUrl: "esd87df7", CreatedAt: testTime("2024-10-16 23:05:18"), VariantMode: "sticky",
+		Variants: []models.Variant{
+			{Name: "A", Destination: "http://example.com/a", Weight: 1},
+			{Name: "B", Destination: "http://example.com/b", Weight: 3},
+		},
+	}))
+	for i, variant := range []string{"B", "B", ""} {
+		clickCount, err := urlStore.RecordClick("", "esd87df7", variant)
+		require.NoError(t, err)
+		require.Equal(t, i+1, clickCount)
+	}
+
+	url, err := urlStore.GetOriginalUrl("", "esd87df7")
+	require.NoError(t, err)
+	require.Equal(t, "sticky", url.VariantMode)
+	require.Equal(t, 3, url.ClickCount)
+	require.Equal(t, []models.Variant{
+		{Name: "A", Destination: "http://example.com/a", Weight: 1},
+		{Name: "B", Destination: "http://example.com/b", Weight: 3, ClickCount: 2},
+	}, url.Variants)
+
+	// Changing the weights keeps the clicks of the variants that remain
+	url.Variants = []models.Variant{
+		{Name: "B", Destination: "http://example.com/b", Weight: 1},
+		{Name: "C", Destination: "http://example.com/c", Weight: 1},
+	}
+	require.NoError(t, urlStore.UpdateUrlDetails(url))
+	url, err = urlStore.GetOriginalUrl("", "esd87df7")
+	require.NoError(t, err)
+	require.Equal(t, []models.Variant{
+		{Name: "B", Destination: "http://example.com/b", Weight: 1, ClickCount: 2},
+		{Name: "C", Destination: "http://example.com/c", Weight: 1},
+	}, url.Variants)
+
+	require.NoError(t, urlStore.DeleteShortUrl("", "esd87df7", 0))
+	var remaining int
+	require.NoError(t, urlStore.db.QueryRow(`SELECT COUNT(*) FROM url_variants`).Scan(&remaining))
+	require.Zero(t, remaining)
+}
+
+func TestReplaceUrl(t *testing.T) {
+	urlStore := newTestStore(t, ":memory:")
+	require.NoError(t, urlStore.InsertUrl(&models.Url{OriginalUrl: "http://example.com/a", ShortUrl: "aaaa", CreatedAt: testTime("2024-10-16 23:05:18"),
+		Tags: []string{"old"}, Variants: []models.Variant{{Name: "A", Destination: "http://example.com/v", Weight: 1}}}))
+	require.NoError(t, urlStore.InsertUrl(&models.Url{OriginalUrl: "http://example.com/b", ShortUrl: "bbbb", CreatedAt: testTime("2024-10-16 23:05:18")}))
+
+	// The new url takes the short url of the first and the original url of the second
+	maxClicks, remainingClicks := 10, 4
+	require.NoError(t, urlStore.ReplaceUrl(&models.Url{OriginalUrl: "http://example.com/b", ShortUrl: "aaaa", CreatedAt: testTime("2020-01-02 03:04:05"),
+		MaxClicks: &maxClicks, RemainingClicks: &remainingClicks, ClickCount: 6, Tags: []string{"new"},
+		Variants: []models.Variant{{Name: "B", Destination: "http://example.com/w", Weight: 1, ClickCount: 5}}}))
+
+	require.False(t, urlStore.CheckShortUrlExists("", "bbbb"))
+	require.False(t, urlStore.CheckOriginalUrlExists("", "http://example.com/a"))
+	url, err := urlStore.GetOriginalUrl("", "aaaa")
+	require.NoError(t, err)
+	require.Equal(t, "http://example.com/b", url.OriginalUrl)
+	require.Equal(t, testTime("2020-01-02 03:04:05"), url.CreatedAt)
+	require.Equal(t, 4, *url.RemainingClicks)
+	require.Equal(t, 6, url.ClickCount)
+	require.Equal(t, []string{"new"}, url.Tags)
+	require.Equal(t, []models.Variant{{Name: "B", Destination: "http://example.com/w", Weight: 1, ClickCount: 5}}, url.Variants)
+
+	var urlTags, variants int
+	require.NoError(t, urlStore.db.QueryRow(`SELECT COUNT(*) FROM url_tags`).Scan(&urlTags))
+	require.NoError(t, urlStore.db.QueryRow(`SELECT COUNT(*) FROM url_variants`).Scan(&variants))
+	require.Equal(t, 1, urlTags)
+	require.Equal(t, 1, variants)
+}
+
+func TestExportUrls(t *testing.T) {
+	urlStore := newTestStore(t, ":memory:")
+	for i := 0; i < 5; i++ {
+		url := &models.Url{OriginalUrl: fmt.Sprintf("http://example.com/%d", i), ShortUrl: fmt.Sprintf("code%d", i),
+			CreatedAt: testTime(fmt.Sprintf("2024-10-16 23:05:1%d", i))}
+		if i%2 == 0 {
+			url.Tags = []string{"even"}
+		}
+		require.NoError(t, urlStore.InsertUrl(url))
+	}
+	require.NoError(t, urlStore.InsertUrl(&models.Url{Domain: "sho.rt", OriginalUrl: "http://example.com", ShortUrl: "other", CreatedAt: testTime("2024-10-16 23:05:18")}))
+
+	var exported []string
+	require.NoError(t, urlStore.ExportUrls(&UrlFilter{}, func(url *models.Url) error {
+		exported = append(exported, url.ShortUrl)
+		return nil
+	}))
+	require.Equal(t, []string{"code4", "code3", "code2", "code1", "code0"}, exported)
+
+	exported = nil
+	require.NoError(t, urlStore.ExportUrls(&UrlFilter{Tag: "even"}, func(url *models.Url) error {
+		exported = append(exported, url.ShortUrl)
+		return nil
+	}))
+	require.Equal(t, []string{"code4", "code2", "code0"}, exported)
+
+	// An error of the callback stops the export
+	stop := fmt.Errorf("stop")
+	calls := 0
+	require.Equal(t, stop, urlStore.ExportUrls(&UrlFilter{}, func(url *models.Url) error {
+		calls++
+		return stop
+	}))
+	require.Equal(t, 1, calls)
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	dir := t.TempDir()
+	backupDir := filepath.Join(dir, "backups")
+	urlStore := newTestStore(t, filepath.Join(dir, "database.sqlite3"))
+	require.NoError(t, urlStore.InsertUrl(&models.Url{OriginalUrl: "http://example.com/1", ShortUrl: "code1", CreatedAt: testTime("2024-10-16 23:05:18")}))
+
+	first, err := urlStore.Backup(backupDir, 2)
+	require.NoError(t, err)
+	require.Equal(t, SchemaVersion(), first.SchemaVersion)
+	checksum, err := os.ReadFile(filepath.Join(backupDir, first.Name+".sha256"))
+	require.NoError(t, err)
+	require.Equal(t, first.Sha256+"  "+first.Name+"\n", string(checksum))
+
+	// Only the two newest backups are kept
+	require.NoError(t, urlStore.InsertUrl(&models.Url{OriginalUrl: "http://example.com/2", ShortUrl: "code2", CreatedAt: testTime("2024-10-16 23:05:18")}))
+	var names []string
+	for i := 0; i < 2; i++ {
+		time.Sleep(2 * time.Millisecond)
+		backup, err := urlStore.Backup(backupDir, 2)
+		require.NoError(t, err)
+		names = append([]string{backup.Name}, names...)
+	}
+	backups, err := ListBackups(backupDir)
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	require.Equal(t, names, []string{backups[0].Name, backups[1].Name})
+	require.NoFileExists(t, filepath.Join(backupDir, first.Name))
+	require.NoFileExists(t, filepath.Join(backupDir, first.Name+".sha256"))
+
+	t.Run("Restore", func(t *testing.T) {
+		restoredPath := filepath.Join(t.TempDir(), "restored.sqlite3")
+		// The restore replaces whatever the database held
+		restoredStore := newTestStore(t, restoredPath)
+		require.NoError(t, restoredStore.InsertUrl(&models.Url{OriginalUrl: "http://example.com/old", ShortUrl: "old", CreatedAt: testTime("2024-10-16 23:05:18")}))
+		restoredStore.Close()
+
+		backup, err := RestoreBackup(filepath.Join(backupDir, backups[0].Name), restoredPath)
+		require.NoError(t, err)
+		require.Equal(t, backups[0].Sha256, backup.Sha256)
+		restoredStore = newTestStore(t, restoredPath)
+		require.True(t, restoredStore.CheckShortUrlExists("", "code1"))
+		require.True(t, restoredStore.CheckShortUrlExists("", "code2"))
+		require.False(t, restoredStore.CheckShortUrlExists("", "old"))
+	})
+
+	t.Run("Checksum", func(t *testing.T) {
+		path := filepath.Join(backupDir, backups[0].Name)
+		copied := filepath.Join(t.TempDir(), backups[0].Name)
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(copied, data, 0o644))
+		_, err = VerifyBackup(copied)
+		require.EqualError(t, err, ErrBackupChecksumMissing)
+
+		require.NoError(t, os.WriteFile(copied+".sha256", []byte(strings.Repeat("0", 64)+"  "+backups[0].Name+"\n"), 0o644))
+		_, err = RestoreBackup(copied, filepath.Join(t.TempDir(), "restored.sqlite3"))
+		require.EqualError(t, err, ErrBackupChecksumMismatch)
+	})
+
+	t.Run("Newer schema", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "backup-newer.sqlite3")
+		db, err := sql.Open(SQLITE, path)
+		require.NoError(t, err)
+		_, err = db.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, SchemaVersion()+1))
+		require.NoError(t, err)
+		require.NoError(t, db.Close())
+		sum, _, err := fileChecksum(path)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path+".sha256", []byte(sum+"  backup-newer.sqlite3\n"), 0o644))
+
+		_, err = VerifyBackup(path)
+		require.ErrorContains(t, err, ErrBackupSchemaTooNew)
 	})
+}
+
+func TestBackupNames(t *testing.T) {
+	// Older backups were named after the local time, which is ahead of UTC here
+	local := time.Local
+	time.Local = time.FixedZone("CET", 60*60)
+	defer func() {
+		time.Local = local
+	}()
+	dir := t.TempDir()
+	for _, name := range []string{
+		"backup-20241027-063000.000.sqlite3",
+		"backup-20241027-054500.000Z.sqlite3",
+		"backup-20241027-060000.000Z.sqlite3",
+		"backup-20241027-062000.000.sqlite3",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o644))
+	}
+
+	names, err := backupNames(dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{"backup-20241027-062000.000.sqlite3", "backup-20241027-063000.000.sqlite3",
+		"backup-20241027-054500.000Z.sqlite3", "backup-20241027-060000.000Z.sqlite3"}, names)
+	require.Equal(t, time.Date(2024, 10, 27, 5, 30, 0, 0, time.UTC), backupTime(names[1]).UTC())
+
+	// The newest backups are kept, even though the older names sort after them
+	require.NoError(t, rotateBackups(dir, 2))
+	names, err = backupNames(dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{"backup-20241027-054500.000Z.sqlite3", "backup-20241027-060000.000Z.sqlite3"}, names)
+}
+
+func TestUrlStatus(t *testing.T) {
+	urlStore := newTestStore(t, ":memory:")
+	require.NoError(t, urlStore.InsertUrl(&models.Url{OriginalUrl: "http://example.com", ShortUrl: "esd87df7", CreatedAt: testTime("2024-10-16 23:05:18")}))
+	require.NoError(t, urlStore.InsertUrl(&models.Url{OriginalUrl: "http://example.net", ShortUrl: "other", CreatedAt: testTime("2024-10-16 23:05:19")}))
+
+	url, err := urlStore.GetOriginalUrl("", "esd87df7")
+	require.NoError(t, err)
+	require.Equal(t, models.StatusActive, url.Status)
+
+	require.NoError(t, urlStore.SetUrlStatus("", "esd87df7", models.StatusDisabled, "Under investigation"))
+	url, err = urlStore.GetOriginalUrl("", "esd87df7")
+	require.NoError(t, err)
+	require.Equal(t, models.StatusDisabled, url.Status)
+	require.Equal(t, "Under investigation", url.StatusReason)
+
+	urls, err := urlStore.ListUrls(&UrlFilter{Status: models.StatusDisabled, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, urls, 1)
+	require.Equal(t, "esd87df7", urls[0].ShortUrl)
+
+	require.EqualError(t, urlStore.SetUrlStatus("sho.rt", "esd87df7", models.StatusDisabled, ""), ErrShortURLDoesNotExist)
+}
+
+func TestMigrateBlockedReasonToStatus(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "blocked.sqlite3")
+	// Create a database with the blocked_reason column of migration 12
+	db, err := sql.Open(SQLITE, dbPath)
+	require.NoError(t, err)
+	for _, migration := range migrations[:12] {
+		_, err = db.Exec(migration)
+		require.NoError(t, err)
+	}
+	_, err = db.Exec(`INSERT INTO urls (original_url, short_url, created_at, blocked_reason) VALUES
+		('http://example.com', 'good', '2024-10-16 23:05:18', ''),
+		('http://evil.example', 'bad', '2024-10-16 23:05:18', 'the domain evil.example is on the blocklist');
+		PRAGMA user_version = 12;`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	urlStore := newTestStore(t, dbPath)
+	url, err := urlStore.GetOriginalUrl("", "good")
+	require.NoError(t, err)
+	require.Equal(t, models.StatusActive, url.Status)
+	url, err = urlStore.GetOriginalUrl("", "bad")
+	require.NoError(t, err)
+	require.Equal(t, models.StatusBlocked, url.Status)
+	require.Equal(t, "the domain evil.example is on the blocklist", url.StatusReason)
+}
+
+func TestMigrateCreatedAtToUtc(t *testing.T) {
+	// SQLite reads the local time of the process from TZ like Go does
+	t.Setenv("TZ", "Europe/Berlin")
+	dbPath := filepath.Join(t.TempDir(), "localtime.sqlite3")
+	// Create a database with created_at in local time of migration 16
+	db, err := sql.Open(SQLITE, dbPath)
+	require.NoError(t, err)
+	for _, migration := range migrations[:16] {
+		_, err = db.Exec(migration)
+		require.NoError(t, err)
+	}
+	_, err = db.Exec(`INSERT INTO urls (original_url, short_url, created_at) VALUES
+		('http://example.com/summer', 'summer', '2024-07-01 12:00:00'),
+		('http://example.com/winter', 'winter', '2024-12-01 12:00:00');
+		PRAGMA user_version = 16;`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	urlStore := newTestStore(t, dbPath)
+	url, err := urlStore.GetOriginalUrl("", "summer")
+	require.NoError(t, err)
+	require.Equal(t, testTime("2024-07-01 10:00:00"), url.CreatedAt)
+	url, err = urlStore.GetOriginalUrl("", "winter")
+	require.NoError(t, err)
+	require.Equal(t, testTime("2024-12-01 11:00:00"), url.CreatedAt)
+}
+
+func TestReports(t *testing.T) {
+	urlStore := newTestStore(t, ":memory:")
+	for _, reason := range []string{"spam", "phishing", "other"} {
+		report := &models.Report{ShortUrl: "esd87df7", Reason: reason, CreatedAt: "2024-10-16 23:05:18"}
+		require.NoError(t, urlStore.InsertReport(report))
+		require.NotZero(t, report.Id)
+	}
+
+	reports, err := urlStore.ListReports(&ReportFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, reports, 3)
+	require.Equal(t, "spam", reports[0].Reason)
+
+	require.NoError(t, urlStore.ReviewReport(reports[1].Id, "2024-10-17 08:00:00"))
+	require.EqualError(t, urlStore.ReviewReport(1000, "2024-10-17 08:00:00"), ErrReportDoesNotExist)
+
+	pending := false
+	reports, err = urlStore.ListReports(&ReportFilter{Reviewed: &pending, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+	require.Equal(t, "other", reports[1].Reason)
+
+	reviewed := true
+	reports, err = urlStore.ListReports(&ReportFilter{Reviewed: &reviewed, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	require.Equal(t, "2024-10-17 08:00:00", reports[0].ReviewedAt)
+}
+
+func TestWebhooks(t *testing.T) {
+	urlStore := newTestStore(t, ":memory:")
+	webhook := &models.Webhook{Url: "https://hooks.example.com", Events: []string{"link.created", "link.click_threshold"},
+		Secret: "0123456789abcdef", ClickThresholds: []int{10, 100}, CreatedAt: "2024-10-16 23:05:18"}
+	require.NoError(t, urlStore.InsertWebhook(webhook))
+	require.NotZero(t, webhook.Id)
+
+	webhooks, err := urlStore.ListWebhooks()
+	require.NoError(t, err)
+	require.Equal(t, []models.Webhook{*webhook}, webhooks)
+
+	now := time.Now()
+	deliveries := []models.WebhookDelivery{
+		{WebhookId: webhook.Id, Event: "link.created", Payload: []byte(`{"event": "link.created"}`), Status: models.DeliveryPending,
+			CreatedAt: "2024-10-16 23:05:18", NextAttemptAt: now},
+		{WebhookId: webhook.Id, Event: "link.click_threshold", Payload: []byte(`{"event": "link.click_threshold"}`), Status: models.DeliveryPending,
+			CreatedAt: "2024-10-16 23:05:19", NextAttemptAt: now.Add(time.Hour)},
+	}
+	require.NoError(t, urlStore.InsertDeliveries(deliveries))
+
+	// Only the first delivery is due, and it comes with what's needed to send it
+	due, err := urlStore.DueDeliveries(now, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	require.Equal(t, deliveries[0].Id, due[0].Id)
+	require.Equal(t, webhook.Url, due[0].Url)
+	require.Equal(t, webhook.Secret, due[0].Secret)
+	require.JSONEq(t, `{"event": "link.created"}`, string(due[0].Payload))
+
+	due[0].Status = models.DeliveryDelivered
+	due[0].Attempts = 1
+	due[0].ResponseStatus = 204
+	due[0].DeliveredAt = "2024-10-16 23:05:20"
+	require.NoError(t, urlStore.FinishDelivery(&due[0]))
+
+	failed := deliveries[1]
+	failed.Status = models.DeliveryFailed
+	failed.Attempts = 8
+	failed.LastError = "receiver answered 500 Internal Server Error"
+	require.NoError(t, urlStore.FinishDelivery(&failed))
+
+	logged, err := urlStore.ListDeliveries(&DeliveryFilter{WebhookId: webhook.Id, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, logged, 2)
+	require.Equal(t, models.DeliveryFailed, logged[0].Status)
+	require.Equal(t, 204, logged[1].ResponseStatus)
+	logged, err = urlStore.ListDeliveries(&DeliveryFilter{WebhookId: webhook.Id, Status: models.DeliveryDelivered, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, logged, 1)
+
+	deadLetters, err := urlStore.ListDeadLetters(10, 0)
+	require.NoError(t, err)
+	require.Len(t, deadLetters, 1)
+	require.Equal(t, failed.Id, deadLetters[0].DeliveryId)
+	require.Equal(t, 8, deadLetters[0].Attempts)
+
+	// A retried dead letter is pending again with a fresh set of attempts
+	require.NoError(t, urlStore.RetryDeadLetter(deadLetters[0].Id, now))
+	require.EqualError(t, urlStore.RetryDeadLetter(deadLetters[0].Id, now), ErrDeadLetterDoesNotExist)
+	due, err = urlStore.DueDeliveries(now, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	require.Equal(t, failed.Id, due[0].Id)
+	require.Zero(t, due[0].Attempts)
+
+	require.NoError(t, urlStore.DeleteWebhook(webhook.Id))
+	require.EqualError(t, urlStore.DeleteWebhook(webhook.Id), ErrWebhookDoesNotExist)
+	require.EqualError(t, urlStore.FinishDelivery(&due[0]), ErrWebhookDoesNotExist)
+	logged, err = urlStore.ListDeliveries(&DeliveryFilter{WebhookId: webhook.Id, Limit: 10})
+	require.NoError(t, err)
+	require.Empty(t, logged)
+}
+
+func TestSetLinkHealth(t *testing.T) {
+	urlStore := newTestStore(t, ":memory:")
+	broken := &models.LinkHealth{Status: models.HealthBroken, StatusCode: 404, LatencyMs: 120, Error: "the destination answered 404 Not Found",
+		CheckedAt: "2024-10-16 23:05:20"}
+	require.EqualError(t, urlStore.SetLinkHealth("", "http://example.com", broken), ErrShortURLDoesNotExist)
+
+	require.NoError(t, urlStore.InsertUrl(&models.Url{OriginalUrl: "http://example.com", ShortUrl: "esd87df7", CreatedAt: testTime("2024-10-16 23:05:18")}))
+	require.NoError(t, urlStore.InsertUrl(&models.Url{OriginalUrl: "http://example.org", ShortUrl: "28b6Nabc", CreatedAt: testTime("2024-10-16 23:05:19")}))
+	url, err := urlStore.GetOriginalUrl("", "esd87df7")
+	require.NoError(t, err)
+	require.Nil(t, url.Health)
+
+	require.NoError(t, urlStore.SetLinkHealth("", "http://example.com", broken))
+	require.NoError(t, urlStore.SetLinkHealth("", "http://example.org", &models.LinkHealth{Status: models.HealthOk, StatusCode: 200,
+		LatencyMs: 80, CheckedAt: "2024-10-16 23:05:21"}))
+	url, err = urlStore.GetOriginalUrl("", "esd87df7")
+	require.NoError(t, err)
+	require.Equal(t, broken, url.Health)
+
+	urls, err := urlStore.ListUrls(&UrlFilter{Health: models.HealthBroken, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, urls, 1)
+	require.Equal(t, "esd87df7", urls[0].ShortUrl)
+	urls, err = urlStore.ListUrls(&UrlFilter{Health: models.HealthOk, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, urls, 1)
+	require.Equal(t, "28b6Nabc", urls[0].ShortUrl)
+}
+
+func TestIdempotencyKeys(t *testing.T) {
+	urlStore := newTestStore(t, ":memory:")
+	now := testTime("2024-10-16 23:05:18")
+	key := &models.IdempotencyKey{Key: "retry-1", RequestHash: "hash", CreatedAt: now}
+
+	existing, err := urlStore.ReserveIdempotencyKey(key, now.Add(-time.Hour), now.Add(-time.Minute))
+	require.NoError(t, err)
+	require.Nil(t, existing)
+
+	// A retry while the first request is handled gets the reserved key back
+	existing, err = urlStore.ReserveIdempotencyKey(key, now.Add(-time.Hour), now.Add(-time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, &models.IdempotencyKey{Key: "retry-1", RequestHash: "hash", Headers: map[string][]string{}, CreatedAt: now}, existing)
+
+	saved := *key
+	saved.Status = 201
+	saved.Headers = map[string][]string{"Content-Type": {"application/json"}, "Etag": {`"1-abc"`}}
+	saved.Response = `{"short_url":"esd87df7"}`
+	require.NoError(t, urlStore.SaveIdempotentResponse(&saved))
+	// The response is only saved once
+	require.EqualError(t, urlStore.SaveIdempotentResponse(&saved), ErrIdempotencyKeyDoesNotExist)
+	existing, err = urlStore.ReserveIdempotencyKey(&models.IdempotencyKey{Key: "retry-1", RequestHash: "other", CreatedAt: now}, now.Add(-time.Hour), now.Add(-time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, "hash", existing.RequestHash)
+	require.Equal(t, 201, existing.Status)
+	require.Equal(t, saved.Headers, existing.Headers)
+	require.Equal(t, `{"short_url":"esd87df7"}`, existing.Response)
+
+	// Once the window has passed the key can be used for another request
+	later := now.Add(2 * time.Hour)
+	reserved := &models.IdempotencyKey{Key: "retry-1", RequestHash: "other", CreatedAt: later}
+	existing, err = urlStore.ReserveIdempotencyKey(reserved, later.Add(-time.Hour), later.Add(-time.Minute))
+	require.NoError(t, err)
+	require.Nil(t, existing)
+
+	// A reservation that never got its response is given up after the timeout, its request can't save it anymore
+	retried := &models.IdempotencyKey{Key: "retry-1", RequestHash: "other", CreatedAt: later.Add(30 * time.Second)}
+	existing, err = urlStore.ReserveIdempotencyKey(retried, later.Add(-time.Hour), retried.CreatedAt.Add(-time.Minute))
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	retried.CreatedAt = later.Add(2 * time.Minute)
+	existing, err = urlStore.ReserveIdempotencyKey(retried, later.Add(-time.Hour), retried.CreatedAt.Add(-time.Minute))
+	require.NoError(t, err)
+	require.Nil(t, existing)
+	reserved.Status = 201
+	require.EqualError(t, urlStore.SaveIdempotentResponse(reserved), ErrIdempotencyKeyDoesNotExist)
+	require.EqualError(t, urlStore.ReleaseIdempotencyKey(reserved), ErrIdempotencyKeyDoesNotExist)
+
+	require.NoError(t, urlStore.ReleaseIdempotencyKey(retried))
+	require.EqualError(t, urlStore.ReleaseIdempotencyKey(retried), ErrIdempotencyKeyDoesNotExist)
+}
+
+func TestPasswordLockouts(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "lockouts.sqlite3")
+	// Two stores on one file stand for two processes serving the links
+	first := newTestStore(t, dbPath)
+	second := newTestStore(t, dbPath)
+	now := testTime("2024-10-16 23:05:18")
+	lockedUntil := now.Add(15 * time.Minute)
+
+	// The attempts given to either process add up, the third one is still compared
+	for _, urlStore := range []*URLStore{first, second, first} {
+		locked, err := urlStore.CountPasswordAttempt("", "esd87df7", 3, now, 15*time.Minute)
+		require.NoError(t, err)
+		require.True(t, locked.IsZero())
+	}
+	locked, err := second.CountPasswordAttempt("", "esd87df7", 3, now, 15*time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, lockedUntil, locked)
+
+	// Links of other domains are counted apart
+	locked, err = first.CountPasswordAttempt("sho.rt", "esd87df7", 3, now, 15*time.Minute)
+	require.NoError(t, err)
+	require.True(t, locked.IsZero())
+
+	// Once the lock expired the count starts over
+	locked, err = first.CountPasswordAttempt("", "esd87df7", 3, lockedUntil, 15*time.Minute)
+	require.NoError(t, err)
+	require.True(t, locked.IsZero())
+
+	require.NoError(t, second.ResetPasswordLockout("", "esd87df7"))
+	var count int
+	require.NoError(t, first.db.QueryRow(`SELECT COUNT(*) FROM password_lockouts WHERE domain = ''`).Scan(&count))
+	require.Zero(t, count)
+}
+
+func TestPasswordLockoutsConcurrentAttempts(t *testing.T) {
+	urlStore := newTestStore(t, filepath.Join(t.TempDir(), "lockouts.sqlite3"))
+	now := testTime("2024-10-16 23:05:18")
+
+	var wg sync.WaitGroup
+	var allowed atomic.Int32
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			locked, err := urlStore.CountPasswordAttempt("", "esd87df7", 5, now, time.Minute)
+			if err != nil {
+				t.Errorf("unexpected count error: %v", err)
+			}
+			if locked.IsZero() {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(5), allowed.Load())
+}
+
+func TestPasswordLockoutsFollowTheLink(t *testing.T) {
+	urlStore := newTestStore(t, filepath.Join(t.TempDir(), "lockouts.sqlite3"))
+	now := testTime("2024-10-16 23:05:18")
+	lock := func(shortUrl string) {
+		_, err := urlStore.CountPasswordAttempt("", shortUrl, 1, now, time.Minute)
+		require.NoError(t, err)
+	}
+	isLocked := func(shortUrl string) bool {
+		var count int
+		require.NoError(t, urlStore.db.QueryRow(`SELECT COUNT(*) FROM password_lockouts WHERE domain = '' AND short_url = ?`, shortUrl).Scan(&count))
+		return count > 0
+	}
+	url := &models.Url{OriginalUrl: "http://example.com", ShortUrl: "esd87df7", CreatedAt: now}
+	require.NoError(t, urlStore.InsertUrl(url))
+
+	// A rename takes the lock along and drops the one left on the new short url
+	lock("esd87df7")
+	lock("xyz12345")
+	require.NoError(t, urlStore.UpdateShortUrl(url, "xyz12345", now))
+	require.False(t, isLocked("esd87df7"))
+	require.True(t, isLocked("xyz12345"))
+
+	// A deleted link leaves nothing for the next link on its short url
+	require.NoError(t, urlStore.DeleteShortUrl("", "xyz12345", 0))
+	require.False(t, isLocked("xyz12345"))
 
+	lock("esd87df7")
+	require.NoError(t, urlStore.ReplaceUrl(&models.Url{OriginalUrl: "http://example.com/b", ShortUrl: "esd87df7", CreatedAt: now}))
+	require.False(t, isLocked("esd87df7"))
 }
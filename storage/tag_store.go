@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"URL_SHORTENER/models"
+)
+
+// setUrlTags replaces the tags of the url identified by its domain and original url
+func setUrlTags(tx *sql.Tx, domain string, originalUrl string, tags []string) error {
+	deleteUrlTagsQuery := `DELETE FROM url_tags WHERE domain = ? AND original_url = ?`
+	if _, err := tx.Exec(deleteUrlTagsQuery, domain, originalUrl); err != nil {
+		return err
+	}
+	insertTagQuery := `INSERT INTO tags (name) VALUES (?) ON CONFLICT (name) DO NOTHING`
+	insertUrlTagQuery := `INSERT INTO url_tags (domain, original_url, tag_id) SELECT ?, ?, id FROM tags WHERE name = ?`
+	for _, tag := range tags {
+		if _, err := tx.Exec(insertTagQuery, tag); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(insertUrlTagQuery, domain, originalUrl, tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// marshalMetadata encodes the metadata map for the metadata column, nil is stored as an empty object
+func marshalMetadata(metadata map[string]interface{}) (string, error) {
+	if metadata == nil {
+		return "{}", nil
+	}
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+// marshalRules encodes the targeting rules for the rules column, nil is stored as an empty array
+func marshalRules(rules []models.TargetingRule) (string, error) {
+	if rules == nil {
+		return "[]", nil
+	}
+	encoded, err := json.Marshal(rules)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
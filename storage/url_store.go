@@ -2,30 +2,57 @@ package storage
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"os"
-	"sync"
+	"sort"
+	"strings"
+	"time"
 
 	"URL_SHORTENER/models"
 )
 
-const SQLITE = "sqlite3"
+const (
+	SQLITE = "sqlite3"
+	// busyTimeoutMs is how long a connection waits on a locked database before giving up
+	busyTimeoutMs = "5000"
+	memoryDBPath  = ":memory:"
+)
 
 type URLStore struct {
-	db    *sql.DB    // Sqlite database
-	mutex sync.Mutex // Mutex for thread safety
+	db *sql.DB // Sqlite database
 }
 
 var ErrURLAlreadyShortened = "Requested Original URL has already been shortened."
 var ErrShortURLDoesNotExist = "The specified Short URL does not exist."
+var ErrShortURLAlreadyExists = "The generated Short URL is already in use."
+var ErrShortURLExhausted = "The specified Short URL has reached its maximum number of clicks."
+var ErrVersionMismatch = "The specified Short URL has been changed since the given version."
 
+// URLOperations works on the urls of one domain at a time.
+// The empty domain is the default namespace, used for hosts that aren't registered.
 type URLOperations interface {
 	InsertUrl(url *models.Url) error
-	CheckShortUrlExists(shortUrl string) bool
-	CheckOriginalUrlExists(originalUrl string) bool
-	GetOriginalUrl(shortUrl string) (*models.Url, error)
-	DeleteShortUrl(shortUrl string) error
-	UpdateShortUrl(updatedShortUrl string, shortUrl string, created_at string) error
+	CheckShortUrlExists(domain string, shortUrl string) bool
+	CheckOriginalUrlExists(domain string, originalUrl string) bool
+	GetOriginalUrl(domain string, shortUrl string) (*models.Url, error)
+	DeleteShortUrl(domain string, shortUrl string, version int64) error
+	UpdateShortUrl(url *models.Url, updatedShortUrl string, createdAt time.Time) error
+	ConsumeClick(domain string, shortUrl string) (int, error)
+	UpdateUrlDetails(url *models.Url) error
+	ListUrls(filter *UrlFilter) ([]models.Url, error)
+	ExportUrls(filter *UrlFilter, fn func(url *models.Url) error) error
+	ReplaceUrl(url *models.Url) error
+	SetPageMetadata(domain string, originalUrl string, page *models.PageMetadata) error
+	RecordClick(domain string, shortUrl string, variant string) (int, error)
+	SetUrlStatus(domain string, shortUrl string, status string, reason string) error
+	SetLinkHealth(domain string, originalUrl string, health *models.LinkHealth) error
+	Backup(dir string, keep int) (*models.Backup, error)
+	DomainOperations
+	ReportOperations
+	WebhookOperations
+	IdempotencyOperations
+	LockoutOperations
 }
 
 func NewURLStore() (*URLStore, error) {
@@ -33,108 +60,537 @@ func NewURLStore() (*URLStore, error) {
 	if dbPath == "" {
 		return nil, errors.New("DB_PATH environment variable not set")
 	}
-	db, err := sql.Open(SQLITE, dbPath)
+	db, err := sql.Open(SQLITE, dataSourceName(dbPath))
 	if err != nil {
 		return nil, err
 	}
-	// Create the table if not already created
-	_, err = db.Exec(`
-			CREATE TABLE IF NOT EXISTS "urls" (
-				original_url TEXT PRIMARY KEY NOT NULL,
-				short_url TEXT NOT NULL,
-				created_at TEXT NOT NULL
-			);
-		`)
-	if err != nil {
-		return nil, errors.New("Failed to create table: " + err.Error())
+	// Every connection to an in-memory database gets its own empty database,
+	// so all queries have to share a single connection.
+	if dbPath == memoryDBPath {
+		db.SetMaxOpenConns(1)
 	}
-	// Create index on the short_url
-	_, err = db.Exec(`
-				CREATE index IF NOT EXISTS idx_short ON urls (short_url);
-		`)
-	if err != nil {
-		return nil, errors.New("Failed to create index: " + err.Error())
+	// Create or upgrade the schema
+	if err = migrate(db); err != nil {
+		return nil, err
 	}
 	return &URLStore{
 		db: db,
 	}, nil
 }
 
+// dataSourceName enables WAL mode and a busy timeout on every connection,
+// so readers don't block writers and concurrent writers wait instead of failing.
+// Transactions take the write lock up front so they never fail half way on a busy database.
+func dataSourceName(dbPath string) string {
+	separator := "?"
+	if strings.Contains(dbPath, "?") {
+		separator = "&"
+	}
+	return dbPath + separator + "_journal_mode=WAL&_txlock=immediate&_busy_timeout=" + busyTimeoutMs
+}
+
+// urlColumns are the columns scanned by scanUrl. The tags are aggregated into a comma separated list.
+const urlColumns = `u.domain, u.original_url, u.short_url, u.created_at, u.password_hash, u.max_clicks, u.remaining_clicks,
+	u.title, u.description, u.metadata, u.click_count,
+	u.utm_source, u.utm_medium, u.utm_campaign, u.utm_term, u.utm_content, u.forward_query, u.rules,
+	u.page_title, u.page_description, u.page_image, u.page_site_name, u.favicon_url, u.page_fetched_at, u.page_fetch_error,
+	u.variant_mode, ` + urlVariantsColumn + `, u.status, u.status_reason,
+	u.health_status, u.health_status_code, u.health_latency_ms, u.health_error, u.health_checked_at, u.version,
+	(SELECT group_concat(t.name, ',') FROM url_tags ut JOIN tags t ON t.id = ut.tag_id
+		WHERE ut.domain = u.domain AND ut.original_url = u.original_url)`
+
+// UrlFilter narrows down the urls returned by ListUrls
+type UrlFilter struct {
+	Domain string
+	// Tag only lists urls with this tag when set
+	Tag string
+	// Status only lists urls with this status when set
+	Status string
+	// Health only lists urls whose destination had this outcome in the last check when set
+	Health string
+	Limit  int
+	Offset int
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// formatCreatedAt formats the creation time of a url in UTC. RFC 3339 in UTC has a fixed width,
+// so the text sorts like the time.
+func formatCreatedAt(createdAt time.Time) string {
+	return models.FormatTimestamp(createdAt)
+}
+
+func scanUrl(row rowScanner) (*models.Url, error) {
+	var url models.Url
+	var metadata, rules, variants string
+	var tags sql.NullString
+	var page models.PageMetadata
+	var utm models.UtmParams
+	var health models.LinkHealth
+	var createdAt string
+	err := row.Scan(&url.Domain, &url.OriginalUrl, &url.ShortUrl, &createdAt, &url.PasswordHash, &url.MaxClicks,
+		&url.RemainingClicks, &url.Title, &url.Description, &metadata, &url.ClickCount,
+		&utm.Source, &utm.Medium, &utm.Campaign, &utm.Term, &utm.Content, &url.ForwardQuery, &rules,
+		&page.Title, &page.Description, &page.Image, &page.SiteName, &page.FaviconUrl, &page.FetchedAt, &page.FetchError,
+		&url.VariantMode, &variants, &url.Status, &url.StatusReason,
+		&health.Status, &health.StatusCode, &health.LatencyMs, &health.Error, &health.CheckedAt, &url.Version, &tags)
+	if err != nil {
+		return nil, err
+	}
+	if url.CreatedAt, err = time.Parse(time.RFC3339, createdAt); err != nil {
+		return nil, err
+	}
+	if page.FetchedAt != "" {
+		url.Page = &page
+	}
+	if health.CheckedAt != "" {
+		url.Health = &health
+	}
+	if !utm.IsEmpty() {
+		url.Utm = &utm
+	}
+	if err = json.Unmarshal([]byte(metadata), &url.Metadata); err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal([]byte(rules), &url.Rules); err != nil {
+		return nil, err
+	}
+	if len(url.Rules) == 0 {
+		url.Rules = nil
+	}
+	if url.Variants, err = unmarshalVariants(variants); err != nil {
+		return nil, err
+	}
+	if tags.String != "" {
+		url.Tags = strings.Split(tags.String, ",")
+		sort.Strings(url.Tags)
+	}
+	return &url, nil
+}
+
 func (s *URLStore) InsertUrl(url *models.Url) error {
-	// Lock the mutex before performing insert operation
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
 
-	// Check if the original URL already exists
-	if s.CheckOriginalUrlExists(url.OriginalUrl) {
-		return errors.New(ErrURLAlreadyShortened)
+	if err = insertUrl(tx, url); err != nil {
+		return err
 	}
+	return tx.Commit()
+}
 
-	insertUrlQuery := `INSERT INTO urls (original_url, short_url, created_at) VALUES (?, ?, ?)`
-	_, err := s.db.Exec(insertUrlQuery, url.OriginalUrl, url.ShortUrl, url.CreatedAt)
+// ReplaceUrl inserts the url after deleting the urls of its domain using either its short url or its original url
+func (s *URLStore) ReplaceUrl(url *models.Url) error {
+	tx, err := s.db.Begin()
 	if err != nil {
 		return err
 	}
-	return nil
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	deleteUrlsQuery := `DELETE FROM urls WHERE domain = ? AND (short_url = ? OR original_url = ?) RETURNING original_url, short_url`
+	rows, err := tx.Query(deleteUrlsQuery, url.Domain, url.ShortUrl, url.OriginalUrl)
+	if err != nil {
+		return err
+	}
+	var deleted []models.Url
+	for rows.Next() {
+		var deletedUrl models.Url
+		if err = rows.Scan(&deletedUrl.OriginalUrl, &deletedUrl.ShortUrl); err != nil {
+			rows.Close()
+			return err
+		}
+		deleted = append(deleted, deletedUrl)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	for _, deletedUrl := range deleted {
+		if err = setUrlTags(tx, url.Domain, deletedUrl.OriginalUrl, nil); err != nil {
+			return err
+		}
+		if err = setUrlVariants(tx, url.Domain, deletedUrl.OriginalUrl, nil); err != nil {
+			return err
+		}
+		if err = deleteLockout(tx, url.Domain, deletedUrl.ShortUrl); err != nil {
+			return err
+		}
+	}
+	if err = insertUrl(tx, url); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-func (s *URLStore) CheckShortUrlExists(shortUrl string) bool {
-	checkShortUrlQuery := `SELECT short_url FROM urls WHERE short_url = ?`
-	err := s.db.QueryRow(checkShortUrlQuery, shortUrl).Scan(&shortUrl)
+// insertUrl inserts the url with its tags and variants. The remaining clicks default to the max clicks.
+func insertUrl(tx *sql.Tx, url *models.Url) error {
+	metadata, err := marshalMetadata(url.Metadata)
+	if err != nil {
+		return err
+	}
+	rules, err := marshalRules(url.Rules)
+	if err != nil {
+		return err
+	}
+	remainingClicks := url.RemainingClicks
+	if remainingClicks == nil {
+		remainingClicks = url.MaxClicks
+	}
+
+	// The primary key on (domain, original_url) decides which of several concurrent inserts wins
+	utm := url.Utm
+	if utm == nil {
+		utm = &models.UtmParams{}
+	}
+	status := url.Status
+	if status == "" {
+		status = models.StatusActive
+	}
+	insertUrlQuery := `INSERT INTO urls (domain, original_url, short_url, created_at, password_hash, max_clicks, remaining_clicks,
+			click_count, title, description, metadata, utm_source, utm_medium, utm_campaign, utm_term, utm_content,
+			forward_query, rules, variant_mode, status, status_reason)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (domain, original_url) DO NOTHING`
+	result, err := tx.Exec(insertUrlQuery, url.Domain, url.OriginalUrl, url.ShortUrl, formatCreatedAt(url.CreatedAt), url.PasswordHash,
+		url.MaxClicks, remainingClicks, url.ClickCount, url.Title, url.Description, metadata,
+		utm.Source, utm.Medium, utm.Campaign, utm.Term, utm.Content, url.ForwardQuery, rules, url.VariantMode,
+		status, url.StatusReason)
+	if err != nil {
+		if isUniqueConstraintError(err) {
+			return errors.New(ErrShortURLAlreadyExists)
+		}
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New(ErrURLAlreadyShortened)
+	}
+	if err = setUrlTags(tx, url.Domain, url.OriginalUrl, url.Tags); err != nil {
+		return err
+	}
+	if err = setUrlVariants(tx, url.Domain, url.OriginalUrl, url.Variants); err != nil {
+		return err
+	}
+	return deleteLockout(tx, url.Domain, url.ShortUrl)
+}
+
+func (s *URLStore) CheckShortUrlExists(domain string, shortUrl string) bool {
+	checkShortUrlQuery := `SELECT short_url FROM urls WHERE domain = ? AND short_url = ?`
+	err := s.db.QueryRow(checkShortUrlQuery, domain, shortUrl).Scan(&shortUrl)
 	if err == nil {
 		return true
 	}
 	return false
 }
-func (s *URLStore) CheckOriginalUrlExists(originalUrl string) bool {
-	checkOriginalUrlQuery := `SELECT original_url from urls WHERE original_url= ?`
-	err := s.db.QueryRow(checkOriginalUrlQuery, originalUrl).Scan(&originalUrl)
+func (s *URLStore) CheckOriginalUrlExists(domain string, originalUrl string) bool {
+	checkOriginalUrlQuery := `SELECT original_url from urls WHERE domain = ? AND original_url= ?`
+	err := s.db.QueryRow(checkOriginalUrlQuery, domain, originalUrl).Scan(&originalUrl)
 	if err == nil {
 		return true
 	}
 	return false
 }
 
-func (s *URLStore) GetOriginalUrl(shortUrl string) (*models.Url, error) {
-	getOriginalUrlQuery := `SELECT original_url, short_url, created_at FROM urls WHERE short_url = ?`
-	var url models.Url
-	err := s.db.QueryRow(getOriginalUrlQuery, shortUrl).Scan(&url.OriginalUrl, &url.ShortUrl, &url.CreatedAt)
+func (s *URLStore) GetOriginalUrl(domain string, shortUrl string) (*models.Url, error) {
+	getOriginalUrlQuery := `SELECT ` + urlColumns + ` FROM urls u WHERE u.domain = ? AND u.short_url = ?`
+	return scanUrl(s.db.QueryRow(getOriginalUrlQuery, domain, shortUrl))
+}
+
+// filterUrlsQuery selects the urls matching the filter, newest first. The limit and offset are left to the caller.
+func filterUrlsQuery(filter *UrlFilter) (string, []interface{}) {
+	filterUrlsQuery := `SELECT ` + urlColumns + ` FROM urls u WHERE u.domain = ?`
+	args := []interface{}{filter.Domain}
+	if filter.Tag != "" {
+		filterUrlsQuery += ` AND EXISTS (SELECT 1 FROM url_tags ut JOIN tags t ON t.id = ut.tag_id
+			WHERE ut.domain = u.domain AND ut.original_url = u.original_url AND t.name = ?)`
+		args = append(args, filter.Tag)
+	}
+	if filter.Status != "" {
+		filterUrlsQuery += ` AND u.status = ?`
+		args = append(args, filter.Status)
+	}
+	if filter.Health != "" {
+		filterUrlsQuery += ` AND u.health_status = ?`
+		args = append(args, filter.Health)
+	}
+	filterUrlsQuery += ` ORDER BY u.created_at DESC, u.short_url`
+	return filterUrlsQuery, args
+}
+
+// ListUrls returns the urls of a domain, newest first
+func (s *URLStore) ListUrls(filter *UrlFilter) ([]models.Url, error) {
+	listUrlsQuery, args := filterUrlsQuery(filter)
+	listUrlsQuery += ` LIMIT ? OFFSET ?`
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := s.db.Query(listUrlsQuery, args...)
 	if err != nil {
 		return nil, err
 	}
-	return &url, nil
+	defer rows.Close()
+
+	urls := make([]models.Url, 0)
+	for rows.Next() {
+		url, err := scanUrl(rows)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, *url)
+	}
+	return urls, rows.Err()
 }
 
-func (s *URLStore) DeleteShortUrl(shortUrl string) error {
-	// Lock the mutex before performing delete operation
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
-	if !s.CheckShortUrlExists(shortUrl) {
-		return errors.New(ErrShortURLDoesNotExist)
+// ExportUrls calls fn with every url matching the filter, newest first, while they are read.
+// The limit and offset of the filter are ignored. fn must not use the store, the in-memory
+// database has a single connection which is busy until all urls are read.
+func (s *URLStore) ExportUrls(filter *UrlFilter, fn func(url *models.Url) error) error {
+	exportUrlsQuery, args := filterUrlsQuery(filter)
+	rows, err := s.db.Query(exportUrlsQuery, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		url, err := scanUrl(rows)
+		if err != nil {
+			return err
+		}
+		if err = fn(url); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// DeleteShortUrl deletes the url. A version other than 0 is the version the url must still have.
+func (s *URLStore) DeleteShortUrl(domain string, shortUrl string, version int64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	deleteUrlQuery := `DELETE FROM urls WHERE domain = ? AND short_url = ? AND (? = 0 OR version = ?) RETURNING original_url`
+	var originalUrl string
+	err = tx.QueryRow(deleteUrlQuery, domain, shortUrl, version, version).Scan(&originalUrl)
+	if err != nil {
+		// Another request may already have deleted or changed the row
+		if errors.Is(err, sql.ErrNoRows) {
+			return unmatchedUrlError(tx, domain, shortUrl)
+		}
+		return err
+	}
+	if err = setUrlTags(tx, domain, originalUrl, nil); err != nil {
+		return err
+	}
+	if err = setUrlVariants(tx, domain, originalUrl, nil); err != nil {
+		return err
+	}
+	if err = deleteLockout(tx, domain, shortUrl); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UpdateUrlDetails replaces the password hash, title, description, tags, metadata, targeting rules and variants
+// of the url. A version other than 0 is the version the url must still have, like the one it was read with.
+func (s *URLStore) UpdateUrlDetails(url *models.Url) error {
+	return s.updateUrl(url, url.ShortUrl, sql.NullString{})
+}
+
+// UpdateShortUrl renames the url to updatedShortUrl, resets its creation time and replaces its details like
+// UpdateUrlDetails, all in one transaction. A version other than 0 is the version the url must still have.
+func (s *URLStore) UpdateShortUrl(url *models.Url, updatedShortUrl string, createdAt time.Time) error {
+	return s.updateUrl(url, updatedShortUrl, sql.NullString{String: formatCreatedAt(createdAt), Valid: true})
+}
+
+// updateUrl writes the details of the url under updatedShortUrl, keeping its creation time when createdAt is null
+func (s *URLStore) updateUrl(url *models.Url, updatedShortUrl string, createdAt sql.NullString) error {
+	metadata, err := marshalMetadata(url.Metadata)
+	if err != nil {
+		return err
+	}
+	rules, err := marshalRules(url.Rules)
+	if err != nil {
+		return err
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
 	}
-	deleteUrlQuery := `DELETE FROM urls WHERE short_url = ?`
-	_, err := s.db.Exec(deleteUrlQuery, shortUrl)
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	updateUrlQuery := `UPDATE urls SET short_url = ?, created_at = COALESCE(?, created_at), password_hash = ?, title = ?,
+			description = ?, metadata = ?, rules = ?, variant_mode = ?, version = version + 1
+		WHERE domain = ? AND short_url = ? AND (? = 0 OR version = ?) RETURNING original_url`
+	var originalUrl string
+	err = tx.QueryRow(updateUrlQuery, updatedShortUrl, createdAt, url.PasswordHash, url.Title, url.Description, metadata,
+		rules, url.VariantMode, url.Domain, url.ShortUrl, url.Version, url.Version).Scan(&originalUrl)
 	if err != nil {
+		// No row matched, either it never existed or a concurrent update renamed or changed it first
+		if errors.Is(err, sql.ErrNoRows) {
+			return unmatchedUrlError(tx, url.Domain, url.ShortUrl)
+		}
+		if isUniqueConstraintError(err) {
+			return errors.New(ErrShortURLAlreadyExists)
+		}
 		return err
 	}
+	if err = setUrlTags(tx, url.Domain, originalUrl, url.Tags); err != nil {
+		return err
+	}
+	if err = setUrlVariants(tx, url.Domain, originalUrl, url.Variants); err != nil {
+		return err
+	}
+	if err = moveLockout(tx, url.Domain, url.ShortUrl, updatedShortUrl); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ConsumeClick uses up one click of a link with a click limit and returns the clicks left.
+// The check and decrement happen in one statement, so concurrent clicks can't overdraw the limit.
+// Links without a click limit are reported as exhausted, so only call it for limited links.
+func (s *URLStore) ConsumeClick(domain string, shortUrl string) (int, error) {
+	consumeClickQuery := `UPDATE urls SET remaining_clicks = remaining_clicks - 1
+		WHERE domain = ? AND short_url = ? AND remaining_clicks > 0 RETURNING remaining_clicks`
+	var remainingClicks int
+	err := s.db.QueryRow(consumeClickQuery, domain, shortUrl).Scan(&remainingClicks)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			if s.CheckShortUrlExists(domain, shortUrl) {
+				return 0, errors.New(ErrShortURLExhausted)
+			}
+			return 0, errors.New(ErrShortURLDoesNotExist)
+		}
+		return 0, err
+	}
+	return remainingClicks, nil
+}
+
+// RecordClick counts one visit of the url, and of the variant served when not empty.
+// It returns the click count of the url including this visit.
+func (s *URLStore) RecordClick(domain string, shortUrl string, variant string) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	recordClickQuery := `UPDATE urls SET click_count = click_count + 1 WHERE domain = ? AND short_url = ? RETURNING original_url, click_count`
+	var originalUrl string
+	var clickCount int
+	err = tx.QueryRow(recordClickQuery, domain, shortUrl).Scan(&originalUrl, &clickCount)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, errors.New(ErrShortURLDoesNotExist)
+		}
+		return 0, err
+	}
+	if variant != "" {
+		recordVariantClickQuery := `UPDATE url_variants SET click_count = click_count + 1
+			WHERE domain = ? AND original_url = ? AND name = ?`
+		if _, err = tx.Exec(recordVariantClickQuery, domain, originalUrl, variant); err != nil {
+			return 0, err
+		}
+	}
+	return clickCount, tx.Commit()
+}
+
+// SetPageMetadata stores what was fetched from the destination page of the url
+func (s *URLStore) SetPageMetadata(domain string, originalUrl string, page *models.PageMetadata) error {
+	setPageMetadataQuery := `UPDATE urls SET page_title = ?, page_description = ?, page_image = ?, page_site_name = ?,
+		favicon_url = ?, page_fetched_at = ?, page_fetch_error = ? WHERE domain = ? AND original_url = ?`
+	result, err := s.db.Exec(setPageMetadataQuery, page.Title, page.Description, page.Image, page.SiteName,
+		page.FaviconUrl, page.FetchedAt, page.FetchError, domain, originalUrl)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	// The url may have been deleted while its page was fetched
+	if rowsAffected == 0 {
+		return errors.New(ErrShortURLDoesNotExist)
+	}
 	return nil
 }
 
-func (s *URLStore) UpdateShortUrl(updatedShortUrl string, shortUrl string, created_at string) error {
-	// Lock the mutex before performing update operation
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
-	if !s.CheckShortUrlExists(shortUrl) {
+// SetLinkHealth stores the outcome of the health check of the original url
+func (s *URLStore) SetLinkHealth(domain string, originalUrl string, health *models.LinkHealth) error {
+	setLinkHealthQuery := `UPDATE urls SET health_status = ?, health_status_code = ?, health_latency_ms = ?, health_error = ?,
+		health_checked_at = ? WHERE domain = ? AND original_url = ?`
+	result, err := s.db.Exec(setLinkHealthQuery, health.Status, health.StatusCode, health.LatencyMs, health.Error,
+		health.CheckedAt, domain, originalUrl)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	// The url may have been deleted while its destination was checked
+	if rowsAffected == 0 {
 		return errors.New(ErrShortURLDoesNotExist)
 	}
-	updateUrlQuery := `UPDATE urls SET short_url = ?, created_at = ? WHERE short_url = ?`
-	_, err := s.db.Exec(updateUrlQuery, updatedShortUrl, created_at, shortUrl)
+	return nil
+}
+
+// SetUrlStatus disables, blocks or reactivates the url. The reason is shown instead of following it.
+func (s *URLStore) SetUrlStatus(domain string, shortUrl string, status string, reason string) error {
+	setUrlStatusQuery := `UPDATE urls SET status = ?, status_reason = ?, version = version + 1 WHERE domain = ? AND short_url = ?`
+	result, err := s.db.Exec(setUrlStatusQuery, status, reason, domain, shortUrl)
 	if err != nil {
 		return err
 	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New(ErrShortURLDoesNotExist)
+	}
 	return nil
 }
 
+// rowQueryer is a database or a transaction
+type rowQueryer interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// unmatchedUrlError tells why a write limited to a version of the url matched no row:
+// the url has another version or doesn't exist
+func unmatchedUrlError(db rowQueryer, domain string, shortUrl string) error {
+	var version int64
+	err := db.QueryRow(`SELECT version FROM urls WHERE domain = ? AND short_url = ?`, domain, shortUrl).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New(ErrShortURLDoesNotExist)
+	}
+	if err != nil {
+		return err
+	}
+	return errors.New(ErrVersionMismatch)
+}
+
 func (s *URLStore) Close() {
 	_ = s.db.Close()
 }
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"URL_SHORTENER/models"
+)
+
+// urlVariantsColumn aggregates the variants of a url into a JSON array ordered by position
+const urlVariantsColumn = `(SELECT json_group_array(json_object('name', v.name, 'destination', v.destination,
+		'weight', v.weight, 'click_count', v.click_count))
+	FROM (SELECT * FROM url_variants WHERE domain = u.domain AND original_url = u.original_url ORDER BY position) v)`
+
+// setUrlVariants replaces the variants of the url. Variants keeping their name keep their click count,
+// new ones start with the click count given.
+func setUrlVariants(tx *sql.Tx, domain string, originalUrl string, variants []models.Variant) error {
+	names := make([]string, len(variants))
+	for i, variant := range variants {
+		names[i] = variant.Name
+	}
+	encodedNames, err := json.Marshal(names)
+	if err != nil {
+		return err
+	}
+	deleteVariantsQuery := `DELETE FROM url_variants WHERE domain = ? AND original_url = ?
+		AND name NOT IN (SELECT value FROM json_each(?))`
+	if _, err = tx.Exec(deleteVariantsQuery, domain, originalUrl, string(encodedNames)); err != nil {
+		return err
+	}
+	upsertVariantQuery := `INSERT INTO url_variants (domain, original_url, name, destination, weight, position, click_count)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (domain, original_url, name) DO UPDATE SET
+			destination = excluded.destination, weight = excluded.weight, position = excluded.position`
+	for position, variant := range variants {
+		_, err = tx.Exec(upsertVariantQuery, domain, originalUrl, variant.Name, variant.Destination, variant.Weight, position,
+			variant.ClickCount)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unmarshalVariants decodes the urlVariantsColumn, no variants decode to nil
+func unmarshalVariants(encoded string) ([]models.Variant, error) {
+	var variants []models.Variant
+	if err := json.Unmarshal([]byte(encoded), &variants); err != nil {
+		return nil, err
+	}
+	if len(variants) == 0 {
+		return nil, nil
+	}
+	return variants, nil
+}
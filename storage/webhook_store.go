@@ -0,0 +1,301 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"URL_SHORTENER/models"
+)
+
+var ErrWebhookDoesNotExist = "The specified webhook does not exist."
+var ErrDeadLetterDoesNotExist = "The specified dead letter does not exist."
+
+type WebhookOperations interface {
+	InsertWebhook(webhook *models.Webhook) error
+	ListWebhooks() ([]models.Webhook, error)
+	DeleteWebhook(id int64) error
+	InsertDeliveries(deliveries []models.WebhookDelivery) error
+	DueDeliveries(now time.Time, limit int) ([]models.WebhookDelivery, error)
+	FinishDelivery(delivery *models.WebhookDelivery) error
+	ListDeliveries(filter *DeliveryFilter) ([]models.WebhookDelivery, error)
+	ListDeadLetters(limit int, offset int) ([]models.WebhookDeadLetter, error)
+	RetryDeadLetter(id int64, now time.Time) error
+}
+
+// DeliveryFilter narrows down the deliveries returned by ListDeliveries
+type DeliveryFilter struct {
+	WebhookId int64
+	// Status only lists deliveries with this status when set
+	Status string
+	Limit  int
+	Offset int
+}
+
+func (s *URLStore) InsertWebhook(webhook *models.Webhook) error {
+	events, err := json.Marshal(webhook.Events)
+	if err != nil {
+		return err
+	}
+	thresholds := webhook.ClickThresholds
+	if thresholds == nil {
+		thresholds = []int{}
+	}
+	clickThresholds, err := json.Marshal(thresholds)
+	if err != nil {
+		return err
+	}
+	insertWebhookQuery := `INSERT INTO webhooks (url, events, secret, click_thresholds, created_at) VALUES (?, ?, ?, ?, ?)`
+	result, err := s.db.Exec(insertWebhookQuery, webhook.Url, string(events), webhook.Secret, string(clickThresholds), webhook.CreatedAt)
+	if err != nil {
+		return err
+	}
+	webhook.Id, err = result.LastInsertId()
+	return err
+}
+
+func (s *URLStore) ListWebhooks() ([]models.Webhook, error) {
+	listWebhooksQuery := `SELECT id, url, events, secret, click_thresholds, created_at FROM webhooks ORDER BY id`
+	rows, err := s.db.Query(listWebhooksQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := make([]models.Webhook, 0)
+	for rows.Next() {
+		var webhook models.Webhook
+		var events, clickThresholds string
+		err = rows.Scan(&webhook.Id, &webhook.Url, &events, &webhook.Secret, &clickThresholds, &webhook.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal([]byte(events), &webhook.Events); err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal([]byte(clickThresholds), &webhook.ClickThresholds); err != nil {
+			return nil, err
+		}
+		if len(webhook.ClickThresholds) == 0 {
+			webhook.ClickThresholds = nil
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, rows.Err()
+}
+
+// DeleteWebhook removes the webhook along with its deliveries and dead letters
+func (s *URLStore) DeleteWebhook(id int64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	result, err := tx.Exec(`DELETE FROM webhooks WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New(ErrWebhookDoesNotExist)
+	}
+	if _, err = tx.Exec(`DELETE FROM webhook_deliveries WHERE webhook_id = ?`, id); err != nil {
+		return err
+	}
+	if _, err = tx.Exec(`DELETE FROM webhook_dead_letters WHERE webhook_id = ?`, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// InsertDeliveries queues the deliveries and sets their ids
+func (s *URLStore) InsertDeliveries(deliveries []models.WebhookDelivery) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	insertDeliveryQuery := `INSERT INTO webhook_deliveries (webhook_id, event, payload, status, created_at, next_attempt_at)
+		VALUES (?, ?, ?, ?, ?, ?)`
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		result, err := tx.Exec(insertDeliveryQuery, delivery.WebhookId, delivery.Event, string(delivery.Payload),
+			delivery.Status, delivery.CreatedAt, delivery.NextAttemptAt.UnixMilli())
+		if err != nil {
+			return err
+		}
+		if delivery.Id, err = result.LastInsertId(); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// DueDeliveries returns the pending deliveries whose next attempt is due, oldest first,
+// with the url and secret of their webhook
+func (s *URLStore) DueDeliveries(now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	dueDeliveriesQuery := `SELECT ` + deliveryColumns + `, w.url, w.secret FROM webhook_deliveries d
+		JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.status = ? AND d.next_attempt_at <= ? ORDER BY d.next_attempt_at, d.id LIMIT ?`
+	rows, err := s.db.Query(dueDeliveriesQuery, models.DeliveryPending, now.UnixMilli(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]models.WebhookDelivery, 0)
+	for rows.Next() {
+		delivery, err := scanDelivery(rows, true)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+// FinishDelivery stores the outcome of an attempt. A failed delivery goes to the dead letters.
+func (s *URLStore) FinishDelivery(delivery *models.WebhookDelivery) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	finishDeliveryQuery := `UPDATE webhook_deliveries SET status = ?, attempts = ?, response_status = ?, last_error = ?,
+		delivered_at = ?, next_attempt_at = ? WHERE id = ?`
+	result, err := tx.Exec(finishDeliveryQuery, delivery.Status, delivery.Attempts, delivery.ResponseStatus, delivery.LastError,
+		delivery.DeliveredAt, delivery.NextAttemptAt.UnixMilli(), delivery.Id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	// The webhook may have been deleted while the delivery was sent
+	if rowsAffected == 0 {
+		return errors.New(ErrWebhookDoesNotExist)
+	}
+	if delivery.Status == models.DeliveryFailed {
+		insertDeadLetterQuery := `INSERT INTO webhook_dead_letters (delivery_id, webhook_id, event, payload, attempts, last_error, failed_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`
+		_, err = tx.Exec(insertDeadLetterQuery, delivery.Id, delivery.WebhookId, delivery.Event, string(delivery.Payload),
+			delivery.Attempts, delivery.LastError, models.FormatTimestamp(time.Now()))
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ListDeliveries returns the delivery log of a webhook, newest first
+func (s *URLStore) ListDeliveries(filter *DeliveryFilter) ([]models.WebhookDelivery, error) {
+	listDeliveriesQuery := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries d WHERE d.webhook_id = ?`
+	args := []interface{}{filter.WebhookId}
+	if filter.Status != "" {
+		listDeliveriesQuery += ` AND d.status = ?`
+		args = append(args, filter.Status)
+	}
+	listDeliveriesQuery += ` ORDER BY d.id DESC LIMIT ? OFFSET ?`
+	args = append(args, filter.Limit, filter.Offset)
+	rows, err := s.db.Query(listDeliveriesQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]models.WebhookDelivery, 0)
+	for rows.Next() {
+		delivery, err := scanDelivery(rows, false)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+// ListDeadLetters returns the deliveries that failed every attempt, oldest first
+func (s *URLStore) ListDeadLetters(limit int, offset int) ([]models.WebhookDeadLetter, error) {
+	listDeadLettersQuery := `SELECT id, delivery_id, webhook_id, event, payload, attempts, last_error, failed_at
+		FROM webhook_dead_letters ORDER BY id LIMIT ? OFFSET ?`
+	rows, err := s.db.Query(listDeadLettersQuery, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deadLetters := make([]models.WebhookDeadLetter, 0)
+	for rows.Next() {
+		var deadLetter models.WebhookDeadLetter
+		var payload string
+		err = rows.Scan(&deadLetter.Id, &deadLetter.DeliveryId, &deadLetter.WebhookId, &deadLetter.Event, &payload,
+			&deadLetter.Attempts, &deadLetter.LastError, &deadLetter.FailedAt)
+		if err != nil {
+			return nil, err
+		}
+		deadLetter.Payload = json.RawMessage(payload)
+		deadLetters = append(deadLetters, deadLetter)
+	}
+	return deadLetters, rows.Err()
+}
+
+// RetryDeadLetter queues the failed delivery again with a fresh set of attempts
+func (s *URLStore) RetryDeadLetter(id int64, now time.Time) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var deliveryId int64
+	err = tx.QueryRow(`DELETE FROM webhook_dead_letters WHERE id = ? RETURNING delivery_id`, id).Scan(&deliveryId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New(ErrDeadLetterDoesNotExist)
+		}
+		return err
+	}
+	retryDeliveryQuery := `UPDATE webhook_deliveries SET status = ?, attempts = 0, next_attempt_at = ? WHERE id = ?`
+	if _, err = tx.Exec(retryDeliveryQuery, models.DeliveryPending, now.UnixMilli(), deliveryId); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// deliveryColumns are the columns scanned by scanDelivery
+const deliveryColumns = `d.id, d.webhook_id, d.event, d.payload, d.status, d.attempts, d.response_status, d.last_error,
+	d.created_at, d.delivered_at, d.next_attempt_at`
+
+// scanDelivery scans the deliveryColumns, followed by the webhook url and secret when withWebhook is set
+func scanDelivery(row rowScanner, withWebhook bool) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	var payload string
+	var nextAttemptAt int64
+	dest := []interface{}{&delivery.Id, &delivery.WebhookId, &delivery.Event, &payload, &delivery.Status, &delivery.Attempts,
+		&delivery.ResponseStatus, &delivery.LastError, &delivery.CreatedAt, &delivery.DeliveredAt, &nextAttemptAt}
+	if withWebhook {
+		dest = append(dest, &delivery.Url, &delivery.Secret)
+	}
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	delivery.Payload = json.RawMessage(payload)
+	delivery.NextAttemptAt = time.UnixMilli(nextAttemptAt)
+	return &delivery, nil
+}
@@ -4,16 +4,23 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"URL_SHORTENER/blocklist"
 	"URL_SHORTENER/controller"
+	"URL_SHORTENER/fetcher"
 	"URL_SHORTENER/models"
+	"URL_SHORTENER/service"
 	"URL_SHORTENER/storage"
+	"URL_SHORTENER/webhook"
 
 	"github.com/gorilla/mux"
 	_ "github.com/mattn/go-sqlite3"
@@ -35,10 +42,36 @@ func SetupTestDB(t *testing.T) (*mux.Router, *storage.URLStore) {
 	// Initialize the router
 	router := mux.NewRouter()
 	routePrefix := "/api/short"
+	router.HandleFunc(routePrefix+"/{short_url}"+controller.PreviewSuffix, controller.PreviewUrl).Methods("GET")
 	router.HandleFunc(routePrefix, controller.CreateShortUrl).Methods("POST")
+	router.HandleFunc(routePrefix+"/import", controller.AdminOnly(controller.ImportShortUrls)).Methods("POST")
+	router.HandleFunc(routePrefix+"/export", controller.AdminOnly(controller.ExportShortUrls)).Methods("GET")
 	router.HandleFunc(routePrefix+"/{short_url}", controller.RedirectUrl).Methods("GET")
 	router.HandleFunc(routePrefix+"/{short_url}", controller.UpdateShortUrl).Methods("PUT")
 	router.HandleFunc(routePrefix+"/{short_url}", controller.DeleteShortUrl).Methods("DELETE")
+	router.HandleFunc(routePrefix, controller.ListShortUrls).Methods("GET")
+	router.HandleFunc(routePrefix+"/{short_url}", controller.PatchShortUrl).Methods("PATCH")
+	router.HandleFunc(routePrefix+"/{short_url}/unlock", controller.UnlockShortUrl).Methods("POST")
+	router.HandleFunc(routePrefix+"/{short_url}/report", controller.ReportShortUrl).Methods("POST")
+	router.HandleFunc(routePrefix+"/{short_url}/status", controller.AdminOnly(controller.SetShortUrlStatus)).Methods("PUT")
+	router.HandleFunc(routePrefix+"/{short_url}/qr", controller.GetQrCode).Methods("GET")
+	router.HandleFunc(routePrefix+"/{short_url}/info", controller.DescribeShortUrl).Methods("GET")
+	router.HandleFunc(controller.OpenApiPath, controller.OpenApiSpec).Methods("GET")
+	router.HandleFunc("/{short_url}", controller.RedirectUrl).Methods("GET")
+	router.HandleFunc("/{short_url}/unlock", controller.UnlockShortUrl).Methods("POST")
+	router.HandleFunc("/api/admin/domains", controller.AdminOnly(controller.RegisterDomain)).Methods("POST")
+	router.HandleFunc("/api/admin/domains", controller.AdminOnly(controller.ListDomains)).Methods("GET")
+	router.HandleFunc("/api/admin/domains/{domain}", controller.AdminOnly(controller.DeleteDomain)).Methods("DELETE")
+	router.HandleFunc("/api/admin/reports", controller.AdminOnly(controller.ListReports)).Methods("GET")
+	router.HandleFunc("/api/admin/reports/{report_id}/review", controller.AdminOnly(controller.ReviewReport)).Methods("POST")
+	router.HandleFunc("/api/admin/webhooks", controller.AdminOnly(controller.CreateWebhook)).Methods("POST")
+	router.HandleFunc("/api/admin/webhooks", controller.AdminOnly(controller.ListWebhooks)).Methods("GET")
+	router.HandleFunc("/api/admin/webhooks/dead-letters", controller.AdminOnly(controller.ListDeadLetters)).Methods("GET")
+	router.HandleFunc("/api/admin/webhooks/dead-letters/{dead_letter_id}/retry", controller.AdminOnly(controller.RetryDeadLetter)).Methods("POST")
+	router.HandleFunc("/api/admin/webhooks/{webhook_id}", controller.AdminOnly(controller.DeleteWebhook)).Methods("DELETE")
+	router.HandleFunc("/api/admin/webhooks/{webhook_id}/deliveries", controller.AdminOnly(controller.ListWebhookDeliveries)).Methods("GET")
+	router.HandleFunc("/api/admin/backups", controller.AdminOnly(controller.CreateBackup)).Methods("POST")
+	router.HandleFunc("/api/admin/backups", controller.AdminOnly(controller.ListBackups)).Methods("GET")
 
 	_ = httptest.NewServer(router)
 	return router, store
@@ -77,7 +110,7 @@ func TestRedirectUrlIntegration(t *testing.T) {
 
 	originalUrl := "http://example.com"
 	shortUrl := "esd87df7"
-	createdAt := time.Now().Format(controller.YYYYMMDDhhmmss)
+	createdAt := time.Now().UTC().Truncate(time.Second)
 
 	// Manually insert the test URL into the database to simulate a previously created short URL
 	err := store.InsertUrl(&models.Url{
@@ -115,7 +148,7 @@ func TestDeleteShortUrlIntegration(t *testing.T) {
 	err := store.InsertUrl(&models.Url{
 		ShortUrl:    shortUrl,
 		OriginalUrl: originalUrl,
-		CreatedAt:   time.Now().Format(controller.YYYYMMDDhhmmss),
+		CreatedAt:   time.Now().UTC().Truncate(time.Second),
 	})
 	require.NoError(t, err)
 
@@ -128,7 +161,7 @@ func TestDeleteShortUrlIntegration(t *testing.T) {
 	// Verify the response
 	require.Equal(t, http.StatusOK, result.StatusCode)
 	// Check the URL has been deleted from the database
-	exists := store.CheckShortUrlExists(shortUrl)
+	exists := store.CheckShortUrlExists("", shortUrl)
 	require.False(t, exists)
 }
 
@@ -138,7 +171,7 @@ func TestUpdateShortUrlIntegration(t *testing.T) {
 
 	shortUrl := "esd87df7"
 	originalUrl := "http://newexample.com"
-	createdAt := time.Now().Format(controller.YYYYMMDDhhmmss)
+	createdAt := time.Now().UTC().Truncate(time.Second)
 
 	// Insert the test URL into the database to simulate a previously created short URL
 	err := store.InsertUrl(&models.Url{
@@ -164,12 +197,12 @@ func TestUpdateShortUrlIntegration(t *testing.T) {
 	require.NotEqual(t, shortUrl, updatedShortUrl)
 
 	// Ensure the previous short url has been removed from the database
-	url, err := store.GetOriginalUrl(shortUrl)
+	url, err := store.GetOriginalUrl("", shortUrl)
 	require.Nil(t, url)
 	require.NotNil(t, err)
 
 	// Ensure the previous short url has been removed from the database
-	url, err = store.GetOriginalUrl(updatedShortUrl)
+	url, err = store.GetOriginalUrl("", updatedShortUrl)
 	require.NotNil(t, url)
 	require.Nil(t, err)
 	require.Equal(t, url.OriginalUrl, originalUrl)
@@ -275,9 +308,9 @@ func TestConcurrentAccess(t *testing.T) {
 	require.Equal(t, concurrencyCount-1, errorCount)
 
 	// Verify if the original URL exists
-	require.True(t, urlStore.CheckOriginalUrlExists(originalUrl))
+	require.True(t, urlStore.CheckOriginalUrlExists("", originalUrl))
 	// Verify if the short URL exists
-	require.True(t, urlStore.CheckShortUrlExists(shortUrl))
+	require.True(t, urlStore.CheckShortUrlExists("", shortUrl))
 
 	// reset error count
 	errorCount = 0
@@ -310,7 +343,7 @@ func TestConcurrentAccess(t *testing.T) {
 	require.Equal(t, concurrencyCount-1, errorCount)
 
 	// Verify if the updated URL exists
-	require.True(t, urlStore.CheckShortUrlExists(updatedShortUrl))
+	require.True(t, urlStore.CheckShortUrlExists("", updatedShortUrl))
 
 	// reset count to 0
 	errorCount = 0
@@ -337,7 +370,835 @@ func TestConcurrentAccess(t *testing.T) {
 	// Verify no of instances error encountered is (total concurrency - 1)
 	require.Equal(t, concurrencyCount-1, errorCount)
 	// Verify the URL has been deleted
-	require.False(t, urlStore.CheckShortUrlExists(shortUrl))
-	require.False(t, urlStore.CheckShortUrlExists(updatedShortUrl))
-	require.False(t, urlStore.CheckOriginalUrlExists(originalUrl))
+	require.False(t, urlStore.CheckShortUrlExists("", shortUrl))
+	require.False(t, urlStore.CheckShortUrlExists("", updatedShortUrl))
+	require.False(t, urlStore.CheckOriginalUrlExists("", originalUrl))
+}
+
+func TestConcurrentMaxClicks(t *testing.T) {
+	router, store := SetupTestDB(t)
+	defer store.Close()
+
+	maxClicks := 3
+	concurrencyCount := 10
+	jsonBody, _ := json.Marshal(&controller.CreateShortUrlRequestParams{
+		OriginalUrl: "http://example.com",
+		MaxClicks:   &maxClicks,
+	})
+	req := httptest.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(jsonBody))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	createShortUrlResp := &controller.ShortUrlResponse{}
+	_ = json.NewDecoder(w.Result().Body).Decode(createShortUrlResp)
+	require.Equal(t, http.StatusCreated, w.Result().StatusCode)
+	require.Equal(t, maxClicks, *createShortUrlResp.RemainingClicks)
+
+	// Concurrently follow the link more often than allowed
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	statusCounts := make(map[int]int)
+	for i := 0; i < concurrencyCount; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("%s/%s", endpoint, createShortUrlResp.ShortUrl), nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			mutex.Lock()
+			statusCounts[w.Result().StatusCode]++
+			mutex.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	// Verify exactly max clicks requests succeeded
+	require.Equal(t, maxClicks, statusCounts[http.StatusOK])
+	require.Equal(t, concurrencyCount-maxClicks, statusCounts[http.StatusGone])
+}
+
+func TestCustomDomainIntegration(t *testing.T) {
+	router, store := SetupTestDB(t)
+	defer store.Close()
+	controller.SetAdminApiKey("admin-key")
+	defer controller.SetAdminApiKey("")
+
+	// Register the branded domain
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/domains", bytes.NewBufferString(`{"name": "sho.rt", "code_length": 5}`))
+	req.Header.Set(controller.HeaderAdminKey, "admin-key")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Result().StatusCode)
+
+	// Shorten the same url on the branded domain and the default host
+	jsonBody, _ := json.Marshal(&controller.CreateShortUrlRequestParams{OriginalUrl: "http://example.com"})
+	req = httptest.NewRequest(http.MethodPost, "http://sho.rt/api/short", bytes.NewBuffer(jsonBody))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	brandedResp := &controller.ShortUrlResponse{}
+	_ = json.NewDecoder(w.Result().Body).Decode(brandedResp)
+	require.Equal(t, http.StatusCreated, w.Result().StatusCode)
+	require.Equal(t, "sho.rt", brandedResp.Domain)
+	require.Len(t, brandedResp.ShortUrl, 5)
+
+	req = httptest.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(jsonBody))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Result().StatusCode)
+
+	// The branded short url redirects on its domain only
+	req = httptest.NewRequest(http.MethodGet, "http://sho.rt/"+brandedResp.ShortUrl, nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusFound, w.Result().StatusCode)
+	require.Equal(t, "http://example.com", w.Result().Header.Get("Location"))
+
+	req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("%s/%s", endpoint, brandedResp.ShortUrl), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+
+	// The unlock form of a protected branded url posts to a route of the branded domain
+	req = httptest.NewRequest(http.MethodPost, "http://sho.rt/api/short", bytes.NewBufferString(`{"original_url": "http://example.com/secret", "password": "secret"}`))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Result().StatusCode)
+	protected := &controller.ShortUrlResponse{}
+	require.NoError(t, json.NewDecoder(w.Result().Body).Decode(protected))
+
+	req = httptest.NewRequest(http.MethodGet, "http://sho.rt/"+protected.ShortUrl, nil)
+	req.Header.Set("Accept", "text/html")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusUnauthorized, w.Result().StatusCode)
+	action := "/" + protected.ShortUrl + "/unlock"
+	require.Contains(t, w.Body.String(), fmt.Sprintf(`action="%s"`, action))
+
+	req = httptest.NewRequest(http.MethodPost, "http://sho.rt"+action, strings.NewReader("password=secret"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusSeeOther, w.Result().StatusCode)
+	require.Equal(t, "http://example.com/secret", w.Result().Header.Get("Location"))
+}
+
+func TestTagsIntegration(t *testing.T) {
+	router, store := SetupTestDB(t)
+	defer store.Close()
+
+	// Create two urls with different tags
+	for _, body := range []string{
+		`{"original_url": "http://example.com/docs", "title": "Docs", "tags": ["docs", "internal"]}`,
+		`{"original_url": "http://example.com/blog", "tags": ["blog"]}`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, endpoint, bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Result().StatusCode)
+	}
+
+	// List the urls tagged internal
+	req := httptest.NewRequest(http.MethodGet, endpoint+"?tag=internal", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var listResp []controller.ShortUrlResponse
+	_ = json.NewDecoder(w.Result().Body).Decode(&listResp)
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	require.Len(t, listResp, 1)
+	require.Equal(t, "Docs", listResp[0].Title)
+
+	// Retag it and verify the filter follows
+	req = httptest.NewRequest(http.MethodPatch, fmt.Sprintf("%s/%s", endpoint, listResp[0].ShortUrl), bytes.NewBufferString(`{"tags": ["blog"]}`))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+	req = httptest.NewRequest(http.MethodGet, endpoint+"?tag=blog", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	listResp = nil
+	_ = json.NewDecoder(w.Result().Body).Decode(&listResp)
+	require.Len(t, listResp, 2)
+}
+
+func TestPreviewIntegration(t *testing.T) {
+	router, store := SetupTestDB(t)
+	defer store.Close()
+
+	shortUrl := "esd87df7"
+	err := store.InsertUrl(&models.Url{
+		ShortUrl:    shortUrl,
+		OriginalUrl: "http://example.com",
+		CreatedAt:   time.Now().UTC().Truncate(time.Second),
+		Title:       "Example",
+	})
+	require.NoError(t, err)
+
+	// Follow the link twice
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("%s/%s", endpoint, shortUrl), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	}
+
+	// The preview shows the clicks and doesn't count itself
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("%s/%s+", endpoint, shortUrl), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.Contains(t, w.Body.String(), "<dd>2</dd>")
+		require.Contains(t, w.Body.String(), "<dd>Example</dd>")
+	}
+
+	// The unlock form of a protected preview posts to the unlock route of the short url
+	protected := "k3j4h5g6"
+	passwordHash, err := service.HashPassword("secret")
+	require.NoError(t, err)
+	err = store.InsertUrl(&models.Url{
+		ShortUrl:     protected,
+		OriginalUrl:  "http://example.com/secret",
+		CreatedAt:    time.Now().UTC().Truncate(time.Second),
+		PasswordHash: passwordHash,
+	})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("%s/%s+", endpoint, protected), nil)
+	req.Header.Set("Accept", "text/html")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusUnauthorized, w.Result().StatusCode)
+	action := fmt.Sprintf("%s/%s/unlock", endpoint, protected)
+	require.Contains(t, w.Body.String(), fmt.Sprintf(`action="%s"`, action))
+
+	req = httptest.NewRequest(http.MethodPost, action, strings.NewReader("password=secret"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusSeeOther, w.Result().StatusCode)
+}
+
+func TestUtmIntegration(t *testing.T) {
+	router, store := SetupTestDB(t)
+	defer store.Close()
+
+	body := `{"original_url": "http://example.com/page?utm_source=old", "utm": {"source": "newsletter", "campaign": "fall"}, "forward_query": true}`
+	req := httptest.NewRequest(http.MethodPost, endpoint, bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Result().StatusCode)
+	var created controller.ShortUrlResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&created))
+	require.Equal(t, "fall", created.Utm.Campaign)
+	require.True(t, created.ForwardQuery)
+
+	req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("%s/%s?ref=x&utm_campaign=spring", endpoint, created.ShortUrl), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	var followed controller.ShortUrlResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&followed))
+	require.Equal(t, "http://example.com/page?ref=x&utm_campaign=fall&utm_source=newsletter", followed.DestinationUrl)
+}
+
+func TestTargetingRulesIntegration(t *testing.T) {
+	router, store := SetupTestDB(t)
+	defer store.Close()
+
+	body := `{"original_url": "https://example.com", "rules": [{"platforms": ["iOS"], "destination": "https://apps.apple.com/app/id1"}]}`
+	req := httptest.NewRequest(http.MethodPost, endpoint, bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Result().StatusCode)
+	var created controller.ShortUrlResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&created))
+	require.Equal(t, []string{"ios"}, created.Rules[0].Platforms)
+
+	follow := func(userAgent string) string {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("%s/%s", endpoint, created.ShortUrl), nil)
+		req.Header.Set("User-Agent", userAgent)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		var followed controller.ShortUrlResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&followed))
+		return followed.DestinationUrl
+	}
+	require.Equal(t, "https://apps.apple.com/app/id1", follow("Mozilla/5.0 (iPad; CPU OS 17_0 like Mac OS X)"))
+	require.Equal(t, "https://example.com", follow("Mozilla/5.0 (Windows NT 10.0; Win64; x64)"))
+
+	// Removing the rules on patch sends everyone to the original url
+	req = httptest.NewRequest(http.MethodPatch, fmt.Sprintf("%s/%s", endpoint, created.ShortUrl), bytes.NewBufferString(`{"rules": []}`))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	require.Equal(t, "https://example.com", follow("Mozilla/5.0 (iPad; CPU OS 17_0 like Mac OS X)"))
+}
+
+func TestVariantsIntegration(t *testing.T) {
+	router, store := SetupTestDB(t)
+	defer store.Close()
+
+	body := `{"original_url": "https://example.com", "variant_mode": "sticky",
+		"variants": [{"destination": "https://example.com/a", "weight": 1}, {"destination": "https://example.com/b", "weight": 1}]}`
+	req := httptest.NewRequest(http.MethodPost, endpoint, bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Result().StatusCode)
+	var created controller.ShortUrlResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&created))
+	require.Equal(t, "A", created.Variants[0].Name)
+	require.Equal(t, "B", created.Variants[1].Name)
+
+	follow := func(cookies []*http.Cookie) (controller.ShortUrlResponse, []*http.Cookie) {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("%s/%s", endpoint, created.ShortUrl), nil)
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		var followed controller.ShortUrlResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&followed))
+		return followed, w.Result().Cookies()
+	}
+
+	// The visitor keeps getting the first variant served
+	first, cookies := follow(nil)
+	require.Len(t, cookies, 1)
+	for i := 0; i < 3; i++ {
+		followed, _ := follow(cookies)
+		require.Equal(t, first.DestinationUrl, followed.DestinationUrl)
+	}
+
+	// Each click is counted for the variant that served it
+	followed, _ := follow(cookies)
+	clicks := 0
+	for _, variant := range followed.Variants {
+		if variant.Destination == first.DestinationUrl {
+			clicks = variant.ClickCount
+		}
+	}
+	require.Equal(t, 5, clicks)
+
+	// Weights are editable, a variant without weight is no longer served
+	req = httptest.NewRequest(http.MethodPatch, fmt.Sprintf("%s/%s", endpoint, created.ShortUrl), bytes.NewBufferString(
+		`{"variants": [{"name": "A", "destination": "https://example.com/a", "weight": 0}, {"name": "B", "destination": "https://example.com/b", "weight": 1}]}`))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	followed, _ = follow(cookies)
+	require.Equal(t, "https://example.com/b", followed.DestinationUrl)
+}
+
+func TestImportExportIntegration(t *testing.T) {
+	router, store := SetupTestDB(t)
+	controller.SetAdminApiKey("admin-key")
+	defer controller.SetAdminApiKey("")
+
+	send := func(method string, path string, contentType string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set(controller.HeaderAdminKey, "admin-key")
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	importUrls := func(query string, contentType string, body string) controller.ImportResponse {
+		w := send(http.MethodPost, endpoint+"/import"+query, contentType, body)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode, w.Body.String())
+		var response controller.ImportResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		return response
+	}
+
+	// Export a password protected url with variants and clicks
+	w := send(http.MethodPost, endpoint, "", `{"original_url": "https://example.com", "password": "secret", "max_clicks": 5,
+		"tags": ["docs"], "metadata": {"team": "web"}, "utm": {"source": "mail"},
+		"variants": [{"destination": "https://example.com/a", "weight": 1}]}`)
+	require.Equal(t, http.StatusCreated, w.Result().StatusCode)
+	var created controller.ShortUrlResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&created))
+	req := httptest.NewRequest(http.MethodGet, endpoint+"/"+created.ShortUrl, nil)
+	req.Header.Set(controller.HeaderLinkPassword, "secret")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	w = send(http.MethodGet, endpoint+"/export", "", "")
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	require.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+	ndjson := w.Body.String()
+	w = send(http.MethodGet, endpoint+"/export?format=csv", "", "")
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	require.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+	csv := w.Body.String()
+	require.True(t, strings.HasPrefix(csv, "domain,short_url,original_url,created_at,password_hash,"))
+	store.Close()
+
+	for _, format := range []struct {
+		name        string
+		contentType string
+		body        string
+		// row is the line of the url, after the header in CSV
+		row int
+	}{
+		{"NDJSON", "application/x-ndjson", ndjson, 1},
+		{"CSV", "text/csv", csv, 2},
+	} {
+		t.Run(format.name, func(t *testing.T) {
+			router, store = SetupTestDB(t)
+			defer store.Close()
+
+			// A dry run stores nothing
+			response := importUrls("?dry_run=true", format.contentType, format.body)
+			require.Equal(t, controller.ImportResponse{DryRun: true, Imported: 1, Rows: []controller.ImportRowResult{}}, response)
+			require.False(t, store.CheckShortUrlExists("", created.ShortUrl))
+
+			response = importUrls("", format.contentType, format.body)
+			require.Equal(t, 1, response.Imported)
+			imported, err := store.GetOriginalUrl("", created.ShortUrl)
+			require.NoError(t, err)
+			require.Equal(t, created.CreatedAt, imported.CreatedAt)
+			require.Equal(t, 1, imported.ClickCount)
+			require.Equal(t, 4, *imported.RemainingClicks)
+			require.Equal(t, []string{"docs"}, imported.Tags)
+			require.Equal(t, map[string]interface{}{"team": "web"}, imported.Metadata)
+			require.Equal(t, "mail", imported.Utm.Source)
+			require.Equal(t, 1, imported.Variants[0].ClickCount)
+
+			// The password still unlocks the url
+			req := httptest.NewRequest(http.MethodGet, endpoint+"/"+created.ShortUrl, nil)
+			req.Header.Set(controller.HeaderLinkPassword, "secret")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			require.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+			// Importing again runs into the url imported before
+			response = importUrls("", format.contentType, format.body)
+			require.Equal(t, 1, response.Failed)
+			require.Equal(t, service.ImportStatusFailed, response.Rows[0].Status)
+			require.Equal(t, format.row, response.Rows[0].Row)
+			response = importUrls("?on_conflict=skip", format.contentType, format.body)
+			require.Equal(t, 1, response.Skipped)
+			response = importUrls("?on_conflict=overwrite", format.contentType, format.body)
+			require.Equal(t, 1, response.Imported)
+			imported, err = store.GetOriginalUrl("", created.ShortUrl)
+			require.NoError(t, err)
+			require.Equal(t, 1, imported.ClickCount)
+		})
+	}
+
+	t.Run("Row errors", func(t *testing.T) {
+		router, store = SetupTestDB(t)
+		defer store.Close()
+
+		body := `{"original_url": "https://example.com/1", "short_url": "abc-1", "created_at": "2021-03-04T05:06:07Z"}
+{"original_url": "https://example.com/2", "short_url": "abc-1"}
+not json
+
+{"original_url": "", "short_url": "abc-3"}
+{"original_url": "https://example.com/4", "short_url": "export"}
+{"original_url": "https://example.com/5", "created_at": "yesterday"}
+{"original_url": "https://example.com/6", "domain": "unknown.example"}
+{"original_url": "https://example.com/7", "remaining_clicks": 3}
+{"original_url": "https://example.com/8"}
+`
+		response := importUrls("", "", body)
+		require.Equal(t, 2, response.Imported)
+		require.Equal(t, 7, response.Failed)
+		rows := make([]int, 0)
+		for _, row := range response.Rows {
+			require.NotEmpty(t, row.Error)
+			rows = append(rows, row.Row)
+		}
+		require.Equal(t, []int{2, 3, 5, 6, 7, 8, 9}, rows)
+		url, err := store.GetOriginalUrl("", "abc-1")
+		require.NoError(t, err)
+		require.Equal(t, time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC), url.CreatedAt)
+
+		// Renaming gives the conflicting row a new short url
+		response = importUrls("?on_conflict=rename", "", `{"original_url": "https://example.com/2", "short_url": "abc-1"}`)
+		require.Equal(t, 1, response.Imported)
+		require.Equal(t, service.ImportStatusRenamed, response.Rows[0].Status)
+		require.NotEqual(t, "abc-1", response.Rows[0].ShortUrl)
+		require.True(t, store.CheckShortUrlExists("", response.Rows[0].ShortUrl))
+
+		// A dry run notices conflicts between the rows
+		response = importUrls("?dry_run=1", "text/csv", "short_url,original_url\nnew-1,https://example.com/new\nnew-1,https://example.com/other\n")
+		require.Equal(t, 1, response.Imported)
+		require.Equal(t, 1, response.Failed)
+		require.False(t, store.CheckShortUrlExists("", "new-1"))
+	})
+
+	t.Run("Invalid requests", func(t *testing.T) {
+		router, store = SetupTestDB(t)
+		defer store.Close()
+
+		for _, path := range []string{"/import?format=xml", "/import?on_conflict=ignore", "/import?dry_run=maybe"} {
+			w := send(http.MethodPost, endpoint+path, "", "")
+			require.Equal(t, http.StatusBadRequest, w.Result().StatusCode, path)
+		}
+		w := send(http.MethodPost, endpoint+"/import", "text/csv", "url,title\nhttps://example.com,Example\n")
+		require.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+		w = send(http.MethodGet, endpoint+"/export?format=xml", "", "")
+		require.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+
+		// Both endpoints require the admin key
+		req := httptest.NewRequest(http.MethodGet, endpoint+"/export", nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusUnauthorized, w.Result().StatusCode)
+	})
+}
+
+func TestBlocklistIntegration(t *testing.T) {
+	router, store := SetupTestDB(t)
+	defer store.Close()
+
+	domains := filepath.Join(t.TempDir(), "domains.txt")
+	require.NoError(t, os.WriteFile(domains, []byte("evil.example\n"), 0o644))
+	list, err := blocklist.Load(blocklist.Config{DomainFiles: []string{domains}})
+	require.NoError(t, err)
+	controller.SetBlocklist(list)
+	defer controller.SetBlocklist(nil)
+
+	send := func(method string, path string, body string, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// New links to a blocked destination are rejected
+	w := send(http.MethodPost, endpoint, `{"original_url": "https://login.evil.example/account"}`, "")
+	require.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	require.Contains(t, w.Body.String(), "evil.example")
+	w = send(http.MethodPost, endpoint, `{"original_url": "https://example.com", "variants": [{"name": "b", "destination": "https://evil.example", "weight": 1}]}`, "")
+	require.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+
+	w = send(http.MethodPost, endpoint, `{"original_url": "https://example.com"}`, "")
+	require.Equal(t, http.StatusCreated, w.Result().StatusCode)
+	var created controller.ShortUrlResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&created))
+	w = send(http.MethodPatch, fmt.Sprintf("%s/%s", endpoint, created.ShortUrl), `{"rules": [{"countries": ["DE"], "destination": "https://evil.example/de"}]}`, "")
+	require.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+
+	// An existing link is disabled once its destination is listed
+	err = store.InsertUrl(&models.Url{
+		ShortUrl:    "listed",
+		OriginalUrl: "https://www.evil.example/promo",
+		CreatedAt:   time.Now().UTC().Truncate(time.Second),
+	})
+	require.NoError(t, err)
+	blocked, err := blocklist.NewScanner(list, store, time.Hour, time.Hour).Rescan()
+	require.NoError(t, err)
+	require.Equal(t, 1, blocked)
+
+	w = send(http.MethodGet, endpoint+"/listed", "", "")
+	require.Equal(t, http.StatusForbidden, w.Result().StatusCode)
+	require.Contains(t, w.Body.String(), controller.ErrUrlBlocked)
+
+	// Browsers get the warning page, which doesn't link to the destination
+	for _, path := range []string{endpoint + "/listed", endpoint + "/listed+"} {
+		w = send(http.MethodGet, path, "", "text/html")
+		require.Equal(t, http.StatusForbidden, w.Result().StatusCode, path)
+		require.Contains(t, w.Body.String(), "this link has been disabled")
+		require.NotContains(t, w.Body.String(), "https://www.evil.example/promo")
+	}
+
+	url, err := store.GetOriginalUrl("", "listed")
+	require.NoError(t, err)
+	require.Equal(t, models.StatusBlocked, url.Status)
+	require.Contains(t, url.StatusReason, "evil.example")
+	require.Equal(t, 0, url.ClickCount)
+}
+
+func TestAbuseReportIntegration(t *testing.T) {
+	router, store := SetupTestDB(t)
+	defer store.Close()
+	controller.SetAdminApiKey("admin-key")
+	defer controller.SetAdminApiKey("")
+
+	send := func(method string, path string, body string, admin bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		if admin {
+			req.Header.Set(controller.HeaderAdminKey, "admin-key")
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(http.MethodPost, endpoint, `{"original_url": "https://example.com/offer"}`, false)
+	require.Equal(t, http.StatusCreated, w.Result().StatusCode)
+	var created controller.ShortUrlResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&created))
+	require.Equal(t, models.StatusActive, created.Status)
+	path := fmt.Sprintf("%s/%s", endpoint, created.ShortUrl)
+
+	// A visitor reports the link, an admin finds it in the queue and disables it
+	w = send(http.MethodPost, path+"/report", `{"reason": "phishing", "details": "Fake login page"}`, false)
+	require.Equal(t, http.StatusAccepted, w.Result().StatusCode)
+	w = send(http.MethodGet, "/api/admin/reports?reviewed=false", "", true)
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	var reports []models.Report
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&reports))
+	require.Len(t, reports, 1)
+	require.Equal(t, created.ShortUrl, reports[0].ShortUrl)
+
+	w = send(http.MethodPut, path+"/status", `{"status": "disabled", "reason": "Investigating a phishing report"}`, false)
+	require.Equal(t, http.StatusUnauthorized, w.Result().StatusCode)
+	w = send(http.MethodPut, path+"/status", `{"status": "disabled", "reason": "Investigating a phishing report"}`, true)
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	w = send(http.MethodPost, fmt.Sprintf("/api/admin/reports/%d/review", reports[0].Id), "", true)
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+	w = send(http.MethodGet, "/api/admin/reports?reviewed=false", "", true)
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&reports))
+	require.Empty(t, reports)
+
+	// The disabled link isn't followed, previewed or counted
+	w = send(http.MethodGet, path, "", false)
+	require.Equal(t, http.StatusUnavailableForLegalReasons, w.Result().StatusCode)
+	req := httptest.NewRequest(http.MethodGet, path+"+", nil)
+	req.Header.Set("Accept", "text/html")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusUnavailableForLegalReasons, w.Result().StatusCode)
+	require.Contains(t, w.Body.String(), "Investigating a phishing report")
+
+	// The status survives an export and import
+	w = send(http.MethodGet, endpoint+"/export", "", true)
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	exported := w.Body.String()
+	require.NoError(t, store.DeleteShortUrl("", created.ShortUrl, 0))
+	w = send(http.MethodPost, endpoint+"/import", exported, true)
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	url, err := store.GetOriginalUrl("", created.ShortUrl)
+	require.NoError(t, err)
+	require.Equal(t, models.StatusDisabled, url.Status)
+	require.Equal(t, 0, url.ClickCount)
+
+	// Reactivated links are followed again
+	w = send(http.MethodPut, path+"/status", `{"status": "active"}`, true)
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	w = send(http.MethodGet, path, "", false)
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+}
+
+func TestWebhookIntegration(t *testing.T) {
+	router, store := SetupTestDB(t)
+	defer store.Close()
+	controller.SetAdminApiKey("admin-key")
+	defer controller.SetAdminApiKey("")
+
+	config := webhook.DefaultConfig()
+	config.PollInterval = 10 * time.Millisecond
+	dispatcher := webhook.NewDispatcher(store, config)
+	dispatcher.Start()
+	defer dispatcher.Stop()
+	controller.SetWebhookDispatcher(dispatcher)
+	defer controller.SetWebhookDispatcher(nil)
+
+	type delivery struct {
+		header http.Header
+		body   []byte
+	}
+	deliveries := make(chan delivery, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		deliveries <- delivery{header: r.Header.Clone(), body: body}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+	next := func() (delivery, webhook.Payload) {
+		select {
+		case received := <-deliveries:
+			var payload webhook.Payload
+			require.NoError(t, json.Unmarshal(received.body, &payload))
+			return received, payload
+		case <-time.After(5 * time.Second):
+			t.Fatal("no delivery received")
+			return delivery{}, webhook.Payload{}
+		}
+	}
+
+	send := func(method string, path string, body string, admin bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		if admin {
+			req.Header.Set(controller.HeaderAdminKey, "admin-key")
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(http.MethodPost, "/api/admin/webhooks", fmt.Sprintf(`{"url": %q, "events": ["link.created", "link.deleted",
+		"link.click_threshold"], "click_thresholds": [2]}`, receiver.URL), true)
+	require.Equal(t, http.StatusCreated, w.Result().StatusCode)
+	var hook controller.CreateWebhookResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&hook))
+
+	// Creating a link sends a signed link.created event
+	w = send(http.MethodPost, endpoint, `{"original_url": "https://example.com/launch"}`, false)
+	require.Equal(t, http.StatusCreated, w.Result().StatusCode)
+	var created controller.ShortUrlResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&created))
+	received, payload := next()
+	require.Equal(t, webhook.EventLinkCreated, received.header.Get(webhook.HeaderEvent))
+	require.True(t, webhook.Verify(hook.Secret, received.header.Get(webhook.HeaderTimestamp), received.header.Get(webhook.HeaderSignature),
+		received.body, time.Now(), time.Minute))
+	require.Equal(t, created.ShortUrl, payload.Link.(map[string]interface{})["short_url"])
+
+	// The second click reaches the threshold, updates aren't subscribed to
+	path := fmt.Sprintf("%s/%s", endpoint, created.ShortUrl)
+	for i := 0; i < 3; i++ {
+		w = send(http.MethodGet, path, "", false)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	}
+	w = send(http.MethodPatch, path, `{"title": "Launch"}`, false)
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	_, payload = next()
+	require.Equal(t, webhook.EventLinkClickThreshold, payload.Event)
+	require.Equal(t, 2, payload.Threshold)
+
+	w = send(http.MethodDelete, path, "", false)
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	_, payload = next()
+	require.Equal(t, webhook.EventLinkDeleted, payload.Event)
+
+	// Every delivery is in the log
+	require.Eventually(t, func() bool {
+		w = send(http.MethodGet, fmt.Sprintf("/api/admin/webhooks/%d/deliveries?status=delivered", hook.Id), "", true)
+		var logged []models.WebhookDelivery
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&logged))
+		return len(logged) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	select {
+	case extra := <-deliveries:
+		t.Fatalf("unexpected delivery %s", extra.body)
+	default:
+	}
+}
+
+func TestSelfLinkIntegration(t *testing.T) {
+	router, store := SetupTestDB(t)
+	defer store.Close()
+
+	create := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, endpoint, bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	w := create(`{"original_url": "https://example.com/landing"}`)
+	require.Equal(t, http.StatusCreated, w.Result().StatusCode)
+	var created controller.ShortUrlResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&created))
+
+	// Short urls of this service and its API can't be shortened again
+	for _, destination := range []string{"http://example.com/" + created.ShortUrl, "http://example.com/api/short/" + created.ShortUrl} {
+		w = create(fmt.Sprintf(`{"original_url": %q}`, destination))
+		require.Equal(t, http.StatusBadRequest, w.Result().StatusCode, destination)
+	}
+	w = create(fmt.Sprintf(`{"original_url": "https://example.com/other", "variants": [{"destination": "https://example.com/%s", "weight": 1}]}`,
+		created.ShortUrl))
+	require.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	req := httptest.NewRequest(http.MethodPatch, endpoint+"/"+created.ShortUrl,
+		bytes.NewBufferString(fmt.Sprintf(`{"rules": [{"platforms": ["ios"], "destination": "https://example.com/%s"}]}`, created.ShortUrl)))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	require.Contains(t, w.Body.String(), "short urls can not lead to each other")
+
+	// With resolution on, redirect chains are stored by where they end
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/short":
+			http.Redirect(w, r, "/article", http.StatusMovedPermanently)
+		case "/back":
+			http.Redirect(w, r, "http://example.com/"+created.ShortUrl, http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer target.Close()
+	controller.SetRedirectResolver(fetcher.NewResolver(2*time.Second, 3, true))
+	defer controller.SetRedirectResolver(nil)
+
+	w = create(fmt.Sprintf(`{"original_url": "%s/short"}`, target.URL))
+	require.Equal(t, http.StatusCreated, w.Result().StatusCode)
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&created))
+	require.Equal(t, target.URL+"/article", created.OriginalUrl)
+
+	// A chain leading back to this service is rejected
+	w = create(fmt.Sprintf(`{"original_url": "%s/back"}`, target.URL))
+	require.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+}
+
+func TestLinkHealthIntegration(t *testing.T) {
+	router, store := SetupTestDB(t)
+	defer store.Close()
+	controller.SetAdminApiKey("admin-key")
+	defer controller.SetAdminApiKey("")
+	config := webhook.DefaultConfig()
+	config.PollInterval = 10 * time.Millisecond
+	dispatcher := webhook.NewDispatcher(store, config)
+	dispatcher.Start()
+	defer dispatcher.Stop()
+	controller.SetWebhookDispatcher(dispatcher)
+	defer controller.SetWebhookDispatcher(nil)
+
+	destination := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/moved-away" {
+			w.WriteHeader(http.StatusGone)
+		}
+	}))
+	defer destination.Close()
+	events := make(chan webhook.Payload, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload webhook.Payload
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		events <- payload
+	}))
+	defer receiver.Close()
+
+	send := func(method string, path string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set(controller.HeaderAdminKey, "admin-key")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	w := send(http.MethodPost, "/api/admin/webhooks", fmt.Sprintf(`{"url": %q, "events": ["link.broken"]}`, receiver.URL))
+	require.Equal(t, http.StatusCreated, w.Result().StatusCode)
+	for _, path := range []string{"/article", "/moved-away"} {
+		w = send(http.MethodPost, endpoint, fmt.Sprintf(`{"original_url": "%s%s"}`, destination.URL, path))
+		require.Equal(t, http.StatusCreated, w.Result().StatusCode)
+	}
+
+	healthConfig := fetcher.DefaultHealthConfig()
+	healthConfig.AllowPrivateAddresses = true
+	healthConfig.HostDelay = 0
+	checked, err := fetcher.NewHealthChecker(store, healthConfig, controller.PublishLinkBroken).CheckDue()
+	require.NoError(t, err)
+	require.Equal(t, 2, checked)
+
+	w = send(http.MethodGet, endpoint+"?health=broken", "")
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	var broken []controller.ShortUrlResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&broken))
+	require.Len(t, broken, 1)
+	require.Equal(t, destination.URL+"/moved-away", broken[0].OriginalUrl)
+	require.Equal(t, http.StatusGone, broken[0].Health.StatusCode)
+	require.NotEmpty(t, broken[0].Health.CheckedAt)
+
+	select {
+	case payload := <-events:
+		require.Equal(t, webhook.EventLinkBroken, payload.Event)
+		require.Equal(t, broken[0].ShortUrl, payload.Link.(map[string]interface{})["short_url"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("no link.broken event received")
+	}
 }
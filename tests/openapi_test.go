@@ -0,0 +1,245 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"URL_SHORTENER/api"
+	"URL_SHORTENER/api/client"
+	"URL_SHORTENER/controller"
+	"URL_SHORTENER/storage"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/stretchr/testify/require"
+)
+
+// apiCall is a request sent to the test router and the route of the spec it belongs to
+type apiCall struct {
+	name       string
+	method     string
+	route      string
+	path       string
+	pathParams map[string]string
+	body       string
+	header     map[string]string
+	status     int
+}
+
+func init() {
+	// The HTML pages and SVG QR codes are validated as plain text
+	openapi3filter.RegisterBodyDecoder("text/html", openapi3filter.RegisteredBodyDecoder("text/plain"))
+	openapi3filter.RegisterBodyDecoder("image/svg+xml", openapi3filter.RegisteredBodyDecoder("text/plain"))
+	openapi3filter.RegisterBodyDecoder("image/png", openapi3filter.FileBodyDecoder)
+	// The exports are validated as plain text too
+	openapi3filter.RegisterBodyDecoder("text/csv", openapi3filter.RegisteredBodyDecoder("text/plain"))
+	openapi3filter.RegisterBodyDecoder("application/x-ndjson", openapi3filter.RegisteredBodyDecoder("text/plain"))
+}
+
+// validateResponse checks the response against the operation of the route in the spec
+func validateResponse(t *testing.T, doc *openapi3.T, call apiCall, req *http.Request, res *http.Response) {
+	pathItem := doc.Paths.Find(call.route)
+	require.NotNil(t, pathItem, "route %s is missing from the spec", call.route)
+	operation := pathItem.GetOperation(call.method)
+	require.NotNil(t, operation, "%s %s is missing from the spec", call.method, call.route)
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	input := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: call.pathParams,
+			Route:      &routers.Route{Spec: doc, Path: call.route, PathItem: pathItem, Method: call.method, Operation: operation},
+			Options:    &openapi3filter.Options{AuthenticationFunc: openapi3filter.NoopAuthenticationFunc},
+		},
+		Status:  res.StatusCode,
+		Header:  res.Header,
+		Options: &openapi3filter.Options{IncludeResponseStatus: true},
+	}
+	input.SetBodyBytes(body)
+	require.NoError(t, openapi3filter.ValidateResponse(context.Background(), input), string(body))
+}
+
+func TestOpenApiSpec(t *testing.T) {
+	router, store := SetupTestDB(t)
+	defer store.Close()
+	controller.SetAdminApiKey("admin-key")
+	defer controller.SetAdminApiKey("")
+	controller.SetBackupConfig(t.TempDir(), 2)
+
+	doc, err := openapi3.NewLoader().LoadFromData(api.Spec)
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(context.Background()))
+
+	t.Run("Spec served", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, controller.OpenApiPath, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.JSONEq(t, string(api.Spec), w.Body.String())
+	})
+
+	// Create the url the other calls work on
+	req := httptest.NewRequest(http.MethodPost, endpoint, bytes.NewBufferString(`{"original_url": "https://example.com", "password": "secret",
+		"tags": ["docs"], "utm": {"source": "spec"}, "variants": [{"destination": "https://example.com/a", "weight": 1}]}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Result().StatusCode)
+	var created controller.ShortUrlResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&created))
+	shortUrl := created.ShortUrl
+	shortUrlParams := map[string]string{"short_url": shortUrl}
+	adminKey := map[string]string{controller.HeaderAdminKey: "admin-key"}
+
+	calls := []apiCall{
+		{name: "Create", method: http.MethodPost, route: "/api/short", path: endpoint, body: `{"original_url": "https://example.org", "max_clicks": 5}`,
+			header: map[string]string{"Idempotency-Key": "spec-1"}, status: http.StatusCreated},
+		{name: "Create replayed", method: http.MethodPost, route: "/api/short", path: endpoint, body: `{"original_url": "https://example.org", "max_clicks": 5}`,
+			header: map[string]string{"Idempotency-Key": "spec-1"}, status: http.StatusCreated},
+		{name: "Create key reused", method: http.MethodPost, route: "/api/short", path: endpoint, body: `{"original_url": "https://example.net"}`,
+			header: map[string]string{"Idempotency-Key": "spec-1"}, status: http.StatusUnprocessableEntity},
+		{name: "Create conflict", method: http.MethodPost, route: "/api/short", path: endpoint, body: `{"original_url": "https://example.org"}`, status: http.StatusConflict},
+		{name: "Create invalid", method: http.MethodPost, route: "/api/short", path: endpoint, body: `{}`, status: http.StatusBadRequest},
+		{name: "List", method: http.MethodGet, route: "/api/short", path: endpoint + "?tag=docs&limit=10", status: http.StatusOK},
+		{name: "List invalid", method: http.MethodGet, route: "/api/short", path: endpoint + "?limit=0", status: http.StatusBadRequest},
+		{name: "List broken", method: http.MethodGet, route: "/api/short", path: endpoint + "?health=broken", status: http.StatusOK},
+		{name: "Follow without password", method: http.MethodGet, route: "/api/short/{short_url}", path: endpoint + "/" + shortUrl, pathParams: shortUrlParams, status: http.StatusUnauthorized},
+		{name: "Follow", method: http.MethodGet, route: "/api/short/{short_url}", path: endpoint + "/" + shortUrl, pathParams: shortUrlParams,
+			header: map[string]string{controller.HeaderLinkPassword: "secret"}, status: http.StatusOK},
+		{name: "Follow unknown", method: http.MethodGet, route: "/{short_url}", path: "/unknown1", pathParams: map[string]string{"short_url": "unknown1"}, status: http.StatusNotFound},
+		{name: "Preview", method: http.MethodGet, route: "/api/short/{short_url}+", path: endpoint + "/" + shortUrl + "+", pathParams: shortUrlParams,
+			header: map[string]string{controller.HeaderLinkPassword: "secret"}, status: http.StatusOK},
+		{name: "Unlock", method: http.MethodPost, route: "/api/short/{short_url}/unlock", path: endpoint + "/" + shortUrl + "/unlock", pathParams: shortUrlParams,
+			body: "password=secret", header: map[string]string{"Content-Type": "application/x-www-form-urlencoded"}, status: http.StatusSeeOther},
+		{name: "Unlock without prefix", method: http.MethodPost, route: "/{short_url}/unlock", path: "/" + shortUrl + "/unlock", pathParams: shortUrlParams,
+			body: "password=wrong", header: map[string]string{"Content-Type": "application/x-www-form-urlencoded"}, status: http.StatusUnauthorized},
+		{name: "Describe", method: http.MethodGet, route: "/api/short/{short_url}/info", path: endpoint + "/" + shortUrl + "/info", pathParams: shortUrlParams, status: http.StatusOK},
+		{name: "Describe unknown", method: http.MethodGet, route: "/api/short/{short_url}/info", path: endpoint + "/unknown1/info",
+			pathParams: map[string]string{"short_url": "unknown1"}, status: http.StatusNotFound},
+		{name: "QR code", method: http.MethodGet, route: "/api/short/{short_url}/qr", path: endpoint + "/" + shortUrl + "/qr?format=svg", pathParams: shortUrlParams, status: http.StatusOK},
+		{name: "QR code invalid", method: http.MethodGet, route: "/api/short/{short_url}/qr", path: endpoint + "/" + shortUrl + "/qr?size=0", pathParams: shortUrlParams, status: http.StatusBadRequest},
+		{name: "Patch", method: http.MethodPatch, route: "/api/short/{short_url}", path: endpoint + "/" + shortUrl, pathParams: shortUrlParams, body: `{"title": "Spec"}`, status: http.StatusOK},
+		{name: "Patch changed", method: http.MethodPatch, route: "/api/short/{short_url}", path: endpoint + "/" + shortUrl, pathParams: shortUrlParams, body: `{"title": "Spec"}`,
+			header: map[string]string{"If-Match": `"1"`}, status: http.StatusPreconditionFailed},
+		{name: "Follow not modified", method: http.MethodGet, route: "/api/short/{short_url}", path: endpoint + "/" + shortUrl, pathParams: shortUrlParams,
+			header: map[string]string{controller.HeaderLinkPassword: "secret", "If-None-Match": "*"}, status: http.StatusNotModified},
+		{name: "Register domain without key", method: http.MethodPost, route: "/api/admin/domains", path: "/api/admin/domains", body: `{"name": "sho.rt"}`, status: http.StatusUnauthorized},
+		{name: "Register domain", method: http.MethodPost, route: "/api/admin/domains", path: "/api/admin/domains", body: `{"name": "sho.rt"}`, header: adminKey, status: http.StatusCreated},
+		{name: "List domains", method: http.MethodGet, route: "/api/admin/domains", path: "/api/admin/domains", header: adminKey, status: http.StatusOK},
+		{name: "Export", method: http.MethodGet, route: "/api/short/export", path: endpoint + "/export", header: adminKey, status: http.StatusOK},
+		{name: "Export CSV", method: http.MethodGet, route: "/api/short/export", path: endpoint + "/export?format=csv&tag=docs", header: adminKey, status: http.StatusOK},
+		{name: "Export invalid", method: http.MethodGet, route: "/api/short/export", path: endpoint + "/export?format=xml", header: adminKey, status: http.StatusBadRequest},
+		{name: "Import", method: http.MethodPost, route: "/api/short/import", path: endpoint + "/import?on_conflict=skip",
+			body:   `{"original_url": "https://example.net", "short_url": "spec-1"}` + "\n" + `{"original_url": "https://example.com"}`,
+			header: map[string]string{controller.HeaderAdminKey: "admin-key", "Content-Type": "application/x-ndjson"}, status: http.StatusOK},
+		{name: "Import invalid", method: http.MethodPost, route: "/api/short/import", path: endpoint + "/import?on_conflict=ignore", header: adminKey, status: http.StatusBadRequest},
+		{name: "Backup", method: http.MethodPost, route: "/api/admin/backups", path: "/api/admin/backups", header: adminKey, status: http.StatusCreated},
+		{name: "List backups", method: http.MethodGet, route: "/api/admin/backups", path: "/api/admin/backups", header: adminKey, status: http.StatusOK},
+		{name: "Delete domain", method: http.MethodDelete, route: "/api/admin/domains/{domain}", path: "/api/admin/domains/sho.rt", pathParams: map[string]string{"domain": "sho.rt"}, header: adminKey, status: http.StatusOK},
+		{name: "Report", method: http.MethodPost, route: "/api/short/{short_url}/report", path: endpoint + "/" + shortUrl + "/report", pathParams: shortUrlParams,
+			body: `{"reason": "spam", "details": "Sent in bulk"}`, status: http.StatusAccepted},
+		{name: "Report invalid", method: http.MethodPost, route: "/api/short/{short_url}/report", path: endpoint + "/" + shortUrl + "/report", pathParams: shortUrlParams,
+			body: `{"reason": "boring"}`, status: http.StatusBadRequest},
+		{name: "List reports", method: http.MethodGet, route: "/api/admin/reports", path: "/api/admin/reports?reviewed=false", header: adminKey, status: http.StatusOK},
+		{name: "Review report", method: http.MethodPost, route: "/api/admin/reports/{report_id}/review", path: "/api/admin/reports/1/review",
+			pathParams: map[string]string{"report_id": "1"}, header: adminKey, status: http.StatusOK},
+		{name: "Review unknown report", method: http.MethodPost, route: "/api/admin/reports/{report_id}/review", path: "/api/admin/reports/99/review",
+			pathParams: map[string]string{"report_id": "99"}, header: adminKey, status: http.StatusNotFound},
+		{name: "Disable", method: http.MethodPut, route: "/api/short/{short_url}/status", path: endpoint + "/" + shortUrl + "/status", pathParams: shortUrlParams,
+			body: `{"status": "disabled", "reason": "Reported as spam"}`, header: adminKey, status: http.StatusOK},
+		{name: "Follow disabled", method: http.MethodGet, route: "/api/short/{short_url}", path: endpoint + "/" + shortUrl, pathParams: shortUrlParams,
+			header: map[string]string{controller.HeaderLinkPassword: "secret"}, status: http.StatusUnavailableForLegalReasons},
+		{name: "List disabled", method: http.MethodGet, route: "/api/short", path: endpoint + "?status=disabled", status: http.StatusOK},
+		{name: "Reactivate", method: http.MethodPut, route: "/api/short/{short_url}/status", path: endpoint + "/" + shortUrl + "/status", pathParams: shortUrlParams,
+			body: `{"status": "active"}`, header: adminKey, status: http.StatusOK},
+		{name: "Status invalid", method: http.MethodPut, route: "/api/short/{short_url}/status", path: endpoint + "/" + shortUrl + "/status", pathParams: shortUrlParams,
+			body: `{"status": "paused"}`, header: adminKey, status: http.StatusBadRequest},
+		{name: "Create webhook", method: http.MethodPost, route: "/api/admin/webhooks", path: "/api/admin/webhooks",
+			body: `{"url": "https://hooks.example.com", "events": ["link.created", "link.click_threshold"], "click_thresholds": [100]}`, header: adminKey, status: http.StatusCreated},
+		{name: "Create webhook invalid", method: http.MethodPost, route: "/api/admin/webhooks", path: "/api/admin/webhooks",
+			body: `{"url": "https://hooks.example.com", "events": ["link.visited"]}`, header: adminKey, status: http.StatusBadRequest},
+		{name: "List webhooks", method: http.MethodGet, route: "/api/admin/webhooks", path: "/api/admin/webhooks", header: adminKey, status: http.StatusOK},
+		{name: "List deliveries", method: http.MethodGet, route: "/api/admin/webhooks/{webhook_id}/deliveries", path: "/api/admin/webhooks/1/deliveries?status=pending",
+			pathParams: map[string]string{"webhook_id": "1"}, header: adminKey, status: http.StatusOK},
+		{name: "List dead letters", method: http.MethodGet, route: "/api/admin/webhooks/dead-letters", path: "/api/admin/webhooks/dead-letters", header: adminKey, status: http.StatusOK},
+		{name: "Retry unknown dead letter", method: http.MethodPost, route: "/api/admin/webhooks/dead-letters/{dead_letter_id}/retry", path: "/api/admin/webhooks/dead-letters/99/retry",
+			pathParams: map[string]string{"dead_letter_id": "99"}, header: adminKey, status: http.StatusNotFound},
+		{name: "Delete webhook", method: http.MethodDelete, route: "/api/admin/webhooks/{webhook_id}", path: "/api/admin/webhooks/1",
+			pathParams: map[string]string{"webhook_id": "1"}, header: adminKey, status: http.StatusOK},
+		{name: "Update", method: http.MethodPut, route: "/api/short/{short_url}", path: endpoint + "/" + shortUrl, pathParams: shortUrlParams, status: http.StatusCreated},
+		{name: "Delete unknown", method: http.MethodDelete, route: "/api/short/{short_url}", path: endpoint + "/" + shortUrl, pathParams: shortUrlParams, status: http.StatusNotFound},
+	}
+	for _, call := range calls {
+		t.Run(call.name, func(t *testing.T) {
+			req := httptest.NewRequest(call.method, call.path, strings.NewReader(call.body))
+			for key, value := range call.header {
+				req.Header.Set(key, value)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			res := w.Result()
+			require.Equal(t, call.status, res.StatusCode, fmt.Sprintf("%s %s", call.method, call.path))
+			validateResponse(t, doc, call, req, res)
+		})
+	}
+}
+
+func TestGeneratedClient(t *testing.T) {
+	router, store := SetupTestDB(t)
+	defer store.Close()
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	apiClient, err := client.NewClientWithResponses(server.URL)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	title := "Example"
+	idempotencyKey := "client-1"
+	createParams := &client.CreateShortUrlParams{IdempotencyKey: &idempotencyKey}
+	createBody := client.CreateShortUrlJSONRequestBody{OriginalUrl: "https://example.com", Title: &title}
+	created, err := apiClient.CreateShortUrlWithResponse(ctx, createParams, createBody)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, created.StatusCode())
+	require.Equal(t, "https://example.com", *created.JSON201.OriginalUrl)
+
+	// A retry with the same key gets the same url instead of a conflict
+	retried, err := apiClient.CreateShortUrlWithResponse(ctx, createParams, createBody)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, retried.StatusCode())
+	require.Equal(t, "true", retried.HTTPResponse.Header.Get("Idempotent-Replayed"))
+	require.Equal(t, created.JSON201.ShortUrl, retried.JSON201.ShortUrl)
+
+	followed, err := apiClient.GetShortUrlWithResponse(ctx, created.JSON201.ShortUrl, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, followed.StatusCode())
+	require.Equal(t, "Example", *followed.JSON200.Title)
+	require.Equal(t, 1, followed.JSON200.ClickCount)
+
+	require.True(t, strings.HasPrefix(followed.HTTPResponse.Header.Get("ETag"), `"1-`))
+
+	// Describing the url counts no click
+	described, err := apiClient.DescribeShortUrlWithResponse(ctx, created.JSON201.ShortUrl)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, described.StatusCode())
+	require.Equal(t, 1, described.JSON200.ClickCount)
+
+	stale := `"2"`
+	changed, err := apiClient.DeleteShortUrlWithResponse(ctx, created.JSON201.ShortUrl, &client.DeleteShortUrlParams{IfMatch: &stale})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusPreconditionFailed, changed.StatusCode())
+	require.Equal(t, storage.ErrVersionMismatch, changed.JSON412.Error)
+
+	missing, err := apiClient.DeleteShortUrlWithResponse(ctx, "unknown1", nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, missing.StatusCode())
+	require.Equal(t, storage.ErrShortURLDoesNotExist, missing.JSON404.Error)
+}
@@ -0,0 +1,334 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"URL_SHORTENER/models"
+)
+
+const userAgent = "URL_SHORTENER-webhooks/1.0"
+
+// Events a webhook can subscribe to
+const (
+	EventLinkCreated        = "link.created"
+	EventLinkUpdated        = "link.updated"
+	EventLinkDeleted        = "link.deleted"
+	EventLinkClickThreshold = "link.click_threshold"
+	// EventLinkBroken is sent when the health check finds the destination of a link broken
+	EventLinkBroken = "link.broken"
+)
+
+// Events lists every event a webhook can subscribe to
+var Events = []string{EventLinkCreated, EventLinkUpdated, EventLinkDeleted, EventLinkClickThreshold, EventLinkBroken}
+
+type Config struct {
+	// Timeout bounds a single delivery attempt
+	Timeout time.Duration
+	// MaxAttempts is how often a delivery is tried before it goes to the dead letters
+	MaxAttempts int
+	// RetryBackoff is the wait before the first retry, it doubles on each further one up to MaxBackoff
+	RetryBackoff time.Duration
+	MaxBackoff   time.Duration
+	// PollInterval is how often the queue is checked for retries that became due
+	PollInterval time.Duration
+	// BatchSize is how many due deliveries are read from the queue at once
+	BatchSize int
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Timeout:      10 * time.Second,
+		MaxAttempts:  8,
+		RetryBackoff: 10 * time.Second,
+		MaxBackoff:   time.Hour,
+		PollInterval: 5 * time.Second,
+		BatchSize:    50,
+	}
+}
+
+// DeliveryStore is where the subscriptions are read and the deliveries are queued
+type DeliveryStore interface {
+	ListWebhooks() ([]models.Webhook, error)
+	InsertDeliveries(deliveries []models.WebhookDelivery) error
+	DueDeliveries(now time.Time, limit int) ([]models.WebhookDelivery, error)
+	FinishDelivery(delivery *models.WebhookDelivery) error
+}
+
+// Event is something that happened to a link
+type Event struct {
+	Type string
+	// Link is sent as the link of the payload
+	Link interface{}
+	// Clicks is the click count the link reached, for click threshold events
+	Clicks int
+}
+
+// Payload is the JSON body of a delivery
+type Payload struct {
+	// Id is the same for every webhook the event is delivered to
+	Id        string      `json:"id"`
+	Event     string      `json:"event"`
+	CreatedAt string      `json:"created_at"`
+	Link      interface{} `json:"link"`
+	// Threshold is the click count reached, for click threshold events
+	Threshold int `json:"threshold,omitempty"`
+}
+
+// Dispatcher queues the events of the subscribed webhooks in the database and delivers them
+// in the background, retrying failed deliveries with exponential backoff
+type Dispatcher struct {
+	store  DeliveryStore
+	config Config
+	client *http.Client
+
+	mu       sync.Mutex
+	webhooks []models.Webhook // nil until loaded, cleared when the subscriptions change
+
+	wake     chan struct{}
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+func NewDispatcher(store DeliveryStore, config Config) *Dispatcher {
+	return &Dispatcher{
+		store:  store,
+		config: config,
+		client: &http.Client{
+			Timeout: config.Timeout,
+			// A redirect would send the signed payload somewhere the subscriber didn't name
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		wake: make(chan struct{}, 1),
+		stop: make(chan struct{}),
+	}
+}
+
+// Start launches the delivery worker, it picks up the deliveries left pending by a previous run
+func (d *Dispatcher) Start() {
+	d.wg.Add(1)
+	go d.worker()
+}
+
+// Stop makes the worker finish the delivery it is on and waits for it.
+// Pending deliveries stay queued for the next run.
+func (d *Dispatcher) Stop() {
+	d.stopOnce.Do(func() {
+		close(d.stop)
+	})
+	d.wg.Wait()
+}
+
+// SubscriptionsChanged makes the next event read the webhooks again
+func (d *Dispatcher) SubscriptionsChanged() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.webhooks = nil
+}
+
+// Publish queues the event for every webhook subscribed to it. Delivery happens in the background.
+func (d *Dispatcher) Publish(event Event) {
+	webhooks, err := d.subscriptions()
+	if err != nil {
+		log.Printf("failed to read the webhooks, dropping %s event: %v", event.Type, err)
+		return
+	}
+	var payload []byte
+	var deliveries []models.WebhookDelivery
+	now := time.Now()
+	for _, webhook := range webhooks {
+		if !subscribed(&webhook, &event) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = newPayload(&event, now); err != nil {
+				log.Printf("failed to encode %s event: %v", event.Type, err)
+				return
+			}
+		}
+		deliveries = append(deliveries, models.WebhookDelivery{
+			WebhookId:     webhook.Id,
+			Event:         event.Type,
+			Payload:       payload,
+			Status:        models.DeliveryPending,
+			CreatedAt:     models.FormatTimestamp(now),
+			NextAttemptAt: now,
+		})
+	}
+	if len(deliveries) == 0 {
+		return
+	}
+	if err = d.store.InsertDeliveries(deliveries); err != nil {
+		log.Printf("failed to queue %s event: %v", event.Type, err)
+		return
+	}
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (d *Dispatcher) subscriptions() ([]models.Webhook, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.webhooks == nil {
+		webhooks, err := d.store.ListWebhooks()
+		if err != nil {
+			return nil, err
+		}
+		d.webhooks = webhooks
+	}
+	return d.webhooks, nil
+}
+
+// subscribed reports whether the webhook wants the event. Click threshold events
+// are only sent for the click counts the webhook named.
+func subscribed(webhook *models.Webhook, event *Event) bool {
+	for _, name := range webhook.Events {
+		if name != event.Type {
+			continue
+		}
+		if event.Type != EventLinkClickThreshold {
+			return true
+		}
+		for _, threshold := range webhook.ClickThresholds {
+			if threshold == event.Clicks {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func newPayload(event *Event, now time.Time) ([]byte, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	payload := Payload{
+		Id:        hex.EncodeToString(id),
+		Event:     event.Type,
+		CreatedAt: models.FormatTimestamp(now),
+		Link:      event.Link,
+	}
+	if event.Type == EventLinkClickThreshold {
+		payload.Threshold = event.Clicks
+	}
+	return json.Marshal(payload)
+}
+
+func (d *Dispatcher) worker() {
+	defer d.wg.Done()
+	poll := time.NewTicker(d.config.PollInterval)
+	defer poll.Stop()
+	for {
+		d.deliverDue()
+		select {
+		case <-d.stop:
+			return
+		case <-d.wake:
+		case <-poll.C:
+		}
+	}
+}
+
+// deliverDue sends the due deliveries until none is left
+func (d *Dispatcher) deliverDue() {
+	for {
+		deliveries, err := d.store.DueDeliveries(time.Now(), d.config.BatchSize)
+		if err != nil {
+			log.Printf("failed to read the webhook deliveries: %v", err)
+			return
+		}
+		if len(deliveries) == 0 {
+			return
+		}
+		for i := range deliveries {
+			select {
+			case <-d.stop:
+				return
+			default:
+			}
+			// The delivery would come right back if its outcome wasn't stored, so wait for the next poll
+			if err = d.attempt(&deliveries[i]); err != nil {
+				log.Printf("failed to store webhook delivery %d: %v", deliveries[i].Id, err)
+				return
+			}
+		}
+	}
+}
+
+// attempt sends the delivery once and stores the outcome, scheduling a retry if it failed
+func (d *Dispatcher) attempt(delivery *models.WebhookDelivery) error {
+	delivery.Attempts++
+	delivery.ResponseStatus, delivery.LastError = 0, ""
+	status, err := d.send(delivery)
+	delivery.ResponseStatus = status
+	now := time.Now()
+	switch {
+	case err == nil:
+		delivery.Status = models.DeliveryDelivered
+		delivery.DeliveredAt = models.FormatTimestamp(now)
+	case delivery.Attempts >= d.config.MaxAttempts:
+		delivery.Status = models.DeliveryFailed
+		delivery.LastError = err.Error()
+	default:
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = now.Add(d.backoff(delivery.Attempts))
+	}
+	return d.store.FinishDelivery(delivery)
+}
+
+// backoff is the wait after the given number of failed attempts
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	backoff := d.config.RetryBackoff
+	for i := 1; i < attempts && backoff < d.config.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > d.config.MaxBackoff {
+		backoff = d.config.MaxBackoff
+	}
+	return backoff
+}
+
+// send posts the signed payload, any 2xx answer counts as delivered
+func (d *Dispatcher) send(delivery *models.WebhookDelivery) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.config.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.Id, 10))
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(delivery.Secret, timestamp, delivery.Payload))
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	// Drain a little of the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("receiver answered %s", res.Status)
+	}
+	return res.StatusCode, nil
+}
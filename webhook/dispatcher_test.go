@@ -0,0 +1,210 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"URL_SHORTENER/models"
+	"URL_SHORTENER/storage"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "0123456789abcdef"
+
+// received is a delivery as the receiver saw it
+type received struct {
+	header http.Header
+	body   []byte
+}
+
+// receiver answers the first failures deliveries with 500 and records the rest
+type receiver struct {
+	*httptest.Server
+	failures  int32
+	requests  int32
+	mu        sync.Mutex
+	delivered chan received
+}
+
+func newReceiver(t *testing.T, failures int32) *receiver {
+	rec := &receiver{failures: failures, delivered: make(chan received, 10)}
+	rec.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&rec.requests, 1) <= rec.failures {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		rec.delivered <- received{header: r.Header.Clone(), body: body}
+	}))
+	t.Cleanup(rec.Close)
+	return rec
+}
+
+func (rec *receiver) next(t *testing.T) received {
+	select {
+	case delivery := <-rec.delivered:
+		return delivery
+	case <-time.After(5 * time.Second):
+		t.Fatal("no delivery received")
+		return received{}
+	}
+}
+
+func newTestStore(t *testing.T) *storage.URLStore {
+	t.Setenv("DB_PATH", ":memory:")
+	store, err := storage.NewURLStore()
+	require.NoError(t, err)
+	t.Cleanup(store.Close)
+	return store
+}
+
+func testConfig() Config {
+	config := DefaultConfig()
+	config.Timeout = 2 * time.Second
+	config.MaxAttempts = 3
+	config.RetryBackoff = time.Millisecond
+	config.MaxBackoff = 10 * time.Millisecond
+	config.PollInterval = 5 * time.Millisecond
+	return config
+}
+
+func addWebhook(t *testing.T, store *storage.URLStore, url string, events []string, thresholds []int) *models.Webhook {
+	webhook := &models.Webhook{Url: url, Events: events, Secret: testSecret, ClickThresholds: thresholds, CreatedAt: "2024-10-16T21:05:18Z"}
+	require.NoError(t, store.InsertWebhook(webhook))
+	return webhook
+}
+
+func TestSignAndVerify(t *testing.T) {
+	body := []byte(`{"event": "link.created"}`)
+	now := time.Unix(1729119918, 0)
+	signature := Sign(testSecret, "1729119918", body)
+	require.Regexp(t, "^sha256=[0-9a-f]{64}$", signature)
+
+	require.True(t, Verify(testSecret, "1729119918", signature, body, now.Add(time.Minute), 5*time.Minute))
+	require.False(t, Verify("another secret", "1729119918", signature, body, now, 5*time.Minute))
+	require.False(t, Verify(testSecret, "1729119918", signature, []byte(`{"event": "link.deleted"}`), now, 5*time.Minute))
+	require.False(t, Verify(testSecret, "1729119918", signature, body, now.Add(10*time.Minute), 5*time.Minute))
+	require.False(t, Verify(testSecret, "yesterday", signature, body, now, 5*time.Minute))
+}
+
+func TestSubscribed(t *testing.T) {
+	webhook := &models.Webhook{Events: []string{EventLinkCreated, EventLinkClickThreshold}, ClickThresholds: []int{10, 100}}
+	require.True(t, subscribed(webhook, &Event{Type: EventLinkCreated}))
+	require.False(t, subscribed(webhook, &Event{Type: EventLinkDeleted}))
+	require.True(t, subscribed(webhook, &Event{Type: EventLinkClickThreshold, Clicks: 100}))
+	require.False(t, subscribed(webhook, &Event{Type: EventLinkClickThreshold, Clicks: 99}))
+}
+
+func TestBackoff(t *testing.T) {
+	dispatcher := NewDispatcher(nil, Config{RetryBackoff: 10 * time.Second, MaxBackoff: time.Minute})
+	require.Equal(t, 10*time.Second, dispatcher.backoff(1))
+	require.Equal(t, 20*time.Second, dispatcher.backoff(2))
+	require.Equal(t, 40*time.Second, dispatcher.backoff(3))
+	require.Equal(t, time.Minute, dispatcher.backoff(4))
+	require.Equal(t, time.Minute, dispatcher.backoff(30))
+}
+
+func TestDispatcher(t *testing.T) {
+	t.Run("Signed delivery", func(t *testing.T) {
+		store := newTestStore(t)
+		rec := newReceiver(t, 0)
+		webhook := addWebhook(t, store, rec.URL, []string{EventLinkCreated}, nil)
+		dispatcher := NewDispatcher(store, testConfig())
+		dispatcher.Start()
+		defer dispatcher.Stop()
+
+		dispatcher.Publish(Event{Type: EventLinkDeleted, Link: map[string]string{"short_url": "esd87df7"}})
+		dispatcher.Publish(Event{Type: EventLinkCreated, Link: map[string]string{"short_url": "esd87df7"}})
+		delivery := rec.next(t)
+		require.Equal(t, EventLinkCreated, delivery.header.Get(HeaderEvent))
+		require.Equal(t, "application/json", delivery.header.Get("Content-Type"))
+		require.True(t, Verify(testSecret, delivery.header.Get(HeaderTimestamp), delivery.header.Get(HeaderSignature),
+			delivery.body, time.Now(), time.Minute))
+
+		var payload Payload
+		require.NoError(t, json.Unmarshal(delivery.body, &payload))
+		require.Equal(t, EventLinkCreated, payload.Event)
+		require.Len(t, payload.Id, 32)
+		require.Equal(t, map[string]interface{}{"short_url": "esd87df7"}, payload.Link)
+
+		require.Eventually(t, func() bool {
+			deliveries, err := store.ListDeliveries(&storage.DeliveryFilter{WebhookId: webhook.Id, Limit: 10})
+			require.NoError(t, err)
+			return len(deliveries) == 1 && deliveries[0].Status == models.DeliveryDelivered
+		}, 5*time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("Failed attempts are retried", func(t *testing.T) {
+		store := newTestStore(t)
+		rec := newReceiver(t, 2)
+		webhook := addWebhook(t, store, rec.URL, []string{EventLinkClickThreshold}, []int{100})
+		dispatcher := NewDispatcher(store, testConfig())
+		dispatcher.Start()
+		defer dispatcher.Stop()
+
+		dispatcher.Publish(Event{Type: EventLinkClickThreshold, Clicks: 99})
+		dispatcher.Publish(Event{Type: EventLinkClickThreshold, Clicks: 100})
+		var payload Payload
+		require.NoError(t, json.Unmarshal(rec.next(t).body, &payload))
+		require.Equal(t, 100, payload.Threshold)
+
+		require.Eventually(t, func() bool {
+			deliveries, err := store.ListDeliveries(&storage.DeliveryFilter{WebhookId: webhook.Id, Status: models.DeliveryDelivered, Limit: 10})
+			require.NoError(t, err)
+			return len(deliveries) == 1 && deliveries[0].Attempts == 3 && deliveries[0].ResponseStatus == http.StatusOK
+		}, 5*time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("Deliveries failing every attempt are dead letters", func(t *testing.T) {
+		store := newTestStore(t)
+		rec := newReceiver(t, 3)
+		webhook := addWebhook(t, store, rec.URL, []string{EventLinkUpdated}, nil)
+		dispatcher := NewDispatcher(store, testConfig())
+		dispatcher.Start()
+		defer dispatcher.Stop()
+
+		dispatcher.Publish(Event{Type: EventLinkUpdated})
+		var deadLetters []models.WebhookDeadLetter
+		require.Eventually(t, func() bool {
+			var err error
+			deadLetters, err = store.ListDeadLetters(10, 0)
+			require.NoError(t, err)
+			return len(deadLetters) == 1
+		}, 5*time.Second, 5*time.Millisecond)
+		require.Equal(t, webhook.Id, deadLetters[0].WebhookId)
+		require.Equal(t, 3, deadLetters[0].Attempts)
+		require.Equal(t, "receiver answered 500 Internal Server Error", deadLetters[0].LastError)
+
+		deliveries, err := store.ListDeliveries(&storage.DeliveryFilter{WebhookId: webhook.Id, Limit: 10})
+		require.NoError(t, err)
+		require.Equal(t, models.DeliveryFailed, deliveries[0].Status)
+
+		// A retried dead letter is delivered once the receiver is back
+		require.NoError(t, store.RetryDeadLetter(deadLetters[0].Id, time.Now()))
+		rec.next(t)
+		deadLetters, err = store.ListDeadLetters(10, 0)
+		require.NoError(t, err)
+		require.Empty(t, deadLetters)
+	})
+
+	t.Run("Pending deliveries survive a restart", func(t *testing.T) {
+		store := newTestStore(t)
+		rec := newReceiver(t, 0)
+		addWebhook(t, store, rec.URL, []string{EventLinkCreated}, nil)
+
+		// Events published while the worker isn't running stay queued
+		NewDispatcher(store, testConfig()).Publish(Event{Type: EventLinkCreated})
+		dispatcher := NewDispatcher(store, testConfig())
+		dispatcher.Start()
+		defer dispatcher.Stop()
+		rec.next(t)
+	})
+}